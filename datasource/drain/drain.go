@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package drain persists the instance drains in the datasource of service center
+package drain
+
+import (
+	"context"
+
+	"github.com/go-chassis/cari/discovery"
+)
+
+var ErrDrainNotExists = discovery.NewError(discovery.ErrInstanceNotExists, "instance drain not exist")
+
+// Event is a step of the instance draining, the timestamp is in unix seconds
+type Event struct {
+	Step      string `json:"step"`
+	Message   string `json:"message,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// Drain is the draining of an instance, it is kept for a while after the
+// instance is unregistered to query the events, the times are in unix seconds
+type Drain struct {
+	Domain     string   `json:"domain"`
+	Project    string   `json:"project"`
+	ServiceID  string   `json:"serviceId"`
+	InstanceID string   `json:"instanceId"`
+	StartTime  int64    `json:"startTime"`
+	Deadline   int64    `json:"deadline"`
+	FinishTime int64    `json:"finishTime,omitempty"`
+	Events     []*Event `json:"events"`
+}
+
+// DAO manages the drains of the domain project in context
+type DAO interface {
+	// PutDrain creates or replaces the drain
+	PutDrain(ctx context.Context, d *Drain) error
+	GetDrain(ctx context.Context, serviceID, instanceID string) (*Drain, error)
+	// ListAllDrains returns the drains of all the domain projects
+	ListAllDrains(ctx context.Context) ([]*Drain, error)
+	DeleteDrain(ctx context.Context, serviceID, instanceID string) error
+}
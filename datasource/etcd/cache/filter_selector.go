@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/cache"
+)
+
+// SelectorFilter filters the instances by the selector in context, the
+// selector is folded into the revision of parent, so the result is changed
+// if either the whole instances or the selector is changed.
+// It must be the last filter, because ConsistencyFilter gets the
+// instances from the backend without the selector.
+type SelectorFilter struct {
+}
+
+func (f *SelectorFilter) Name(ctx context.Context, _ *cache.Node) string {
+	return datasource.GetInstanceSelector(ctx).String()
+}
+
+func (f *SelectorFilter) Init(ctx context.Context, parent *cache.Node) (node *cache.Node, err error) {
+	s := datasource.GetInstanceSelector(ctx)
+	if s.Empty() {
+		node = cache.NewNode()
+		node.Cache = parent.Cache
+		return
+	}
+
+	pCache := parent.Cache.Get(FindResult).(*VersionRuleCacheItem)
+	node = cache.NewNode()
+	node.Cache.Set(FindResult, &VersionRuleCacheItem{
+		ServiceIds: pCache.ServiceIds,
+		Instances:  datasource.FilterInstances(s, pCache.Instances),
+		Rev:        datasource.SelectorRevision(s, pCache.Rev),
+	})
+	return
+}
@@ -23,6 +23,7 @@ import (
 
 	pb "github.com/go-chassis/cari/discovery"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/cache"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 )
@@ -38,6 +39,7 @@ func init() {
 		&AccessibleFilter{},
 		&InstancesFilter{},
 		&ConsistencyFilter{},
+		&SelectorFilter{},
 	)
 }
 
@@ -78,7 +80,8 @@ func (f *FindInstancesCache) Get(ctx context.Context, consumer *pb.MicroService,
 		CtxConsumerID, consumer),
 		CtxProviderKey, provider),
 		CtxTags, tags),
-		CtxRequestRev, rev)
+		// compare the instances revision only, the selector revision is folded by SelectorFilter
+		CtxRequestRev, datasource.InstancesRevision(rev))
 
 	node, err := f.Tree.Get(cloneCtx, cache.Options().Temporary(ctx.Value(util.CtxNocache) == "1"))
 	if node == nil {
@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/little-cui/etcdadpt"
+
+	"github.com/apache/servicecomb-service-center/datasource/drain"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+func init() {
+	drain.Install("etcd", NewDrainDAO)
+	drain.Install("embeded_etcd", NewDrainDAO)
+	drain.Install("embedded_etcd", NewDrainDAO)
+}
+
+func NewDrainDAO(_ drain.Options) (drain.DAO, error) {
+	return &DrainDAO{}, nil
+}
+
+type DrainDAO struct{}
+
+func (dao *DrainDAO) PutDrain(ctx context.Context, d *drain.Drain) error {
+	value, err := json.Marshal(d)
+	if err != nil {
+		log.Error("instance drain is invalid", err)
+		return err
+	}
+	err = etcdadpt.PutBytes(ctx, path.GenerateDrainKey(util.ParseDomainProject(ctx), d.ServiceID, d.InstanceID), value)
+	if err != nil {
+		log.Error("can not save instance drain "+d.InstanceID, err)
+		return err
+	}
+	return nil
+}
+
+func (dao *DrainDAO) GetDrain(ctx context.Context, serviceID, instanceID string) (*drain.Drain, error) {
+	kv, err := etcdadpt.Get(ctx, path.GenerateDrainKey(util.ParseDomainProject(ctx), serviceID, instanceID))
+	if err != nil {
+		return nil, err
+	}
+	if kv == nil {
+		return nil, drain.ErrDrainNotExists
+	}
+	d := &drain.Drain{}
+	if err = json.Unmarshal(kv.Value, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (dao *DrainDAO) ListAllDrains(ctx context.Context) ([]*drain.Drain, error) {
+	kvs, n, err := etcdadpt.List(ctx, path.GetDrainRootKey()+path.SPLIT)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*drain.Drain, 0, n)
+	for _, kv := range kvs {
+		d := &drain.Drain{}
+		if err := json.Unmarshal(kv.Value, d); err != nil {
+			log.Error("instance drain format invalid", err)
+			continue
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+func (dao *DrainDAO) DeleteDrain(ctx context.Context, serviceID, instanceID string) error {
+	_, err := etcdadpt.Delete(ctx, path.GenerateDrainKey(util.ParseDomainProject(ctx), serviceID, instanceID))
+	if err != nil {
+		log.Error("can not delete instance drain "+instanceID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/little-cui/etcdadpt"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	govds "github.com/apache/servicecomb-service-center/datasource/gov"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+func init() {
+	govds.Install("etcd", NewGovDAO)
+	govds.Install("embeded_etcd", NewGovDAO)
+	govds.Install("embedded_etcd", NewGovDAO)
+}
+
+func NewGovDAO(_ govds.Options) (govds.DAO, error) {
+	return &GovDAO{}, nil
+}
+
+// GovDAO uses the mod revision of the keys as the policy revision,
+// the revision key of the domain project is put in the same txn with every change.
+type GovDAO struct{}
+
+func (dao *GovDAO) CreatePolicy(ctx context.Context, p *gov.Policy) error {
+	return dao.putPolicy(ctx, p)
+}
+
+func (dao *GovDAO) UpdatePolicy(ctx context.Context, p *gov.Policy) error {
+	key := path.GenerateGovPolicyKey(util.ParseDomainProject(ctx), p.ID)
+	return dao.putPolicy(ctx, p, etcdadpt.ExistKey(key))
+}
+
+// putPolicy saves the policy if the cmps succeed, otherwise returns ErrPolicyNotExists
+func (dao *GovDAO) putPolicy(ctx context.Context, p *gov.Policy, cmps ...etcdadpt.CmpOptions) error {
+	domainProject := util.ParseDomainProject(ctx)
+	p.Revision = 0
+	value, err := json.Marshal(p)
+	if err != nil {
+		log.Error("policy is invalid", err)
+		return err
+	}
+	key := path.GenerateGovPolicyKey(domainProject, p.ID)
+	resp, err := etcdadpt.TxnWithCmp(ctx, []etcdadpt.OpOptions{
+		etcdadpt.OpPut(etcdadpt.WithStrKey(key), etcdadpt.WithValue(value)),
+		etcdadpt.OpPut(etcdadpt.WithStrKey(path.GenerateGovRevisionKey(domainProject)), etcdadpt.WithStrValue(p.ID)),
+	}, cmps, nil)
+	if err != nil {
+		log.Error("can not save policy "+p.ID, err)
+		return err
+	}
+	if !resp.Succeeded {
+		return govds.ErrPolicyNotExists
+	}
+	kv, err := etcdadpt.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if kv != nil {
+		p.Revision = kv.ModRevision
+	}
+	return nil
+}
+
+func (dao *GovDAO) DeletePolicies(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	domainProject := util.ParseDomainProject(ctx)
+	opts := make([]etcdadpt.OpOptions, 0, len(ids)+1)
+	for _, id := range ids {
+		opts = append(opts, etcdadpt.OpDel(etcdadpt.WithStrKey(path.GenerateGovPolicyKey(domainProject, id))))
+	}
+	opts = append(opts, etcdadpt.OpPut(etcdadpt.WithStrKey(path.GenerateGovRevisionKey(domainProject)),
+		etcdadpt.WithStrValue(ids[0])))
+	err := etcdadpt.Txn(ctx, opts)
+	if err != nil {
+		log.Error("can not delete policies", err)
+		return err
+	}
+	return nil
+}
+
+func (dao *GovDAO) GetPolicy(ctx context.Context, id string) (*gov.Policy, error) {
+	kv, err := etcdadpt.Get(ctx, path.GenerateGovPolicyKey(util.ParseDomainProject(ctx), id))
+	if err != nil {
+		return nil, err
+	}
+	if kv == nil {
+		return nil, govds.ErrPolicyNotExists
+	}
+	return toPolicy(kv)
+}
+
+func (dao *GovDAO) ListPolicies(ctx context.Context, kind string) ([]*gov.Policy, error) {
+	kvs, n, err := etcdadpt.List(ctx, path.GenerateGovPolicyKey(util.ParseDomainProject(ctx), ""))
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]*gov.Policy, 0, n)
+	for _, kv := range kvs {
+		p, err := toPolicy(kv)
+		if err != nil {
+			log.Error("policy format invalid", err)
+			continue // do not fail if some policy is invalid
+		}
+		if kind != "" && p.Kind != kind {
+			continue
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (dao *GovDAO) GetRevision(ctx context.Context) (int64, error) {
+	kv, err := etcdadpt.Get(ctx, path.GenerateGovRevisionKey(util.ParseDomainProject(ctx)))
+	if err != nil {
+		return 0, err
+	}
+	if kv == nil {
+		return 0, nil
+	}
+	return kv.ModRevision, nil
+}
+
+func (dao *GovDAO) AddHistory(ctx context.Context, h *gov.History) error {
+	domainProject := util.ParseDomainProject(ctx)
+	for {
+		list, err := dao.ListHistory(ctx, h.PolicyID)
+		if err != nil {
+			return err
+		}
+		h.Version = 1
+		if len(list) > 0 {
+			h.Version = list[len(list)-1].Version + 1
+		}
+		value, err := json.Marshal(h)
+		if err != nil {
+			log.Error("policy history is invalid", err)
+			return err
+		}
+		// the version may be taken by the concurrent change, retry with the next one
+		ok, err := etcdadpt.InsertBytes(ctx,
+			path.GenerateGovHistoryKey(domainProject, h.PolicyID, strconv.FormatInt(h.Version, 10)), value)
+		if err != nil {
+			log.Error("can not save policy history "+h.PolicyID, err)
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+}
+
+func (dao *GovDAO) ListHistory(ctx context.Context, policyID string) ([]*gov.History, error) {
+	kvs, n, err := etcdadpt.List(ctx, path.GenerateGovHistoryKey(util.ParseDomainProject(ctx), policyID, ""))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*gov.History, 0, n)
+	for _, kv := range kvs {
+		h := &gov.History{}
+		if err := json.Unmarshal(kv.Value, h); err != nil {
+			log.Error("policy history format invalid", err)
+			continue
+		}
+		list = append(list, h)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+	return list, nil
+}
+
+func (dao *GovDAO) GetHistory(ctx context.Context, policyID string, version int64) (*gov.History, error) {
+	kv, err := etcdadpt.Get(ctx, path.GenerateGovHistoryKey(util.ParseDomainProject(ctx), policyID,
+		strconv.FormatInt(version, 10)))
+	if err != nil {
+		return nil, err
+	}
+	if kv == nil {
+		return nil, govds.ErrHistoryNotExists
+	}
+	h := &gov.History{}
+	if err = json.Unmarshal(kv.Value, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (dao *GovDAO) PutTemplate(ctx context.Context, t *gov.Template) error {
+	value, err := json.Marshal(t)
+	if err != nil {
+		log.Error("policy template is invalid", err)
+		return err
+	}
+	err = etcdadpt.PutBytes(ctx, path.GenerateGovTemplateKey(util.ParseDomainProject(ctx), t.ID), value)
+	if err != nil {
+		log.Error("can not save policy template "+t.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (dao *GovDAO) UpdateTemplate(ctx context.Context, t *gov.Template, revision int64) error {
+	key := path.GenerateGovTemplateKey(util.ParseDomainProject(ctx), t.ID)
+	kv, err := etcdadpt.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if kv == nil {
+		return govds.ErrTemplateNotExists
+	}
+	old := &gov.Template{}
+	if err = json.Unmarshal(kv.Value, old); err != nil {
+		return err
+	}
+	if old.Revision != revision {
+		return govds.ErrTemplateConflict
+	}
+	value, err := json.Marshal(t)
+	if err != nil {
+		log.Error("policy template is invalid", err)
+		return err
+	}
+	resp, err := etcdadpt.TxnWithCmp(ctx,
+		etcdadpt.Ops(etcdadpt.OpPut(etcdadpt.WithStrKey(key), etcdadpt.WithValue(value))),
+		etcdadpt.If(etcdadpt.EqualModRev(key, kv.ModRevision)), nil)
+	if err != nil {
+		log.Error("can not update policy template "+t.ID, err)
+		return err
+	}
+	if !resp.Succeeded {
+		return govds.ErrTemplateConflict
+	}
+	return nil
+}
+
+func (dao *GovDAO) GetTemplate(ctx context.Context, id string) (*gov.Template, error) {
+	kv, err := etcdadpt.Get(ctx, path.GenerateGovTemplateKey(util.ParseDomainProject(ctx), id))
+	if err != nil {
+		return nil, err
+	}
+	if kv == nil {
+		return nil, govds.ErrTemplateNotExists
+	}
+	t := &gov.Template{}
+	if err = json.Unmarshal(kv.Value, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (dao *GovDAO) ListTemplates(ctx context.Context) ([]*gov.Template, error) {
+	kvs, n, err := etcdadpt.List(ctx, path.GenerateGovTemplateKey(util.ParseDomainProject(ctx), ""))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*gov.Template, 0, n)
+	for _, kv := range kvs {
+		t := &gov.Template{}
+		if err := json.Unmarshal(kv.Value, t); err != nil {
+			log.Error("policy template format invalid", err)
+			continue
+		}
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+func (dao *GovDAO) DeleteTemplate(ctx context.Context, id string) error {
+	domainProject := util.ParseDomainProject(ctx)
+	err := etcdadpt.Txn(ctx, []etcdadpt.OpOptions{
+		etcdadpt.OpDel(etcdadpt.WithStrKey(path.GenerateGovTemplateKey(domainProject, id))),
+		etcdadpt.OpDel(etcdadpt.WithStrKey(path.GenerateGovTemplateInstanceKey(domainProject, id, "")),
+			etcdadpt.WithPrefix()),
+	})
+	if err != nil {
+		log.Error("can not delete policy template "+id, err)
+		return err
+	}
+	return nil
+}
+
+func (dao *GovDAO) PutTemplateInstance(ctx context.Context, i *gov.TemplateInstance) error {
+	value, err := json.Marshal(i)
+	if err != nil {
+		log.Error("policy template instance is invalid", err)
+		return err
+	}
+	err = etcdadpt.PutBytes(ctx,
+		path.GenerateGovTemplateInstanceKey(util.ParseDomainProject(ctx), i.TemplateID, i.ID), value)
+	if err != nil {
+		log.Error("can not save policy template instance "+i.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (dao *GovDAO) ListTemplateInstances(ctx context.Context, templateID string) ([]*gov.TemplateInstance, error) {
+	kvs, n, err := etcdadpt.List(ctx,
+		path.GenerateGovTemplateInstanceKey(util.ParseDomainProject(ctx), templateID, ""))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*gov.TemplateInstance, 0, n)
+	for _, kv := range kvs {
+		i := &gov.TemplateInstance{}
+		if err := json.Unmarshal(kv.Value, i); err != nil {
+			log.Error("policy template instance format invalid", err)
+			continue
+		}
+		list = append(list, i)
+	}
+	return list, nil
+}
+
+func toPolicy(kv *mvccpb.KeyValue) (*gov.Policy, error) {
+	p := &gov.Policy{GovernancePolicy: &gov.GovernancePolicy{Selector: gov.Selector{}}}
+	if err := json.Unmarshal(kv.Value, p); err != nil {
+		return nil, err
+	}
+	p.Revision = kv.ModRevision
+	return p, nil
+}
@@ -889,6 +889,158 @@ func (ds *MetadataManager) UnregisterInstance(ctx context.Context, request *pb.U
 	return nil
 }
 
+func (ds *MetadataManager) RegisterManyInstances(ctx context.Context, serviceID string,
+	instances []*pb.MicroServiceInstance) error {
+	remoteIP := util.GetIPFromContext(ctx)
+	domainProject := util.ParseDomainProject(ctx)
+
+	var (
+		ops        []etcdadpt.OpOptions
+		leaseIDs   []int64
+		registered []*pb.MicroServiceInstance
+		existed    []*pb.MicroServiceInstance
+	)
+	// the granted leases are useless if the instances are not registered
+	revokeLeases := func() {
+		for _, leaseID := range leaseIDs {
+			if err := etcdadpt.Instance().LeaseRevoke(ctx, leaseID); err != nil && err != etcdadpt.ErrLeaseNotFound {
+				log.Warn(fmt.Sprintf("revoke lease %d failed: %s", leaseID, err))
+			}
+		}
+	}
+	for _, instance := range instances {
+		//允许自定义id
+		if len(instance.InstanceId) > 0 {
+			exist, err := eutil.ExistInstance(ctx, domainProject, serviceID, instance.InstanceId)
+			if err != nil {
+				revokeLeases()
+				return pb.NewError(pb.ErrUnavailableBackend, err.Error())
+			}
+			if exist {
+				// keep alive after the others are registered
+				existed = append(existed, instance)
+				continue
+			}
+		} else {
+			instance.InstanceId = uuid.Generator().GetInstanceID(ctx)
+		}
+
+		ttl := ds.calcInstanceTTL(instance)
+		data, err := json.Marshal(instance)
+		if err != nil {
+			log.Error(fmt.Sprintf("batch register service[%s] instances failed, instanceID %s, operator %s",
+				serviceID, instance.InstanceId, remoteIP), err)
+			revokeLeases()
+			return pb.NewError(pb.ErrInternal, err.Error())
+		}
+		leaseID, err := etcdadpt.Instance().LeaseGrant(ctx, ttl)
+		if err != nil {
+			log.Error(fmt.Sprintf("grant lease failed, service[%s] instance %s, operator: %s",
+				serviceID, instance.InstanceId, remoteIP), err)
+			revokeLeases()
+			return pb.NewError(pb.ErrUnavailableBackend, err.Error())
+		}
+		leaseIDs = append(leaseIDs, leaseID)
+		leaseOp := etcdadpt.WithLease(leaseID)
+		ops = append(ops,
+			etcdadpt.OpPut(etcdadpt.WithStrKey(path.GenerateInstanceKey(domainProject, serviceID, instance.InstanceId)),
+				etcdadpt.WithValue(data), leaseOp),
+			etcdadpt.OpPut(etcdadpt.WithStrKey(path.GenerateInstanceLeaseKey(domainProject, serviceID, instance.InstanceId)),
+				etcdadpt.WithStrValue(fmt.Sprintf("%d", leaseID)), leaseOp))
+		registered = append(registered, instance)
+	}
+
+	if len(ops) > 0 {
+		// call the client directly, the package level etcdadpt.TxnWithCmp splits the ops
+		// into several txns when they are more than etcdadpt.MaxTxnNumberOneTime,
+		// which breaks the atomicity, the ops of MaxBatchInstances are within the limit
+		resp, err := etcdadpt.Instance().TxnWithCmp(ctx, ops,
+			etcdadpt.If(etcdadpt.NotEqualVer(path.GenerateServiceKey(domainProject, serviceID), 0)), nil)
+		if err != nil {
+			log.Error(fmt.Sprintf("batch register service[%s] %d instances failed, operator %s",
+				serviceID, len(registered), remoteIP), err)
+			revokeLeases()
+			return pb.NewError(pb.ErrUnavailableBackend, err.Error())
+		}
+		if !resp.Succeeded {
+			log.Error(fmt.Sprintf("batch register service[%s] %d instances failed, operator %s: service does not exist",
+				serviceID, len(registered), remoteIP), nil)
+			revokeLeases()
+			return pb.NewError(pb.ErrServiceNotExists, "Service does not exist.")
+		}
+		for _, instance := range registered {
+			sendEvent(ctx, sync.CreateAction, datasource.ResourceInstance, &pb.RegisterInstanceRequest{Instance: instance})
+		}
+		log.Info(fmt.Sprintf("batch register service[%s] %d instances, operator %s", serviceID, len(registered), remoteIP))
+	}
+
+	for _, instance := range existed {
+		needRegister, err := ds.sendHeartbeatInstead(ctx, instance)
+		if err != nil {
+			return err
+		}
+		if needRegister {
+			log.Error(fmt.Sprintf("batch register service[%s] instances failed, instance %s is removed, operator %s",
+				serviceID, instance.InstanceId, remoteIP), nil)
+			return pb.NewError(pb.ErrInstanceNotExists, fmt.Sprintf("Instance[%s] is removed.", instance.InstanceId))
+		}
+	}
+	return nil
+}
+
+func (ds *MetadataManager) UnregisterManyInstances(ctx context.Context, serviceID string, instanceIDs []string) error {
+	remoteIP := util.GetIPFromContext(ctx)
+	domainProject := util.ParseDomainProject(ctx)
+
+	var (
+		ops      []etcdadpt.OpOptions
+		cmps     []etcdadpt.CmpOptions
+		leaseIDs []int64
+	)
+	for _, instanceID := range instanceIDs {
+		leaseID, err := serviceUtil.GetLeaseID(ctx, domainProject, serviceID, instanceID)
+		if err != nil {
+			return pb.NewError(pb.ErrUnavailableBackend, err.Error())
+		}
+		if leaseID == -1 {
+			return pb.NewError(pb.ErrInstanceNotExists, fmt.Sprintf("Instance[%s]'s leaseId not exist.", instanceID))
+		}
+		key := path.GenerateInstanceKey(domainProject, serviceID, instanceID)
+		ops = append(ops,
+			etcdadpt.OpDel(etcdadpt.WithStrKey(key)),
+			etcdadpt.OpDel(etcdadpt.WithStrKey(path.GenerateInstanceLeaseKey(domainProject, serviceID, instanceID))))
+		cmps = append(cmps, etcdadpt.NotEqualVer(key, 0))
+		leaseIDs = append(leaseIDs, leaseID)
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	resp, err := etcdadpt.Instance().TxnWithCmp(ctx, ops, cmps, nil)
+	if err != nil {
+		log.Error(fmt.Sprintf("batch unregister service[%s] instances %v failed, operator %s",
+			serviceID, instanceIDs, remoteIP), err)
+		return pb.NewError(pb.ErrUnavailableBackend, err.Error())
+	}
+	if !resp.Succeeded {
+		log.Error(fmt.Sprintf("batch unregister service[%s] instances %v failed, operator %s: instance does not exist",
+			serviceID, instanceIDs, remoteIP), nil)
+		return pb.NewError(pb.ErrInstanceNotExists, "Service instance does not exist.")
+	}
+	// the keys are deleted, revoke the leases to release them early
+	for _, leaseID := range leaseIDs {
+		if err := etcdadpt.Instance().LeaseRevoke(ctx, leaseID); err != nil && err != etcdadpt.ErrLeaseNotFound {
+			log.Warn(fmt.Sprintf("revoke lease %d failed: %s", leaseID, err))
+		}
+	}
+	for _, instanceID := range instanceIDs {
+		sendEvent(ctx, sync.DeleteAction, datasource.ResourceInstance,
+			&pb.UnregisterInstanceRequest{ServiceId: serviceID, InstanceId: instanceID})
+	}
+	log.Info(fmt.Sprintf("batch unregister service[%s] instances %v, operator %s", serviceID, instanceIDs, remoteIP))
+	return nil
+}
+
 func (ds *MetadataManager) SendHeartbeat(ctx context.Context, request *pb.HeartbeatRequest) error {
 	remoteIP := util.GetIPFromContext(ctx)
 	domainProject := util.ParseDomainProject(ctx)
@@ -1621,3 +1773,52 @@ func (ds *MetadataManager) UpdateManyInstanceStatus(ctx context.Context, match *
 	}
 	return nil
 }
+
+func (ds *MetadataManager) ListAllServiceInstances(ctx context.Context) ([]*datasource.ServiceInstances, error) {
+	instancesResp, err := sd.Instance().Search(ctx,
+		etcdadpt.WithStrKey(path.GetInstanceRootKey("")), etcdadpt.WithPrefix())
+	if err != nil {
+		log.Error("list instances of all domains failed", err)
+		return nil, err
+	}
+	if len(instancesResp.Kvs) == 0 {
+		return nil, nil
+	}
+	servicesResp, err := sd.Service().Search(ctx,
+		etcdadpt.WithStrKey(path.GetServiceRootKey("")), etcdadpt.WithPrefix())
+	if err != nil {
+		log.Error("list services of all domains failed", err)
+		return nil, err
+	}
+	list := make(map[string]*datasource.ServiceInstances, len(servicesResp.Kvs))
+	for _, kv := range servicesResp.Kvs {
+		service, ok := kv.Value.(*pb.MicroService)
+		if !ok {
+			continue
+		}
+		_, domainProject := path.GetInfoFromSvcKV(kv.Key)
+		domain, project := path.SplitDomainProject(domainProject)
+		list[domainProject+path.SPLIT+service.ServiceId] = &datasource.ServiceInstances{
+			Domain:  domain,
+			Project: project,
+			Service: service,
+		}
+	}
+	result := make([]*datasource.ServiceInstances, 0)
+	for _, kv := range instancesResp.Kvs {
+		instance, ok := kv.Value.(*pb.MicroServiceInstance)
+		if !ok {
+			continue
+		}
+		serviceID, _, domainProject := path.GetInfoFromInstKV(kv.Key)
+		si, ok := list[domainProject+path.SPLIT+serviceID]
+		if !ok {
+			continue
+		}
+		if len(si.Instances) == 0 {
+			result = append(result, si)
+		}
+		si.Instances = append(si.Instances, instance)
+	}
+	return result, nil
+}
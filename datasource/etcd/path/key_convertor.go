@@ -58,6 +58,11 @@ func GetInfoFromInstKV(key []byte) (serviceID, instanceID, domainProject string)
 	return
 }
 
+func GetInfoFromProbeKV(key []byte) (serviceID, instanceID, domainProject string) {
+	domainProject, serviceID, instanceID = getLast3Keys(key)
+	return
+}
+
 func GetInfoFromDomainKV(key []byte) (domain string) {
 	keys := splitKey(key)
 	l := len(keys)
@@ -383,3 +383,81 @@ func GenerateMetricsKey(name, utc, domain string) string {
 		domain,
 	}, SPLIT)
 }
+
+func GenerateGovPolicyKey(domainProject string, id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov-policies",
+		domainProject,
+		id,
+	}, SPLIT)
+}
+
+func GenerateGovRevisionKey(domainProject string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov-revisions",
+		domainProject,
+	}, SPLIT)
+}
+
+func GenerateGovHistoryKey(domainProject string, policyID string, version string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov-histories",
+		domainProject,
+		policyID,
+		version,
+	}, SPLIT)
+}
+
+func GenerateGovTemplateKey(domainProject string, id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov-templates",
+		domainProject,
+		id,
+	}, SPLIT)
+}
+
+func GenerateGovTemplateInstanceKey(domainProject string, templateID string, id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov-template-instances",
+		domainProject,
+		templateID,
+		id,
+	}, SPLIT)
+}
+
+func GetDrainRootKey() string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"drains",
+	}, SPLIT)
+}
+
+func GenerateDrainKey(domainProject string, serviceID string, instanceID string) string {
+	return util.StringJoin([]string{
+		GetDrainRootKey(),
+		domainProject,
+		serviceID,
+		instanceID,
+	}, SPLIT)
+}
+
+func GetProbeRootKey() string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"probes",
+	}, SPLIT)
+}
+
+func GenerateProbeKey(domainProject string, serviceID string, instanceID string) string {
+	return util.StringJoin([]string{
+		GetProbeRootKey(),
+		domainProject,
+		serviceID,
+		instanceID,
+	}, SPLIT)
+}
@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/little-cui/etcdadpt"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/datasource/probe"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+func init() {
+	probe.Install("etcd", NewProbeDAO)
+	probe.Install("embeded_etcd", NewProbeDAO)
+	probe.Install("embedded_etcd", NewProbeDAO)
+}
+
+func NewProbeDAO(_ probe.Options) (probe.DAO, error) {
+	return &ProbeDAO{}, nil
+}
+
+type ProbeDAO struct{}
+
+func (dao *ProbeDAO) PutResult(ctx context.Context, r *probe.Result) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		log.Error("instance probe result is invalid", err)
+		return err
+	}
+	err = etcdadpt.PutBytes(ctx, path.GenerateProbeKey(util.ParseDomainProject(ctx), r.ServiceID, r.InstanceID), value)
+	if err != nil {
+		log.Error("can not save instance probe result "+r.InstanceID, err)
+		return err
+	}
+	return nil
+}
+
+func (dao *ProbeDAO) GetResult(ctx context.Context, serviceID, instanceID string) (*probe.Result, error) {
+	kv, err := etcdadpt.Get(ctx, path.GenerateProbeKey(util.ParseDomainProject(ctx), serviceID, instanceID))
+	if err != nil {
+		return nil, err
+	}
+	if kv == nil {
+		return nil, probe.ErrResultNotExists
+	}
+	return toProbeResult(kv)
+}
+
+func (dao *ProbeDAO) ListResults(ctx context.Context, serviceID string) ([]*probe.Result, error) {
+	return listProbeResults(ctx, path.GenerateProbeKey(util.ParseDomainProject(ctx), serviceID, ""))
+}
+
+func (dao *ProbeDAO) ListAllResults(ctx context.Context) ([]*probe.Result, error) {
+	return listProbeResults(ctx, path.GetProbeRootKey()+path.SPLIT)
+}
+
+func (dao *ProbeDAO) DeleteResult(ctx context.Context, serviceID, instanceID string) error {
+	_, err := etcdadpt.Delete(ctx, path.GenerateProbeKey(util.ParseDomainProject(ctx), serviceID, instanceID))
+	if err != nil {
+		log.Error("can not delete instance probe result "+instanceID, err)
+		return err
+	}
+	return nil
+}
+
+func listProbeResults(ctx context.Context, prefix string) ([]*probe.Result, error) {
+	kvs, n, err := etcdadpt.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*probe.Result, 0, n)
+	for _, kv := range kvs {
+		r, err := toProbeResult(kv)
+		if err != nil {
+			log.Error("instance probe result format invalid", err)
+			continue
+		}
+		list = append(list, r)
+	}
+	return list, nil
+}
+
+func toProbeResult(kv *mvccpb.KeyValue) (*probe.Result, error) {
+	r := &probe.Result{}
+	if err := json.Unmarshal(kv.Value, r); err != nil {
+		return nil, err
+	}
+	_, _, domainProject := path.GetInfoFromProbeKV(kv.Key)
+	r.Domain, r.Project = path.SplitDomainProject(domainProject)
+	return r, nil
+}
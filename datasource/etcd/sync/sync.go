@@ -77,22 +77,23 @@ func genOpts(ctx context.Context, action string, resourceType string, resource i
 	for _, option := range options {
 		option(&syncOpts)
 	}
-	taskOpt, err := genTaskOpt(ctx, action, resourceType, resource, &syncOpts)
+	taskOpts, err := genTaskOpts(ctx, action, resourceType, resource, &syncOpts)
 	if err != nil {
 		return nil, err
 	}
 	if action != sync.DeleteAction {
-		return []etcdadpt.OpOptions{taskOpt}, nil
+		return taskOpts, nil
 	}
 	tombstoneOpt, err := genTombstoneOpt(ctx, resourceType, syncOpts.ResourceID)
 	if err != nil {
 		return nil, err
 	}
-	return []etcdadpt.OpOptions{taskOpt, tombstoneOpt}, nil
+	return append(taskOpts, tombstoneOpt), nil
 }
 
-func genTaskOpt(ctx context.Context, action string, resourceType string, resource interface{},
-	syncOpts *Options) (etcdadpt.OpOptions, error) {
+// genTaskOpts puts the task and the index of the task id
+func genTaskOpts(ctx context.Context, action string, resourceType string, resource interface{},
+	syncOpts *Options) ([]etcdadpt.OpOptions, error) {
 	domain := util.ParseDomain(ctx)
 	project := util.ParseProject(ctx)
 	if len(domain) == 0 {
@@ -103,18 +104,21 @@ func genTaskOpt(ctx context.Context, action string, resourceType string, resourc
 	}
 	task, err := sync.NewTask(domain, project, action, resourceType, resource)
 	if err != nil {
-		return etcdadpt.OpOptions{}, err
+		return nil, err
 	}
 	if syncOpts.Opts != nil {
 		task.Opts = syncOpts.Opts
 	}
+	task.Opts = util.SyncRelayOpts(ctx, task.Opts)
 	taskBytes, err := json.Marshal(task)
 	if err != nil {
-		return etcdadpt.OpOptions{}, err
+		return nil, err
 	}
-	taskOpPut := etcdadpt.OpPut(etcdadpt.WithStrKey(key.TaskKey(domain, project,
-		task.ID, task.Timestamp)), etcdadpt.WithValue(taskBytes))
-	return taskOpPut, nil
+	taskKey := key.TaskKey(domain, project, task.ID, task.Timestamp)
+	return []etcdadpt.OpOptions{
+		etcdadpt.OpPut(etcdadpt.WithStrKey(taskKey), etcdadpt.WithValue(taskBytes)),
+		etcdadpt.OpPut(etcdadpt.WithStrKey(key.TaskIndexKey(task.ID)), etcdadpt.WithStrValue(taskKey)),
+	}, nil
 }
 
 func genTombstoneOpt(ctx context.Context, resourceType, resourceID string) (etcdadpt.OpOptions, error) {
@@ -39,18 +39,18 @@ func TestOpts(t *testing.T) {
 	t.Run("create func will create a task opt should pass", func(t *testing.T) {
 		opts, err := sync.GenCreateOpts(optsContext(), datasource.ResourceService, &pb.CreateServiceRequest{})
 		assert.Nil(t, err)
-		assert.Equal(t, 1, len(opts))
+		assert.Equal(t, 2, len(opts))
 	})
 
 	t.Run("update func will create a task opt should pass", func(t *testing.T) {
 		opts, err := sync.GenUpdateOpts(optsContext(), datasource.ResourceService, &pb.UpdateServicePropsRequest{})
 		assert.Nil(t, err)
-		assert.Equal(t, 1, len(opts))
+		assert.Equal(t, 2, len(opts))
 	})
 
 	t.Run("delete func will create a task and a tombstone should pass", func(t *testing.T) {
 		opts, err := sync.GenDeleteOpts(optsContext(), datasource.ResourceService, "11111", &pb.DeleteServiceRequest{})
 		assert.Nil(t, err)
-		assert.Equal(t, 2, len(opts))
+		assert.Equal(t, 3, len(opts))
 	})
 }
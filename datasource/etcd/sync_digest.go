@@ -0,0 +1,230 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-chassis/cari/discovery"
+	crbac "github.com/go-chassis/cari/rbac"
+	"github.com/little-cui/etcdadpt"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+// ListDigest lists the digests of the resources, schemas are listed as kv resource type
+func (s *SyncManager) ListDigest(ctx context.Context, resourceType string) ([]*datasource.Digest, error) {
+	switch resourceType {
+	case datasource.ResourceService:
+		return listServiceDigests(ctx)
+	case datasource.ResourceInstance:
+		return listInstanceDigests(ctx)
+	case datasource.ResourceAccount:
+		return listAccountDigests(ctx)
+	case datasource.ResourceRole:
+		return listRoleDigests(ctx)
+	case datasource.ResourceKV:
+		return listSchemaDigests(ctx)
+	default:
+		return nil, datasource.ErrNotSupportDigest
+	}
+}
+
+func listServiceDigests(ctx context.Context) ([]*datasource.Digest, error) {
+	kvs, _, err := etcdadpt.List(ctx, path.GetServiceRootKey(""))
+	if err != nil {
+		return nil, err
+	}
+	tags, err := listServiceTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	digests := make([]*datasource.Digest, 0, len(kvs))
+	for _, kv := range kvs {
+		service := &discovery.MicroService{}
+		err := json.Unmarshal(kv.Value, service)
+		if err != nil {
+			log.Error("fail to unmarshal service", err)
+			return nil, err
+		}
+		serviceID, domainProject := path.GetInfoFromSvcKV(kv.Key)
+		domain, project := path.SplitDomainProject(domainProject)
+		// only properties can be updated, so the digest ignores the other fields
+		content, err := json.Marshal(service.Properties)
+		if err != nil {
+			return nil, err
+		}
+		d := datasource.NewDigest(datasource.ResourceService, domain, project, serviceID, content).
+			WithSecondTimestamp(service.ModTimestamp)
+		d.Resource = &discovery.CreateServiceRequest{
+			Service: service,
+			Tags:    tags[domainProject+path.SPLIT+serviceID],
+		}
+		d.Update = &discovery.UpdateServicePropsRequest{
+			ServiceId:  serviceID,
+			Properties: service.Properties,
+		}
+		digests = append(digests, d)
+	}
+	return digests, nil
+}
+
+// listServiceTags returns the tags of all the services, keyed by domainProject/serviceID
+func listServiceTags(ctx context.Context) (map[string]map[string]string, error) {
+	kvs, _, err := etcdadpt.List(ctx, path.GetServiceTagRootKey(""))
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]map[string]string, len(kvs))
+	for _, kv := range kvs {
+		t := make(map[string]string)
+		err := json.Unmarshal(kv.Value, &t)
+		if err != nil {
+			log.Error("fail to unmarshal service tags", err)
+			return nil, err
+		}
+		serviceID, domainProject := path.GetInfoFromTagKV(kv.Key)
+		tags[domainProject+path.SPLIT+serviceID] = t
+	}
+	return tags, nil
+}
+
+func listInstanceDigests(ctx context.Context) ([]*datasource.Digest, error) {
+	kvs, _, err := etcdadpt.List(ctx, path.GetInstanceRootKey(""))
+	if err != nil {
+		return nil, err
+	}
+	digests := make([]*datasource.Digest, 0, len(kvs))
+	for _, kv := range kvs {
+		instance := &discovery.MicroServiceInstance{}
+		err := json.Unmarshal(kv.Value, instance)
+		if err != nil {
+			log.Error("fail to unmarshal instance", err)
+			return nil, err
+		}
+		_, instanceID, domainProject := path.GetInfoFromInstKV(kv.Key)
+		domain, project := path.SplitDomainProject(domainProject)
+		modTimestamp := instance.ModTimestamp
+		content, err := json.Marshal(withoutInstanceTimestamp(instance))
+		if err != nil {
+			return nil, err
+		}
+		d := datasource.NewDigest(datasource.ResourceInstance, domain, project, instanceID, content).
+			WithSecondTimestamp(modTimestamp)
+		d.Resource = &discovery.RegisterInstanceRequest{
+			Instance: instance,
+		}
+		d.Update = instance
+		digests = append(digests, d)
+	}
+	return digests, nil
+}
+
+func withoutInstanceTimestamp(instance *discovery.MicroServiceInstance) *discovery.MicroServiceInstance {
+	c := *instance
+	c.Timestamp = ""
+	c.ModTimestamp = ""
+	return &c
+}
+
+func listAccountDigests(ctx context.Context) ([]*datasource.Digest, error) {
+	kvs, _, err := etcdadpt.List(ctx, path.GenerateRBACAccountKey(""))
+	if err != nil {
+		return nil, err
+	}
+	digests := make([]*datasource.Digest, 0, len(kvs))
+	for _, kv := range kvs {
+		a := &crbac.Account{}
+		err = json.Unmarshal(kv.Value, a)
+		if err != nil {
+			log.Error("fail to unmarshal account", err)
+			return nil, err
+		}
+		c := *a
+		c.CreateTime, c.UpdateTime = "", ""
+		content, err := json.Marshal(&c)
+		if err != nil {
+			return nil, err
+		}
+		d := datasource.NewDigest(datasource.ResourceAccount, "", "", a.Name, content).
+			WithSecondTimestamp(a.UpdateTime)
+		d.Resource = a
+		digests = append(digests, d)
+	}
+	return digests, nil
+}
+
+func listRoleDigests(ctx context.Context) ([]*datasource.Digest, error) {
+	kvs, _, err := etcdadpt.List(ctx, path.GenerateRBACRoleKey(""))
+	if err != nil {
+		return nil, err
+	}
+	digests := make([]*datasource.Digest, 0, len(kvs))
+	for _, kv := range kvs {
+		r := &crbac.Role{}
+		err = json.Unmarshal(kv.Value, r)
+		if err != nil {
+			log.Error("fail to unmarshal role", err)
+			return nil, err
+		}
+		c := *r
+		c.CreateTime, c.UpdateTime = "", ""
+		content, err := json.Marshal(&c)
+		if err != nil {
+			return nil, err
+		}
+		d := datasource.NewDigest(datasource.ResourceRole, "", "", r.Name, content).
+			WithSecondTimestamp(r.UpdateTime)
+		d.Resource = r
+		digests = append(digests, d)
+	}
+	return digests, nil
+}
+
+// listSchemaDigests lists the schema keys in kv resource type, same as syncAllSchemas
+func listSchemaDigests(ctx context.Context) ([]*datasource.Digest, error) {
+	roots := []string{
+		path.GetServiceSchemaRootKey(""),
+		path.GetServiceSchemaRefRootKey(""),
+		path.GetServiceSchemaContentRootKey(""),
+		path.GetServiceSchemaSummaryRootKey(""),
+	}
+	digests := make([]*datasource.Digest, 0)
+	for _, root := range roots {
+		kvs, _, err := etcdadpt.List(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		for _, kv := range kvs {
+			domain, project, err := getDomainProject(string(kv.Key), root)
+			if err != nil {
+				log.Error("fail to get domain and project", err)
+				return nil, err
+			}
+			key := string(kv.Key)
+			d := datasource.NewDigest(datasource.ResourceKV, domain, project, key, kv.Value)
+			d.Resource = kv.Value
+			d.Opts = map[string]string{"key": key}
+			digests = append(digests, d)
+		}
+	}
+	return digests, nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gov persists the governance policies in the datasource of service center
+package gov
+
+import (
+	"context"
+
+	"github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+)
+
+var (
+	ErrPolicyNotExists   = discovery.NewError(discovery.ErrInvalidParams, "policy not exist")
+	ErrHistoryNotExists  = discovery.NewError(discovery.ErrInvalidParams, "policy history not exist")
+	ErrTemplateNotExists = discovery.NewError(discovery.ErrInvalidParams, "policy template not exist")
+	ErrTemplateConflict  = discovery.NewError(ErrConflictTemplate, "policy template has been changed by others")
+)
+
+// ErrConflictTemplate is the code of updating a template changed after it was read
+var ErrConflictTemplate int32 = 409002
+
+// DAO manages the policies of the domain project in context,
+// every change increases the revision of the domain project,
+// and the changed policies carry the revision of the change.
+type DAO interface {
+	CreatePolicy(ctx context.Context, p *gov.Policy) error
+	UpdatePolicy(ctx context.Context, p *gov.Policy) error
+	// DeletePolicies deletes the policies and increases the revision once
+	DeletePolicies(ctx context.Context, ids ...string) error
+	GetPolicy(ctx context.Context, id string) (*gov.Policy, error)
+	// ListPolicies returns all the policies if kind is empty
+	ListPolicies(ctx context.Context, kind string) ([]*gov.Policy, error)
+	// GetRevision returns 0 if no policy was ever changed
+	GetRevision(ctx context.Context) (int64, error)
+
+	// AddHistory saves h as the next version of its policy and sets h.Version,
+	// the history of a policy is kept after the policy is deleted
+	AddHistory(ctx context.Context, h *gov.History) error
+	// ListHistory returns the history of the policy in ascending version
+	ListHistory(ctx context.Context, policyID string) ([]*gov.History, error)
+	GetHistory(ctx context.Context, policyID string, version int64) (*gov.History, error)
+
+	// PutTemplate creates or replaces the template, the revision is kept as it is
+	PutTemplate(ctx context.Context, t *gov.Template) error
+	// UpdateTemplate replaces the template only if the saved revision is still revision,
+	// otherwise returns ErrTemplateConflict
+	UpdateTemplate(ctx context.Context, t *gov.Template, revision int64) error
+	GetTemplate(ctx context.Context, id string) (*gov.Template, error)
+	ListTemplates(ctx context.Context) ([]*gov.Template, error)
+	// DeleteTemplate deletes the template and its instance records,
+	// the policies of the instances are not deleted
+	DeleteTemplate(ctx context.Context, id string) error
+	// PutTemplateInstance creates or replaces the instance record
+	PutTemplateInstance(ctx context.Context, i *gov.TemplateInstance) error
+	ListTemplateInstances(ctx context.Context, templateID string) ([]*gov.TemplateInstance, error)
+}
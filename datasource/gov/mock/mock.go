@@ -0,0 +1,215 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package mock is an in-memory gov datasource for tests
+package mock
+
+import (
+	"context"
+	"sync"
+
+	govds "github.com/apache/servicecomb-service-center/datasource/gov"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+const Kind = "mock"
+
+type DAO struct {
+	mux       sync.Mutex
+	revisions map[string]int64
+	policies  map[string]map[string]*gov.Policy
+	histories map[string][]*gov.History
+	templates map[string]map[string]*gov.Template
+	instances map[string]map[string]*gov.TemplateInstance
+}
+
+func (m *DAO) CreatePolicy(ctx context.Context, p *gov.Policy) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	domainProject := util.ParseDomainProject(ctx)
+	if m.policies[domainProject] == nil {
+		m.policies[domainProject] = map[string]*gov.Policy{}
+	}
+	m.revisions[domainProject]++
+	p.Revision = m.revisions[domainProject]
+	m.policies[domainProject][p.ID] = p
+	return nil
+}
+
+func (m *DAO) UpdatePolicy(ctx context.Context, p *gov.Policy) error {
+	if _, err := m.GetPolicy(ctx, p.ID); err != nil {
+		return err
+	}
+	return m.CreatePolicy(ctx, p)
+}
+
+func (m *DAO) DeletePolicies(ctx context.Context, ids ...string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	domainProject := util.ParseDomainProject(ctx)
+	for _, id := range ids {
+		delete(m.policies[domainProject], id)
+	}
+	m.revisions[domainProject]++
+	return nil
+}
+
+func (m *DAO) GetPolicy(ctx context.Context, id string) (*gov.Policy, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	p, ok := m.policies[util.ParseDomainProject(ctx)][id]
+	if !ok {
+		return nil, govds.ErrPolicyNotExists
+	}
+	return p, nil
+}
+
+func (m *DAO) ListPolicies(ctx context.Context, kind string) ([]*gov.Policy, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	var r []*gov.Policy
+	for _, p := range m.policies[util.ParseDomainProject(ctx)] {
+		if kind == "" || p.Kind == kind {
+			r = append(r, p)
+		}
+	}
+	return r, nil
+}
+
+func (m *DAO) GetRevision(ctx context.Context) (int64, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.revisions[util.ParseDomainProject(ctx)], nil
+}
+
+func (m *DAO) AddHistory(ctx context.Context, h *gov.History) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	key := util.ParseDomainProject(ctx) + "/" + h.PolicyID
+	h.Version = int64(len(m.histories[key]) + 1)
+	m.histories[key] = append(m.histories[key], h)
+	return nil
+}
+
+func (m *DAO) ListHistory(ctx context.Context, policyID string) ([]*gov.History, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return append([]*gov.History{}, m.histories[util.ParseDomainProject(ctx)+"/"+policyID]...), nil
+}
+
+func (m *DAO) GetHistory(ctx context.Context, policyID string, version int64) (*gov.History, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	list := m.histories[util.ParseDomainProject(ctx)+"/"+policyID]
+	if version < 1 || version > int64(len(list)) {
+		return nil, govds.ErrHistoryNotExists
+	}
+	return list[version-1], nil
+}
+
+func (m *DAO) PutTemplate(ctx context.Context, t *gov.Template) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	domainProject := util.ParseDomainProject(ctx)
+	if m.templates[domainProject] == nil {
+		m.templates[domainProject] = map[string]*gov.Template{}
+	}
+	c := *t
+	m.templates[domainProject][t.ID] = &c
+	return nil
+}
+
+func (m *DAO) UpdateTemplate(ctx context.Context, t *gov.Template, revision int64) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	old, ok := m.templates[util.ParseDomainProject(ctx)][t.ID]
+	if !ok {
+		return govds.ErrTemplateNotExists
+	}
+	if old.Revision != revision {
+		return govds.ErrTemplateConflict
+	}
+	c := *t
+	m.templates[util.ParseDomainProject(ctx)][t.ID] = &c
+	return nil
+}
+
+func (m *DAO) GetTemplate(ctx context.Context, id string) (*gov.Template, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	t, ok := m.templates[util.ParseDomainProject(ctx)][id]
+	if !ok {
+		return nil, govds.ErrTemplateNotExists
+	}
+	c := *t
+	return &c, nil
+}
+
+func (m *DAO) ListTemplates(ctx context.Context) ([]*gov.Template, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	var r []*gov.Template
+	for _, t := range m.templates[util.ParseDomainProject(ctx)] {
+		r = append(r, t)
+	}
+	return r, nil
+}
+
+func (m *DAO) DeleteTemplate(ctx context.Context, id string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	domainProject := util.ParseDomainProject(ctx)
+	delete(m.templates[domainProject], id)
+	delete(m.instances, domainProject+"/"+id)
+	return nil
+}
+
+func (m *DAO) PutTemplateInstance(ctx context.Context, i *gov.TemplateInstance) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	key := util.ParseDomainProject(ctx) + "/" + i.TemplateID
+	if m.instances[key] == nil {
+		m.instances[key] = map[string]*gov.TemplateInstance{}
+	}
+	m.instances[key][i.ID] = i
+	return nil
+}
+
+func (m *DAO) ListTemplateInstances(ctx context.Context, templateID string) ([]*gov.TemplateInstance, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	var r []*gov.TemplateInstance
+	for _, i := range m.instances[util.ParseDomainProject(ctx)+"/"+templateID] {
+		r = append(r, i)
+	}
+	return r, nil
+}
+
+func New(_ govds.Options) (govds.DAO, error) {
+	return &DAO{
+		revisions: map[string]int64{},
+		policies:  map[string]map[string]*gov.Policy{},
+		histories: map[string][]*gov.History{},
+		templates: map[string]map[string]*gov.Template{},
+		instances: map[string]map[string]*gov.TemplateInstance{},
+	}, nil
+}
+
+func init() {
+	govds.Install(Kind, New)
+}
@@ -0,0 +1,210 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	govds "github.com/apache/servicecomb-service-center/datasource/gov"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+func TestGov_Policy(t *testing.T) {
+	ctx := getContext()
+	p := &gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{
+			ID:       util.GenerateUUID(),
+			Name:     "gov_policy_test",
+			Selector: gov.Selector{"app": "gov_app", "environment": "gov_env"},
+		},
+		Kind: "retry",
+		Spec: map[string]interface{}{"maxAttempts": float64(3)},
+	}
+
+	var revision int64
+	t.Run("create policy, should increase the revision", func(t *testing.T) {
+		before, err := govds.Instance().GetRevision(ctx)
+		assert.NoError(t, err)
+		err = govds.Instance().CreatePolicy(ctx, p)
+		assert.NoError(t, err)
+		revision, err = govds.Instance().GetRevision(ctx)
+		assert.NoError(t, err)
+		assert.True(t, revision > before)
+		assert.Equal(t, revision, p.Revision)
+	})
+
+	t.Run("get and list policy, should return the policy", func(t *testing.T) {
+		got, err := govds.Instance().GetPolicy(ctx, p.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, p.Name, got.Name)
+		assert.Equal(t, revision, got.Revision)
+		assert.Equal(t, p.Spec, got.Spec)
+
+		list, err := govds.Instance().ListPolicies(ctx, "retry")
+		assert.NoError(t, err)
+		var found bool
+		for _, item := range list {
+			found = found || item.ID == p.ID
+		}
+		assert.True(t, found)
+
+		list, err = govds.Instance().ListPolicies(ctx, "loadbalance")
+		assert.NoError(t, err)
+		for _, item := range list {
+			assert.NotEqual(t, p.ID, item.ID)
+		}
+	})
+
+	t.Run("update policy, should increase the revision", func(t *testing.T) {
+		p.Spec = map[string]interface{}{"maxAttempts": float64(5)}
+		err := govds.Instance().UpdatePolicy(ctx, p)
+		assert.NoError(t, err)
+		assert.True(t, p.Revision > revision)
+		revision = p.Revision
+
+		got, err := govds.Instance().GetPolicy(ctx, p.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(5), got.Spec["maxAttempts"])
+
+		err = govds.Instance().UpdatePolicy(ctx, &gov.Policy{
+			GovernancePolicy: &gov.GovernancePolicy{ID: "not_exist"},
+		})
+		assert.Equal(t, govds.ErrPolicyNotExists, err)
+	})
+
+	t.Run("delete policy, should increase the revision", func(t *testing.T) {
+		err := govds.Instance().DeletePolicies(ctx, p.ID)
+		assert.NoError(t, err)
+		current, err := govds.Instance().GetRevision(ctx)
+		assert.NoError(t, err)
+		assert.True(t, current > revision)
+
+		_, err = govds.Instance().GetPolicy(ctx, p.ID)
+		assert.Equal(t, govds.ErrPolicyNotExists, err)
+	})
+}
+
+func TestGov_History(t *testing.T) {
+	ctx := getContext()
+	policyID := util.GenerateUUID()
+
+	t.Run("add history, should assign increasing versions", func(t *testing.T) {
+		for _, action := range []string{gov.ActionCreate, gov.ActionUpdate} {
+			h := &gov.History{
+				PolicyID: policyID,
+				Kind:     "retry",
+				Action:   action,
+				Policy: &gov.Policy{
+					GovernancePolicy: &gov.GovernancePolicy{ID: policyID, Name: "gov_history_test"},
+					Spec:             map[string]interface{}{"maxAttempts": float64(3)},
+				},
+			}
+			err := govds.Instance().AddHistory(ctx, h)
+			assert.NoError(t, err)
+			assert.True(t, h.Version > 0)
+		}
+	})
+
+	t.Run("list and get history, should return in version order", func(t *testing.T) {
+		list, err := govds.Instance().ListHistory(ctx, policyID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(list))
+		assert.True(t, list[0].Version < list[1].Version)
+		assert.Equal(t, gov.ActionUpdate, list[1].Action)
+
+		h, err := govds.Instance().GetHistory(ctx, policyID, list[0].Version)
+		assert.NoError(t, err)
+		assert.Equal(t, gov.ActionCreate, h.Action)
+		assert.Equal(t, float64(3), h.Policy.Spec["maxAttempts"])
+
+		_, err = govds.Instance().GetHistory(ctx, policyID, 100)
+		assert.Equal(t, govds.ErrHistoryNotExists, err)
+	})
+}
+
+func TestGov_Template(t *testing.T) {
+	ctx := getContext()
+	tpl := &gov.Template{
+		ID:         util.GenerateUUID(),
+		Name:       "gov_template_test",
+		Revision:   1,
+		MatchGroup: map[string]interface{}{"matches": []interface{}{}},
+		Policies:   map[string]map[string]interface{}{"retry": {"maxAttempts": float64(3)}},
+	}
+
+	t.Run("put and get template, should return the template", func(t *testing.T) {
+		err := govds.Instance().PutTemplate(ctx, tpl)
+		assert.NoError(t, err)
+		tpl.Revision = 2
+		err = govds.Instance().PutTemplate(ctx, tpl)
+		assert.NoError(t, err)
+
+		got, err := govds.Instance().GetTemplate(ctx, tpl.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), got.Revision)
+		assert.Equal(t, float64(3), got.Policies["retry"]["maxAttempts"])
+
+		list, err := govds.Instance().ListTemplates(ctx)
+		assert.NoError(t, err)
+		var found bool
+		for _, item := range list {
+			found = found || item.ID == tpl.ID
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("update template with an old revision, should return conflict", func(t *testing.T) {
+		tpl.Revision = 3
+		err := govds.Instance().UpdateTemplate(ctx, tpl, 2)
+		assert.NoError(t, err)
+		err = govds.Instance().UpdateTemplate(ctx, tpl, 2)
+		assert.Equal(t, govds.ErrTemplateConflict, err)
+
+		got, err := govds.Instance().GetTemplate(ctx, tpl.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), got.Revision)
+	})
+
+	t.Run("put instance, should list it by the template", func(t *testing.T) {
+		err := govds.Instance().PutTemplateInstance(ctx, &gov.TemplateInstance{
+			ID:         util.GenerateUUID(),
+			TemplateID: tpl.ID,
+			Revision:   2,
+			Target:     &gov.TemplateTarget{App: "gov_app"},
+			Policies:   map[string]string{"retry": util.GenerateUUID()},
+		})
+		assert.NoError(t, err)
+		list, err := govds.Instance().ListTemplateInstances(ctx, tpl.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(list))
+		assert.Equal(t, "gov_app", list[0].Target.App)
+	})
+
+	t.Run("delete template, should delete its instances", func(t *testing.T) {
+		err := govds.Instance().DeleteTemplate(ctx, tpl.ID)
+		assert.NoError(t, err)
+		_, err = govds.Instance().GetTemplate(ctx, tpl.ID)
+		assert.Equal(t, govds.ErrTemplateNotExists, err)
+		list, err := govds.Instance().ListTemplateInstances(ctx, tpl.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(list))
+	})
+}
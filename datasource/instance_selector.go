@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/pkg/selector"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+const (
+	CtxInstanceSelector util.CtxKey = "instanceSelector"
+
+	// InstancePropertiesPrefix is the prefix of the selector key to select the instance property
+	InstancePropertiesPrefix = "properties."
+
+	// selectorRevSeparator joins the instances revision and the selector revision
+	selectorRevSeparator = "."
+)
+
+// InstanceFields is the selector fields of the instance, the keys are
+// instanceId, serviceId, status, version, hostName, dataCenterInfo.name,
+// dataCenterInfo.region, dataCenterInfo.availableZone and properties.{name}
+type InstanceFields struct {
+	Instance *discovery.MicroServiceInstance
+}
+
+func (f InstanceFields) Get(key string) (string, bool) {
+	if strings.HasPrefix(key, InstancePropertiesPrefix) {
+		v, ok := f.Instance.Properties[key[len(InstancePropertiesPrefix):]]
+		return v, ok
+	}
+	var v string
+	switch key {
+	case "instanceId":
+		v = f.Instance.InstanceId
+	case "serviceId":
+		v = f.Instance.ServiceId
+	case "status":
+		v = f.Instance.Status
+	case "version":
+		v = f.Instance.Version
+	case "hostName":
+		v = f.Instance.HostName
+	case "dataCenterInfo.name", "dataCenterInfo.region", "dataCenterInfo.availableZone":
+		v = dataCenterField(f.Instance.DataCenterInfo, key)
+	}
+	return v, len(v) > 0
+}
+
+func dataCenterField(dc *discovery.DataCenterInfo, key string) string {
+	if dc == nil {
+		return ""
+	}
+	switch key {
+	case "dataCenterInfo.name":
+		return dc.Name
+	case "dataCenterInfo.region":
+		return dc.Region
+	default:
+		return dc.AvailableZone
+	}
+}
+
+// ValidateInstanceSelector returns error if the selector has any unknown key of instance
+func ValidateInstanceSelector(s selector.Selector) error {
+	for _, r := range s {
+		switch r.Key {
+		case "instanceId", "serviceId", "status", "version", "hostName",
+			"dataCenterInfo.name", "dataCenterInfo.region", "dataCenterInfo.availableZone":
+			continue
+		}
+		if !strings.HasPrefix(r.Key, InstancePropertiesPrefix) || len(r.Key) == len(InstancePropertiesPrefix) {
+			return fmt.Errorf("unknown instance selector key '%s'", r.Key)
+		}
+	}
+	return nil
+}
+
+// WithInstanceSelector sets the selector to filter the instances found
+func WithInstanceSelector(ctx context.Context, s selector.Selector) context.Context {
+	return util.SetContext(ctx, CtxInstanceSelector, s)
+}
+
+func GetInstanceSelector(ctx context.Context) selector.Selector {
+	s, _ := ctx.Value(CtxInstanceSelector).(selector.Selector)
+	return s
+}
+
+// FilterInstances returns the instances matched the selector, never modifies the input
+func FilterInstances(s selector.Selector, instances []*discovery.MicroServiceInstance) []*discovery.MicroServiceInstance {
+	if s.Empty() {
+		return instances
+	}
+	matched := make([]*discovery.MicroServiceInstance, 0, len(instances))
+	for _, instance := range instances {
+		if s.Matches(InstanceFields{Instance: instance}) {
+			matched = append(matched, instance)
+		}
+	}
+	return matched
+}
+
+// SelectorRevision appends the hash of the selector to the instances revision, so that the
+// consumers polling with the revision get the instances again when the selector changes
+func SelectorRevision(s selector.Selector, rev string) string {
+	if s.Empty() || len(rev) == 0 {
+		return rev
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s.String()))
+	return fmt.Sprintf("%s%s%x", rev, selectorRevSeparator, h.Sum32())
+}
+
+// InstancesRevision removes the selector revision appended by SelectorRevision
+func InstancesRevision(rev string) string {
+	if i := strings.Index(rev, selectorRevSeparator); i >= 0 {
+		return rev[:i]
+	}
+	return rev
+}
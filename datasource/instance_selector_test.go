@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource_test
+
+import (
+	"testing"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/selector"
+)
+
+func TestFilterInstances(t *testing.T) {
+	instances := []*discovery.MicroServiceInstance{
+		{InstanceId: "1", Status: discovery.MSI_UP, Version: "1.0.0", HostName: "a",
+			Properties:     map[string]string{"env": "prod"},
+			DataCenterInfo: &discovery.DataCenterInfo{Name: "dc", Region: "r1", AvailableZone: "az1"}},
+		{InstanceId: "2", Status: discovery.MSI_DOWN, Version: "1.0.1", HostName: "b",
+			Properties: map[string]string{"env": "gray", "canary": "true"}},
+	}
+
+	t.Run("empty selector should return all", func(t *testing.T) {
+		assert.Equal(t, instances, datasource.FilterInstances(nil, instances))
+	})
+
+	cases := map[string][]string{
+		"status=UP":                                      {"1"},
+		"version in (1.0.1,2.0.0)":                       {"2"},
+		"hostName!=a":                                    {"2"},
+		"dataCenterInfo.region=r1":                       {"1"},
+		"!dataCenterInfo.availableZone":                  {"2"},
+		"properties.env in (prod,gray)":                  {"1", "2"},
+		"properties.env notin (gray),!properties.canary": {"1"},
+		"properties.canary exists":                       {"2"},
+		"properties.env=test":                            {},
+	}
+	for expr, expected := range cases {
+		s, err := selector.Parse(expr)
+		assert.NoError(t, err)
+		assert.NoError(t, datasource.ValidateInstanceSelector(s))
+		ids := make([]string, 0)
+		for _, instance := range datasource.FilterInstances(s, instances) {
+			ids = append(ids, instance.InstanceId)
+		}
+		assert.Equal(t, expected, ids, expr)
+	}
+
+	t.Run("unknown key should be invalid", func(t *testing.T) {
+		for _, expr := range []string{"endpoints=a", "properties.", "dataCenterInfo.zone=a"} {
+			s, err := selector.Parse(expr)
+			assert.NoError(t, err)
+			assert.Error(t, datasource.ValidateInstanceSelector(s), expr)
+		}
+	})
+}
+
+func TestSelectorRevision(t *testing.T) {
+	t.Run("empty selector should keep the revision", func(t *testing.T) {
+		assert.Equal(t, "abc", datasource.SelectorRevision(nil, "abc"))
+		assert.Equal(t, "abc", datasource.InstancesRevision("abc"))
+	})
+
+	t.Run("different selectors should return different revisions", func(t *testing.T) {
+		s1, err := selector.Parse("status=UP")
+		assert.NoError(t, err)
+		s2, err := selector.Parse("status=DOWN")
+		assert.NoError(t, err)
+		rev1 := datasource.SelectorRevision(s1, "abc")
+		rev2 := datasource.SelectorRevision(s2, "abc")
+		assert.NotEqual(t, rev1, rev2)
+		assert.NotEqual(t, "abc", rev1)
+		assert.Equal(t, "abc", datasource.InstancesRevision(rev1))
+		assert.Equal(t, "", datasource.SelectorRevision(s1, ""))
+	})
+}
@@ -1095,6 +1095,78 @@ func TestInstance_Unregister(t *testing.T) {
 	})
 }
 
+func TestInstance_Many(t *testing.T) {
+	var serviceID string
+	ctx := getContext()
+	defer func() {
+		datasource.GetMetadataManager().UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: serviceID, Force: true})
+	}()
+
+	t.Run("register service", func(t *testing.T) {
+		respCreateService, err := datasource.GetMetadataManager().RegisterService(ctx, &pb.CreateServiceRequest{
+			Service: &pb.MicroService{
+				AppId:       "many_instance_ms",
+				ServiceName: "many_instance_service_ms",
+				Version:     "1.0.0",
+				Level:       "FRONT",
+				Status:      pb.MS_UP,
+			},
+		})
+		assert.NoError(t, err)
+		serviceID = respCreateService.ServiceId
+	})
+
+	instances := []*pb.MicroServiceInstance{
+		{ServiceId: serviceID, HostName: "UT-HOST-MS", Endpoints: []string{"many:127.0.0.1:8080"}, Status: pb.MSI_UP},
+		{ServiceId: serviceID, HostName: "UT-HOST-MS", Endpoints: []string{"many:127.0.0.1:8081"}, Status: pb.MSI_UP},
+	}
+
+	t.Run("register many instances, should be passed", func(t *testing.T) {
+		for _, instance := range instances {
+			instance.ServiceId = serviceID
+		}
+		err := datasource.GetMetadataManager().RegisterManyInstances(ctx, serviceID, instances)
+		assert.NoError(t, err)
+		for _, instance := range instances {
+			assert.NotEmpty(t, instance.InstanceId)
+			resp, err := datasource.GetMetadataManager().ExistInstance(ctx, &pb.MicroServiceInstanceKey{
+				ServiceId:  serviceID,
+				InstanceId: instance.InstanceId,
+			})
+			assert.NoError(t, err)
+			assert.True(t, resp.Exist)
+		}
+	})
+
+	t.Run("unregister many instances with one not exist, should be failed", func(t *testing.T) {
+		err := datasource.GetMetadataManager().UnregisterManyInstances(ctx, serviceID,
+			[]string{instances[0].InstanceId, "not-exist-id-ms"})
+		testErr := err.(*errsvc.Error)
+		assert.Equal(t, pb.ErrInstanceNotExists, testErr.Code)
+
+		resp, err := datasource.GetMetadataManager().ExistInstance(ctx, &pb.MicroServiceInstanceKey{
+			ServiceId:  serviceID,
+			InstanceId: instances[0].InstanceId,
+		})
+		assert.NoError(t, err)
+		assert.True(t, resp.Exist)
+	})
+
+	t.Run("unregister many instances, should be passed", func(t *testing.T) {
+		err := datasource.GetMetadataManager().UnregisterManyInstances(ctx, serviceID,
+			[]string{instances[0].InstanceId, instances[1].InstanceId})
+		assert.NoError(t, err)
+		for _, instance := range instances {
+			resp, err := datasource.GetMetadataManager().ExistInstance(ctx, &pb.MicroServiceInstanceKey{
+				ServiceId:  serviceID,
+				InstanceId: instance.InstanceId,
+			})
+			assert.NoError(t, err)
+			assert.False(t, resp.Exist)
+		}
+	})
+}
+
 func TestInstance_Exist(t *testing.T) {
 	var (
 		serviceId  string
@@ -23,6 +23,9 @@ import (
 
 	"github.com/go-chassis/cari/dlock"
 
+	"github.com/apache/servicecomb-service-center/datasource/drain"
+	"github.com/apache/servicecomb-service-center/datasource/gov"
+	"github.com/apache/servicecomb-service-center/datasource/probe"
 	"github.com/apache/servicecomb-service-center/datasource/rbac"
 	"github.com/apache/servicecomb-service-center/datasource/schema"
 	"github.com/apache/servicecomb-service-center/eventbase/datasource"
@@ -64,6 +67,18 @@ func Init(opts Options) error {
 	if err != nil {
 		return err
 	}
+	err = gov.Init(gov.Options{Kind: opts.Kind})
+	if err != nil {
+		return err
+	}
+	err = drain.Init(drain.Options{Kind: opts.Kind})
+	if err != nil {
+		return err
+	}
+	err = probe.Init(probe.Options{Kind: opts.Kind})
+	if err != nil {
+		return err
+	}
 	err = dlock.Init(dlock.Options{Kind: opts.Kind})
 	if err != nil {
 		return err
@@ -34,6 +34,9 @@ func ensureDB() {
 	ensureAccount()
 	ensureAccountLock()
 	ensureSyncLock()
+	ensureGov()
+	ensureDrain()
+	ensureProbe()
 }
 
 func ensureService() {
@@ -99,3 +102,61 @@ func ensureSyncLock() {
 	dmongo.EnsureCollection(model.CollectionSync, nil, []mongo.IndexModel{
 		util.BuildIndexDoc(model.ColumnKey)})
 }
+
+func ensureGov() {
+	policyIndex := util.BuildIndexDoc(
+		model.ColumnDomain,
+		model.ColumnProject,
+		model.ColumnID)
+	policyIndex.Options = options.Index().SetUnique(true)
+	dmongo.EnsureCollection(model.CollectionGovPolicy, nil, []mongo.IndexModel{policyIndex})
+
+	revisionIndex := util.BuildIndexDoc(
+		model.ColumnDomain,
+		model.ColumnProject)
+	revisionIndex.Options = options.Index().SetUnique(true)
+	dmongo.EnsureCollection(model.CollectionGovRevision, nil, []mongo.IndexModel{revisionIndex})
+
+	historyIndex := util.BuildIndexDoc(
+		model.ColumnDomain,
+		model.ColumnProject,
+		model.ColumnPolicyID,
+		model.ColumnVersion)
+	historyIndex.Options = options.Index().SetUnique(true)
+	dmongo.EnsureCollection(model.CollectionGovHistory, nil, []mongo.IndexModel{historyIndex})
+
+	templateIndex := util.BuildIndexDoc(
+		model.ColumnDomain,
+		model.ColumnProject,
+		model.ColumnID)
+	templateIndex.Options = options.Index().SetUnique(true)
+	dmongo.EnsureCollection(model.CollectionGovTemplate, nil, []mongo.IndexModel{templateIndex})
+
+	instanceIndex := util.BuildIndexDoc(
+		model.ColumnDomain,
+		model.ColumnProject,
+		model.ColumnTemplateID,
+		model.ColumnID)
+	instanceIndex.Options = options.Index().SetUnique(true)
+	dmongo.EnsureCollection(model.CollectionGovInstance, nil, []mongo.IndexModel{instanceIndex})
+}
+
+func ensureDrain() {
+	drainIndex := util.BuildIndexDoc(
+		model.ColumnDomain,
+		model.ColumnProject,
+		model.ColumnServiceID,
+		model.ColumnInstanceID)
+	drainIndex.Options = options.Index().SetUnique(true)
+	dmongo.EnsureCollection(model.CollectionDrain, nil, []mongo.IndexModel{drainIndex})
+}
+
+func ensureProbe() {
+	probeIndex := util.BuildIndexDoc(
+		model.ColumnDomain,
+		model.ColumnProject,
+		model.ColumnServiceID,
+		model.ColumnInstanceID)
+	probeIndex.Options = options.Index().SetUnique(true)
+	dmongo.EnsureCollection(model.CollectionProbe, nil, []mongo.IndexModel{probeIndex})
+}
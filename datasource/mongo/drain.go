@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+
+	dmongo "github.com/go-chassis/cari/db/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource/drain"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+func init() {
+	drain.Install("mongo", NewDrainDAO)
+}
+
+func NewDrainDAO(_ drain.Options) (drain.DAO, error) {
+	return &DrainDAO{}, nil
+}
+
+type DrainDAO struct{}
+
+func (dao *DrainDAO) PutDrain(ctx context.Context, d *drain.Drain) error {
+	b, err := json.Marshal(d)
+	if err != nil {
+		log.Error("instance drain is invalid", err)
+		return err
+	}
+	_, err = dmongo.GetClient().GetDB().Collection(model.CollectionDrain).ReplaceOne(ctx,
+		drainFilter(ctx, d.ServiceID, d.InstanceID), &model.Drain{
+			Domain:     util.ParseDomain(ctx),
+			Project:    util.ParseProject(ctx),
+			ServiceID:  d.ServiceID,
+			InstanceID: d.InstanceID,
+			Drain:      string(b),
+		}, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Error("can not save instance drain "+d.InstanceID, err)
+		return err
+	}
+	return nil
+}
+
+func (dao *DrainDAO) GetDrain(ctx context.Context, serviceID, instanceID string) (*drain.Drain, error) {
+	result := dmongo.GetClient().GetDB().Collection(model.CollectionDrain).FindOne(ctx,
+		drainFilter(ctx, serviceID, instanceID))
+	if result.Err() == mongo.ErrNoDocuments {
+		return nil, drain.ErrDrainNotExists
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	var doc model.Drain
+	if err := result.Decode(&doc); err != nil {
+		return nil, err
+	}
+	d := &drain.Drain{}
+	if err := json.Unmarshal([]byte(doc.Drain), d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (dao *DrainDAO) ListAllDrains(ctx context.Context) ([]*drain.Drain, error) {
+	cursor, err := dmongo.GetClient().GetDB().Collection(model.CollectionDrain).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	list := make([]*drain.Drain, 0)
+	for cursor.Next(ctx) {
+		var doc model.Drain
+		if err = cursor.Decode(&doc); err != nil {
+			log.Error("failed to decode instance drain", err)
+			continue
+		}
+		d := &drain.Drain{}
+		if err = json.Unmarshal([]byte(doc.Drain), d); err != nil {
+			log.Error("instance drain format invalid", err)
+			continue
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+func (dao *DrainDAO) DeleteDrain(ctx context.Context, serviceID, instanceID string) error {
+	_, err := dmongo.GetClient().GetDB().Collection(model.CollectionDrain).DeleteOne(ctx,
+		drainFilter(ctx, serviceID, instanceID))
+	if err != nil {
+		log.Error("can not delete instance drain "+instanceID, err)
+		return err
+	}
+	return nil
+}
+
+func drainFilter(ctx context.Context, serviceID, instanceID string) bson.M {
+	filter := mutil.NewBasicFilter(ctx)
+	filter[model.ColumnServiceID] = serviceID
+	filter[model.ColumnInstanceID] = instanceID
+	return filter
+}
@@ -0,0 +1,451 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+
+	dmongo "github.com/go-chassis/cari/db/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	govds "github.com/apache/servicecomb-service-center/datasource/gov"
+	mdao "github.com/apache/servicecomb-service-center/datasource/mongo/dao"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+func init() {
+	govds.Install("mongo", NewGovDAO)
+}
+
+func NewGovDAO(_ govds.Options) (govds.DAO, error) {
+	return &GovDAO{}, nil
+}
+
+// GovDAO increases the revision counter of the domain project in the same txn with every change
+type GovDAO struct{}
+
+func (dao *GovDAO) CreatePolicy(ctx context.Context, p *gov.Policy) error {
+	return dmongo.GetClient().ExecTxn(ctx, func(sessionContext mongo.SessionContext) error {
+		doc, err := toGovPolicy(sessionContext, p)
+		if err != nil {
+			return err
+		}
+		_, err = dmongo.GetClient().GetDB().Collection(model.CollectionGovPolicy).InsertOne(sessionContext, doc)
+		if err != nil {
+			log.Error("can not save policy "+p.ID, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (dao *GovDAO) UpdatePolicy(ctx context.Context, p *gov.Policy) error {
+	return dmongo.GetClient().ExecTxn(ctx, func(sessionContext mongo.SessionContext) error {
+		doc, err := toGovPolicy(sessionContext, p)
+		if err != nil {
+			return err
+		}
+		filter := mutil.NewBasicFilter(ctx, mutil.ID(p.ID))
+		result, err := dmongo.GetClient().GetDB().Collection(model.CollectionGovPolicy).ReplaceOne(sessionContext, filter, doc)
+		if err != nil {
+			log.Error("can not update policy "+p.ID, err)
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return govds.ErrPolicyNotExists
+		}
+		return nil
+	})
+}
+
+func (dao *GovDAO) DeletePolicies(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return dmongo.GetClient().ExecTxn(ctx, func(sessionContext mongo.SessionContext) error {
+		filter := mutil.NewBasicFilter(ctx)
+		filter[model.ColumnID] = mutil.NewFilter(mutil.In(ids))
+		_, err := dmongo.GetClient().GetDB().Collection(model.CollectionGovPolicy).DeleteMany(sessionContext, filter)
+		if err != nil {
+			log.Error("can not delete policies", err)
+			return err
+		}
+		_, err = incGovRevision(sessionContext)
+		return err
+	})
+}
+
+func (dao *GovDAO) GetPolicy(ctx context.Context, id string) (*gov.Policy, error) {
+	filter := mutil.NewBasicFilter(ctx, mutil.ID(id))
+	result := dmongo.GetClient().GetDB().Collection(model.CollectionGovPolicy).FindOne(ctx, filter)
+	if result.Err() == mongo.ErrNoDocuments {
+		return nil, govds.ErrPolicyNotExists
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	var doc model.GovPolicy
+	err := result.Decode(&doc)
+	if err != nil {
+		log.Error("failed to decode policy", err)
+		return nil, err
+	}
+	return fromGovPolicy(&doc)
+}
+
+func (dao *GovDAO) ListPolicies(ctx context.Context, kind string) ([]*gov.Policy, error) {
+	filter := mutil.NewBasicFilter(ctx)
+	if kind != "" {
+		filter[model.ColumnKind] = kind
+	}
+	cursor, err := dmongo.GetClient().GetDB().Collection(model.CollectionGovPolicy).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	policies := make([]*gov.Policy, 0)
+	for cursor.Next(ctx) {
+		var doc model.GovPolicy
+		err = cursor.Decode(&doc)
+		if err != nil {
+			log.Error("failed to decode policy", err)
+			continue
+		}
+		p, err := fromGovPolicy(&doc)
+		if err != nil {
+			log.Error("policy format invalid", err)
+			continue
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (dao *GovDAO) GetRevision(ctx context.Context) (int64, error) {
+	result := dmongo.GetClient().GetDB().Collection(model.CollectionGovRevision).FindOne(ctx, mutil.NewBasicFilter(ctx))
+	if result.Err() == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if result.Err() != nil {
+		return 0, result.Err()
+	}
+	var doc model.GovRevision
+	if err := result.Decode(&doc); err != nil {
+		return 0, err
+	}
+	return doc.Revision, nil
+}
+
+func (dao *GovDAO) AddHistory(ctx context.Context, h *gov.History) error {
+	collection := dmongo.GetClient().GetDB().Collection(model.CollectionGovHistory)
+	for {
+		h.Version = 1
+		filter := mutil.NewBasicFilter(ctx)
+		filter[model.ColumnPolicyID] = h.PolicyID
+		result := collection.FindOne(ctx, filter, options.FindOne().SetSort(bson.M{model.ColumnVersion: -1}))
+		if result.Err() != nil && result.Err() != mongo.ErrNoDocuments {
+			return result.Err()
+		}
+		if result.Err() == nil {
+			var last model.GovHistory
+			if err := result.Decode(&last); err != nil {
+				return err
+			}
+			h.Version = last.Version + 1
+		}
+		b, err := json.Marshal(h)
+		if err != nil {
+			log.Error("policy history is invalid", err)
+			return err
+		}
+		_, err = collection.InsertOne(ctx, &model.GovHistory{
+			Domain:   util.ParseDomain(ctx),
+			Project:  util.ParseProject(ctx),
+			PolicyID: h.PolicyID,
+			Version:  h.Version,
+			History:  string(b),
+		})
+		// the version may be taken by the concurrent change, retry with the next one
+		if mdao.IsDuplicateKey(err) {
+			continue
+		}
+		if err != nil {
+			log.Error("can not save policy history "+h.PolicyID, err)
+		}
+		return err
+	}
+}
+
+func (dao *GovDAO) ListHistory(ctx context.Context, policyID string) ([]*gov.History, error) {
+	filter := mutil.NewBasicFilter(ctx)
+	filter[model.ColumnPolicyID] = policyID
+	cursor, err := dmongo.GetClient().GetDB().Collection(model.CollectionGovHistory).Find(ctx, filter,
+		options.Find().SetSort(bson.M{model.ColumnVersion: 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	list := make([]*gov.History, 0)
+	for cursor.Next(ctx) {
+		var doc model.GovHistory
+		if err = cursor.Decode(&doc); err != nil {
+			log.Error("failed to decode policy history", err)
+			continue
+		}
+		h := &gov.History{}
+		if err = json.Unmarshal([]byte(doc.History), h); err != nil {
+			log.Error("policy history format invalid", err)
+			continue
+		}
+		list = append(list, h)
+	}
+	return list, nil
+}
+
+func (dao *GovDAO) GetHistory(ctx context.Context, policyID string, version int64) (*gov.History, error) {
+	filter := mutil.NewBasicFilter(ctx)
+	filter[model.ColumnPolicyID] = policyID
+	filter[model.ColumnVersion] = version
+	result := dmongo.GetClient().GetDB().Collection(model.CollectionGovHistory).FindOne(ctx, filter)
+	if result.Err() == mongo.ErrNoDocuments {
+		return nil, govds.ErrHistoryNotExists
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	var doc model.GovHistory
+	if err := result.Decode(&doc); err != nil {
+		return nil, err
+	}
+	h := &gov.History{}
+	if err := json.Unmarshal([]byte(doc.History), h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (dao *GovDAO) PutTemplate(ctx context.Context, t *gov.Template) error {
+	b, err := json.Marshal(t)
+	if err != nil {
+		log.Error("policy template is invalid", err)
+		return err
+	}
+	filter := mutil.NewBasicFilter(ctx, mutil.ID(t.ID))
+	_, err = dmongo.GetClient().GetDB().Collection(model.CollectionGovTemplate).ReplaceOne(ctx, filter, &model.GovTemplate{
+		Domain:   util.ParseDomain(ctx),
+		Project:  util.ParseProject(ctx),
+		ID:       t.ID,
+		Revision: t.Revision,
+		Template: string(b),
+	}, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Error("can not save policy template "+t.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (dao *GovDAO) UpdateTemplate(ctx context.Context, t *gov.Template, revision int64) error {
+	b, err := json.Marshal(t)
+	if err != nil {
+		log.Error("policy template is invalid", err)
+		return err
+	}
+	filter := mutil.NewBasicFilter(ctx, mutil.ID(t.ID))
+	filter[model.ColumnRevision] = revision
+	result, err := dmongo.GetClient().GetDB().Collection(model.CollectionGovTemplate).ReplaceOne(ctx, filter, &model.GovTemplate{
+		Domain:   util.ParseDomain(ctx),
+		Project:  util.ParseProject(ctx),
+		ID:       t.ID,
+		Revision: t.Revision,
+		Template: string(b),
+	})
+	if err != nil {
+		log.Error("can not update policy template "+t.ID, err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		if _, err = dao.GetTemplate(ctx, t.ID); err != nil {
+			return err
+		}
+		return govds.ErrTemplateConflict
+	}
+	return nil
+}
+
+func (dao *GovDAO) GetTemplate(ctx context.Context, id string) (*gov.Template, error) {
+	result := dmongo.GetClient().GetDB().Collection(model.CollectionGovTemplate).FindOne(ctx,
+		mutil.NewBasicFilter(ctx, mutil.ID(id)))
+	if result.Err() == mongo.ErrNoDocuments {
+		return nil, govds.ErrTemplateNotExists
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	var doc model.GovTemplate
+	if err := result.Decode(&doc); err != nil {
+		return nil, err
+	}
+	t := &gov.Template{}
+	if err := json.Unmarshal([]byte(doc.Template), t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (dao *GovDAO) ListTemplates(ctx context.Context) ([]*gov.Template, error) {
+	cursor, err := dmongo.GetClient().GetDB().Collection(model.CollectionGovTemplate).Find(ctx, mutil.NewBasicFilter(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	list := make([]*gov.Template, 0)
+	for cursor.Next(ctx) {
+		var doc model.GovTemplate
+		if err = cursor.Decode(&doc); err != nil {
+			log.Error("failed to decode policy template", err)
+			continue
+		}
+		t := &gov.Template{}
+		if err = json.Unmarshal([]byte(doc.Template), t); err != nil {
+			log.Error("policy template format invalid", err)
+			continue
+		}
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+func (dao *GovDAO) DeleteTemplate(ctx context.Context, id string) error {
+	return dmongo.GetClient().ExecTxn(ctx, func(sessionContext mongo.SessionContext) error {
+		_, err := dmongo.GetClient().GetDB().Collection(model.CollectionGovTemplate).DeleteOne(sessionContext,
+			mutil.NewBasicFilter(ctx, mutil.ID(id)))
+		if err != nil {
+			log.Error("can not delete policy template "+id, err)
+			return err
+		}
+		filter := mutil.NewBasicFilter(ctx)
+		filter[model.ColumnTemplateID] = id
+		_, err = dmongo.GetClient().GetDB().Collection(model.CollectionGovInstance).DeleteMany(sessionContext, filter)
+		if err != nil {
+			log.Error("can not delete instances of policy template "+id, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (dao *GovDAO) PutTemplateInstance(ctx context.Context, i *gov.TemplateInstance) error {
+	b, err := json.Marshal(i)
+	if err != nil {
+		log.Error("policy template instance is invalid", err)
+		return err
+	}
+	filter := mutil.NewBasicFilter(ctx, mutil.ID(i.ID))
+	filter[model.ColumnTemplateID] = i.TemplateID
+	_, err = dmongo.GetClient().GetDB().Collection(model.CollectionGovInstance).ReplaceOne(ctx, filter, &model.GovTemplateInstance{
+		Domain:     util.ParseDomain(ctx),
+		Project:    util.ParseProject(ctx),
+		TemplateID: i.TemplateID,
+		ID:         i.ID,
+		Instance:   string(b),
+	}, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Error("can not save policy template instance "+i.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (dao *GovDAO) ListTemplateInstances(ctx context.Context, templateID string) ([]*gov.TemplateInstance, error) {
+	filter := mutil.NewBasicFilter(ctx)
+	filter[model.ColumnTemplateID] = templateID
+	cursor, err := dmongo.GetClient().GetDB().Collection(model.CollectionGovInstance).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	list := make([]*gov.TemplateInstance, 0)
+	for cursor.Next(ctx) {
+		var doc model.GovTemplateInstance
+		if err = cursor.Decode(&doc); err != nil {
+			log.Error("failed to decode policy template instance", err)
+			continue
+		}
+		i := &gov.TemplateInstance{}
+		if err = json.Unmarshal([]byte(doc.Instance), i); err != nil {
+			log.Error("policy template instance format invalid", err)
+			continue
+		}
+		list = append(list, i)
+	}
+	return list, nil
+}
+
+func incGovRevision(ctx context.Context) (int64, error) {
+	result := dmongo.GetClient().GetDB().Collection(model.CollectionGovRevision).FindOneAndUpdate(ctx,
+		mutil.NewBasicFilter(ctx), bson.M{"$inc": bson.M{model.ColumnRevision: 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After))
+	if result.Err() != nil {
+		log.Error("can not increase the policy revision", result.Err())
+		return 0, result.Err()
+	}
+	var doc model.GovRevision
+	if err := result.Decode(&doc); err != nil {
+		return 0, err
+	}
+	return doc.Revision, nil
+}
+
+// toGovPolicy increases the revision and sets it to p
+func toGovPolicy(ctx context.Context, p *gov.Policy) (*model.GovPolicy, error) {
+	revision, err := incGovRevision(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.Revision = revision
+	b, err := json.Marshal(p)
+	if err != nil {
+		log.Error("policy is invalid", err)
+		return nil, err
+	}
+	return &model.GovPolicy{
+		Domain:   util.ParseDomain(ctx),
+		Project:  util.ParseProject(ctx),
+		ID:       p.ID,
+		Kind:     p.Kind,
+		Revision: revision,
+		Policy:   string(b),
+	}, nil
+}
+
+func fromGovPolicy(doc *model.GovPolicy) (*gov.Policy, error) {
+	p := &gov.Policy{GovernancePolicy: &gov.GovernancePolicy{Selector: gov.Selector{}}}
+	if err := json.Unmarshal([]byte(doc.Policy), p); err != nil {
+		return nil, err
+	}
+	p.Revision = doc.Revision
+	return p, nil
+}
@@ -34,6 +34,13 @@ const (
 	CollectionDomain      = "domain"
 	CollectionProject     = "project"
 	CollectionSync        = "sync"
+	CollectionGovPolicy   = "gov_policy"
+	CollectionGovRevision = "gov_revision"
+	CollectionGovHistory  = "gov_history"
+	CollectionGovTemplate = "gov_template"
+	CollectionGovInstance = "gov_template_instance"
+	CollectionDrain       = "drain"
+	CollectionProbe       = "probe"
 )
 
 const (
@@ -77,6 +84,10 @@ const (
 	ColumnAccountLockStatus    = "status"
 	ColumnAccountLockReleaseAt = "release_at"
 	ColumnKey                  = "key"
+	ColumnKind                 = "kind"
+	ColumnRevision             = "revision"
+	ColumnPolicyID             = "policy_id"
+	ColumnTemplateID           = "template_id"
 )
 
 type Service struct {
@@ -131,3 +142,65 @@ type Project struct {
 	Domain  string `json:"domain,omitempty"`
 	Project string `json:"project,omitempty"`
 }
+
+// GovPolicy saves the json of a governance policy
+type GovPolicy struct {
+	Domain   string `json:"domain,omitempty"`
+	Project  string `json:"project,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Kind     string `json:"kind,omitempty"`
+	Revision int64  `json:"revision,omitempty"`
+	Policy   string `json:"policy,omitempty"`
+}
+
+// GovRevision is the counter increased by every policy change of the domain project
+type GovRevision struct {
+	Domain   string `json:"domain,omitempty"`
+	Project  string `json:"project,omitempty"`
+	Revision int64  `json:"revision,omitempty"`
+}
+
+// GovHistory saves the json of a governance policy history
+type GovHistory struct {
+	Domain   string `json:"domain,omitempty"`
+	Project  string `json:"project,omitempty"`
+	PolicyID string `json:"policyID,omitempty" bson:"policy_id"`
+	Version  int64  `json:"version,omitempty"`
+	History  string `json:"history,omitempty"`
+}
+
+// GovTemplate saves the json of a governance policy template
+type GovTemplate struct {
+	Domain   string `json:"domain,omitempty"`
+	Project  string `json:"project,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Revision int64  `json:"revision,omitempty"`
+	Template string `json:"template,omitempty"`
+}
+
+// GovTemplateInstance saves the json of a governance policy template instance
+type GovTemplateInstance struct {
+	Domain     string `json:"domain,omitempty"`
+	Project    string `json:"project,omitempty"`
+	TemplateID string `json:"templateID,omitempty" bson:"template_id"`
+	ID         string `json:"id,omitempty"`
+	Instance   string `json:"instance,omitempty"`
+}
+
+// Drain saves the json of an instance drain
+type Drain struct {
+	Domain     string `json:"domain,omitempty"`
+	Project    string `json:"project,omitempty"`
+	ServiceID  string `json:"serviceID,omitempty" bson:"service_id"`
+	InstanceID string `json:"instanceID,omitempty" bson:"instance_id"`
+	Drain      string `json:"drain,omitempty"`
+}
+
+// Probe saves the json of an instance probe result
+type Probe struct {
+	Domain     string `json:"domain,omitempty"`
+	Project    string `json:"project,omitempty"`
+	ServiceID  string `json:"serviceID,omitempty" bson:"service_id"`
+	InstanceID string `json:"instanceID,omitempty" bson:"instance_id"`
+	Result     string `json:"result,omitempty"`
+}
@@ -1216,6 +1216,127 @@ func (ds *MetadataManager) UnregisterInstance(ctx context.Context, request *disc
 	return nil
 }
 
+func (ds *MetadataManager) RegisterManyInstances(ctx context.Context, serviceID string,
+	instances []*discovery.MicroServiceInstance) error {
+	remoteIP := util.GetIPFromContext(ctx)
+	domain := util.ParseDomain(ctx)
+	project := util.ParseProject(ctx)
+
+	var (
+		docs       []interface{}
+		registered []*discovery.MicroServiceInstance
+		existed    []*discovery.MicroServiceInstance
+	)
+	for _, instance := range instances {
+		if len(instance.InstanceId) > 0 {
+			resp, err := ds.ExistInstance(ctx, &discovery.MicroServiceInstanceKey{
+				ServiceId:  serviceID,
+				InstanceId: instance.InstanceId,
+			})
+			if err != nil {
+				return err
+			}
+			if resp.Exist {
+				// keep alive after the others are registered
+				existed = append(existed, instance)
+				continue
+			}
+		} else {
+			instance.InstanceId = uuid.Generator().GetInstanceID(ctx)
+		}
+		if _, _, err := preProcessRegister(ctx, instance, false); err != nil {
+			return err
+		}
+		docs = append(docs, model.Instance{
+			Domain:      domain,
+			Project:     project,
+			RefreshTime: time.Now(),
+			Instance:    instance,
+		})
+		registered = append(registered, instance)
+	}
+	if len(docs) > 0 {
+		if err := insertInstances(ctx, serviceID, docs, registered); err != nil {
+			return err
+		}
+	}
+
+	for _, instance := range existed {
+		needRegister, err := sendHeartbeatInstead(ctx, instance)
+		if err != nil {
+			return err
+		}
+		if needRegister {
+			log.Error(fmt.Sprintf("batch register service[%s] instances failed, instance %s is removed, operator %s",
+				serviceID, instance.InstanceId, remoteIP), nil)
+			return discovery.NewError(discovery.ErrInstanceNotExists,
+				fmt.Sprintf("Instance[%s] is removed.", instance.InstanceId))
+		}
+	}
+	return nil
+}
+
+func insertInstances(ctx context.Context, serviceID string, docs []interface{},
+	registered []*discovery.MicroServiceInstance) error {
+	remoteIP := util.GetIPFromContext(ctx)
+	err := dmongo.GetClient().ExecTxn(ctx, func(sessionContext mongo.SessionContext) error {
+		_, err := dmongo.GetClient().GetDB().Collection(model.CollectionInstance).InsertMany(sessionContext, docs)
+		return err
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("batch register service[%s] %d instances failed, operator %s",
+			serviceID, len(registered), remoteIP), err)
+		if dao.IsDuplicateKey(err) {
+			return discovery.NewError(discovery.ErrInvalidParams, "Duplicate instance id.")
+		}
+		return discovery.NewError(discovery.ErrUnavailableBackend, err.Error())
+	}
+
+	for _, instance := range registered {
+		if err := heartbeat.Instance().CheckInstance(ctx, instance); err != nil {
+			log.Error(fmt.Sprintf("fail to check instance, instance[%s]. operator %s", instance.InstanceId, remoteIP), err)
+		}
+		sendEvent(ctx, csync.CreateAction, datasource.ResourceInstance, &discovery.RegisterInstanceRequest{Instance: instance})
+	}
+	log.Info(fmt.Sprintf("batch register service[%s] %d instances, operator %s", serviceID, len(registered), remoteIP))
+	return nil
+}
+
+func (ds *MetadataManager) UnregisterManyInstances(ctx context.Context, serviceID string, instanceIDs []string) error {
+	remoteIP := util.GetIPFromContext(ctx)
+	if len(instanceIDs) == 0 {
+		return nil
+	}
+
+	filter := mutil.NewBasicFilter(ctx, mutil.InstanceServiceID(serviceID),
+		mutil.InstanceInstanceID(mutil.NewFilter(mutil.In(instanceIDs))))
+	err := dmongo.GetClient().ExecTxn(ctx, func(sessionContext mongo.SessionContext) error {
+		result, err := dmongo.GetClient().GetDB().Collection(model.CollectionInstance).DeleteMany(sessionContext, filter)
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount != int64(len(instanceIDs)) {
+			// abort the txn
+			return discovery.NewError(discovery.ErrInstanceNotExists, "Service instance does not exist.")
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("batch unregister service[%s] instances %v failed, operator %s",
+			serviceID, instanceIDs, remoteIP), err)
+		if _, ok := err.(*errsvc.Error); ok {
+			return err
+		}
+		return discovery.NewError(discovery.ErrInternal, err.Error())
+	}
+	for _, instanceID := range instanceIDs {
+		sendEvent(ctx, csync.DeleteAction, datasource.ResourceInstance,
+			&discovery.UnregisterInstanceRequest{ServiceId: serviceID, InstanceId: instanceID})
+	}
+	log.Info(fmt.Sprintf("batch unregister service[%s] instances %v, operator %s", serviceID, instanceIDs, remoteIP))
+	return nil
+}
+
 func (ds *MetadataManager) SendHeartbeat(ctx context.Context, request *discovery.HeartbeatRequest) error {
 	remoteIP := util.GetIPFromContext(ctx)
 	instanceFlag := util.StringJoin([]string{request.ServiceId, request.InstanceId}, "/")
@@ -1348,6 +1469,9 @@ func (ds *MetadataManager) findSharedServiceInstance(ctx context.Context, reques
 		return nil, discovery.NewError(discovery.ErrInternal, err.Error())
 	}
 	newRev, _ := formatRevision(request.ConsumerServiceId, instances)
+	s := datasource.GetInstanceSelector(ctx)
+	newRev = datasource.SelectorRevision(s, newRev)
+	instances = datasource.FilterInstances(s, instances)
 	if rev == newRev {
 		instances = nil // for gRPC
 	}
@@ -1427,6 +1551,9 @@ func (ds *MetadataManager) findInstance(ctx context.Context, request *discovery.
 		}
 	}
 	newRev, _ := formatRevision(request.ConsumerServiceId, instances)
+	s := datasource.GetInstanceSelector(ctx)
+	newRev = datasource.SelectorRevision(s, newRev)
+	instances = datasource.FilterInstances(s, instances)
 	if rev == newRev {
 		instances = nil // for gRPC
 	}
@@ -1680,3 +1807,39 @@ func (ds *MetadataManager) Statistics(ctx context.Context, withShared bool) (*di
 func (ds *MetadataManager) UpdateManyInstanceStatus(_ context.Context, _ *datasource.MatchPolicy, _ string) error {
 	return nil
 }
+
+func (ds *MetadataManager) ListAllServiceInstances(ctx context.Context) ([]*datasource.ServiceInstances, error) {
+	instances, err := dao.GetInstances(ctx, bson.M{})
+	if err != nil {
+		log.Error("list instances of all domains failed", err)
+		return nil, err
+	}
+	if len(instances) == 0 {
+		return nil, nil
+	}
+	services, err := dao.GetServices(ctx, bson.M{})
+	if err != nil {
+		log.Error("list services of all domains failed", err)
+		return nil, err
+	}
+	list := make(map[string]*datasource.ServiceInstances, len(services))
+	for _, s := range services {
+		list[util.StringJoin([]string{s.Domain, s.Project, s.Service.ServiceId}, "/")] = &datasource.ServiceInstances{
+			Domain:  s.Domain,
+			Project: s.Project,
+			Service: s.Service,
+		}
+	}
+	result := make([]*datasource.ServiceInstances, 0)
+	for _, inst := range instances {
+		si, ok := list[util.StringJoin([]string{inst.Domain, inst.Project, inst.Instance.ServiceId}, "/")]
+		if !ok {
+			continue
+		}
+		if len(si.Instances) == 0 {
+			result = append(result, si)
+		}
+		si.Instances = append(si.Instances, inst.Instance)
+	}
+	return result, nil
+}
@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+
+	dmongo "github.com/go-chassis/cari/db/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource/mongo/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/datasource/probe"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+func init() {
+	probe.Install("mongo", NewProbeDAO)
+}
+
+func NewProbeDAO(_ probe.Options) (probe.DAO, error) {
+	return &ProbeDAO{}, nil
+}
+
+type ProbeDAO struct{}
+
+func (dao *ProbeDAO) PutResult(ctx context.Context, r *probe.Result) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		log.Error("instance probe result is invalid", err)
+		return err
+	}
+	_, err = dmongo.GetClient().GetDB().Collection(model.CollectionProbe).ReplaceOne(ctx,
+		probeFilter(ctx, r.ServiceID, r.InstanceID), &model.Probe{
+			Domain:     util.ParseDomain(ctx),
+			Project:    util.ParseProject(ctx),
+			ServiceID:  r.ServiceID,
+			InstanceID: r.InstanceID,
+			Result:     string(b),
+		}, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Error("can not save instance probe result "+r.InstanceID, err)
+		return err
+	}
+	return nil
+}
+
+func (dao *ProbeDAO) GetResult(ctx context.Context, serviceID, instanceID string) (*probe.Result, error) {
+	result := dmongo.GetClient().GetDB().Collection(model.CollectionProbe).FindOne(ctx,
+		probeFilter(ctx, serviceID, instanceID))
+	if result.Err() == mongo.ErrNoDocuments {
+		return nil, probe.ErrResultNotExists
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	var doc model.Probe
+	if err := result.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return toProbeResult(&doc)
+}
+
+func (dao *ProbeDAO) ListResults(ctx context.Context, serviceID string) ([]*probe.Result, error) {
+	filter := mutil.NewBasicFilter(ctx)
+	filter[model.ColumnServiceID] = serviceID
+	return listProbeResults(ctx, filter)
+}
+
+func (dao *ProbeDAO) ListAllResults(ctx context.Context) ([]*probe.Result, error) {
+	return listProbeResults(ctx, bson.M{})
+}
+
+func (dao *ProbeDAO) DeleteResult(ctx context.Context, serviceID, instanceID string) error {
+	_, err := dmongo.GetClient().GetDB().Collection(model.CollectionProbe).DeleteOne(ctx,
+		probeFilter(ctx, serviceID, instanceID))
+	if err != nil {
+		log.Error("can not delete instance probe result "+instanceID, err)
+		return err
+	}
+	return nil
+}
+
+func listProbeResults(ctx context.Context, filter bson.M) ([]*probe.Result, error) {
+	cursor, err := dmongo.GetClient().GetDB().Collection(model.CollectionProbe).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	list := make([]*probe.Result, 0)
+	for cursor.Next(ctx) {
+		var doc model.Probe
+		if err = cursor.Decode(&doc); err != nil {
+			log.Error("failed to decode instance probe result", err)
+			continue
+		}
+		r, err := toProbeResult(&doc)
+		if err != nil {
+			log.Error("instance probe result format invalid", err)
+			continue
+		}
+		list = append(list, r)
+	}
+	return list, nil
+}
+
+func toProbeResult(doc *model.Probe) (*probe.Result, error) {
+	r := &probe.Result{}
+	if err := json.Unmarshal([]byte(doc.Result), r); err != nil {
+		return nil, err
+	}
+	r.Domain, r.Project = doc.Domain, doc.Project
+	return r, nil
+}
+
+func probeFilter(ctx context.Context, serviceID, instanceID string) bson.M {
+	filter := mutil.NewBasicFilter(ctx)
+	filter[model.ColumnServiceID] = serviceID
+	filter[model.ColumnInstanceID] = instanceID
+	return filter
+}
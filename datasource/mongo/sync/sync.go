@@ -91,6 +91,7 @@ func doTaskOpt(ctx context.Context, action string, resourceType string, resource
 	if syncOpts != nil {
 		task.Opts = syncOpts.Opts
 	}
+	task.Opts = util.SyncRelayOpts(ctx, task.Opts)
 	_, err = mongo.GetClient().GetDB().Collection(CollectionTask).InsertOne(ctx, task)
 	return err
 }
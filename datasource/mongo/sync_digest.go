@@ -0,0 +1,166 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+
+	dmongo "github.com/go-chassis/cari/db/mongo"
+	"github.com/go-chassis/cari/discovery"
+	rbacmodel "github.com/go-chassis/cari/rbac"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/model"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+// ListDigest lists the digests of the resources, schemas are not synchronized
+// in mongo, so the kv resource type is always empty
+func (s *SyncManager) ListDigest(ctx context.Context, resourceType string) ([]*datasource.Digest, error) {
+	switch resourceType {
+	case datasource.ResourceService:
+		return listServiceDigests(ctx)
+	case datasource.ResourceInstance:
+		return listInstanceDigests(ctx)
+	case datasource.ResourceAccount:
+		return listAccountDigests(ctx)
+	case datasource.ResourceRole:
+		return listRoleDigests(ctx)
+	case datasource.ResourceKV:
+		return []*datasource.Digest{}, nil
+	default:
+		return nil, datasource.ErrNotSupportDigest
+	}
+}
+
+func listDigests(ctx context.Context, collection string,
+	decode func(cursor *mongo.Cursor) (*datasource.Digest, error)) ([]*datasource.Digest, error) {
+	cursor, err := dmongo.GetClient().GetDB().Collection(collection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			log.Error("fail to close mongo cursor", err)
+		}
+	}(cursor, ctx)
+
+	digests := make([]*datasource.Digest, 0)
+	for cursor.Next(ctx) {
+		d, err := decode(cursor)
+		if err != nil {
+			log.Error("failed to decode "+collection, err)
+			return nil, err
+		}
+		digests = append(digests, d)
+	}
+	return digests, nil
+}
+
+func listServiceDigests(ctx context.Context) ([]*datasource.Digest, error) {
+	return listDigests(ctx, model.CollectionService, func(cursor *mongo.Cursor) (*datasource.Digest, error) {
+		var tmp model.Service
+		err := cursor.Decode(&tmp)
+		if err != nil {
+			return nil, err
+		}
+		// only properties can be updated, so the digest ignores the other fields
+		content, err := json.Marshal(tmp.Service.Properties)
+		if err != nil {
+			return nil, err
+		}
+		d := datasource.NewDigest(datasource.ResourceService, tmp.Domain, tmp.Project, tmp.Service.ServiceId, content).
+			WithSecondTimestamp(tmp.Service.ModTimestamp)
+		d.Resource = &discovery.CreateServiceRequest{
+			Service: tmp.Service,
+			Tags:    tmp.Tags,
+		}
+		d.Update = &discovery.UpdateServicePropsRequest{
+			ServiceId:  tmp.Service.ServiceId,
+			Properties: tmp.Service.Properties,
+		}
+		return d, nil
+	})
+}
+
+func listInstanceDigests(ctx context.Context) ([]*datasource.Digest, error) {
+	return listDigests(ctx, model.CollectionInstance, func(cursor *mongo.Cursor) (*datasource.Digest, error) {
+		var tmp model.Instance
+		err := cursor.Decode(&tmp)
+		if err != nil {
+			return nil, err
+		}
+		c := *tmp.Instance
+		c.Timestamp, c.ModTimestamp = "", ""
+		content, err := json.Marshal(&c)
+		if err != nil {
+			return nil, err
+		}
+		d := datasource.NewDigest(datasource.ResourceInstance, tmp.Domain, tmp.Project, tmp.Instance.InstanceId, content).
+			WithSecondTimestamp(tmp.Instance.ModTimestamp)
+		d.Resource = &discovery.RegisterInstanceRequest{
+			Instance: tmp.Instance,
+		}
+		d.Update = tmp.Instance
+		return d, nil
+	})
+}
+
+func listAccountDigests(ctx context.Context) ([]*datasource.Digest, error) {
+	return listDigests(ctx, model.CollectionAccount, func(cursor *mongo.Cursor) (*datasource.Digest, error) {
+		var account rbacmodel.Account
+		err := cursor.Decode(&account)
+		if err != nil {
+			return nil, err
+		}
+		c := account
+		c.CreateTime, c.UpdateTime = "", ""
+		content, err := json.Marshal(&c)
+		if err != nil {
+			return nil, err
+		}
+		d := datasource.NewDigest(datasource.ResourceAccount, "", "", account.Name, content).
+			WithSecondTimestamp(account.UpdateTime)
+		d.Resource = &account
+		return d, nil
+	})
+}
+
+func listRoleDigests(ctx context.Context) ([]*datasource.Digest, error) {
+	return listDigests(ctx, model.CollectionRole, func(cursor *mongo.Cursor) (*datasource.Digest, error) {
+		var role rbacmodel.Role
+		err := cursor.Decode(&role)
+		if err != nil {
+			return nil, err
+		}
+		c := role
+		c.CreateTime, c.UpdateTime = "", ""
+		content, err := json.Marshal(&c)
+		if err != nil {
+			return nil, err
+		}
+		d := datasource.NewDigest(datasource.ResourceRole, "", "", role.Name, content).
+			WithSecondTimestamp(role.UpdateTime)
+		d.Resource = &role
+		return d, nil
+	})
+}
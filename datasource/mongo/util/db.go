@@ -202,7 +202,7 @@ func InstanceServiceID(serviceID interface{}) Option {
 	}
 }
 
-func InstanceInstanceID(instanceID string) Option {
+func InstanceInstanceID(instanceID interface{}) Option {
 	return func(filter bson.M) {
 		filter[ConnectWithDot([]string{model.ColumnInstance, model.ColumnInstanceID})] = instanceID
 	}
@@ -29,6 +29,9 @@ const (
 	ExistTypeSchema                   = "schema"
 	DefaultLeaseRenewalInterval int32 = 30
 	DefaultLeaseRetryTimes      int32 = 3
+	// MaxBatchInstances is the max instances in a batch registration, the instances of a service
+	// are registered in one etcd txn which accepts 128 operations, and each instance takes 2
+	MaxBatchInstances = 64
 )
 
 var (
@@ -42,6 +45,14 @@ type MatchPolicy struct {
 	Properties map[string]string `json:"properties,omitempty"`
 }
 
+// ServiceInstances is a service and its instances in the domain project
+type ServiceInstances struct {
+	Domain    string
+	Project   string
+	Service   *pb.MicroService
+	Instances []*pb.MicroServiceInstance
+}
+
 // Attention: request validation must be finished before the following interface being invoked!!!
 // MetadataManager contains the CRUD of cache metadata
 type MetadataManager interface {
@@ -72,6 +83,12 @@ type MetadataManager interface {
 	PutInstanceStatus(ctx context.Context, request *pb.UpdateInstanceStatusRequest) error
 	PutInstanceProperties(ctx context.Context, request *pb.UpdateInstancePropsRequest) error
 	UnregisterInstance(ctx context.Context, request *pb.UnregisterInstanceRequest) error
+	// RegisterManyInstances registers the instances of the service atomically, the instance ids are
+	// generated in place, and the ones already exist are kept alive after the others are registered
+	RegisterManyInstances(ctx context.Context, serviceID string, instances []*pb.MicroServiceInstance) error
+	// UnregisterManyInstances unregisters the instances of the service atomically,
+	// it fails if any of the instances does not exist
+	UnregisterManyInstances(ctx context.Context, serviceID string, instanceIDs []string) error
 	SendHeartbeat(ctx context.Context, request *pb.HeartbeatRequest) error
 	SendManyHeartbeat(ctx context.Context, request *pb.HeartbeatSetRequest) (*pb.HeartbeatSetResponse, error)
 	// ListManyInstances returns instances under the specified domain
@@ -99,4 +116,7 @@ type MetadataManager interface {
 	Statistics(ctx context.Context, withShared bool) (*pb.Statistics, error)
 
 	UpdateManyInstanceStatus(ctx context.Context, match *MatchPolicy, status string) error
+
+	// ListAllServiceInstances lists the services having instances in all domains and projects
+	ListAllServiceInstances(ctx context.Context) ([]*ServiceInstances, error)
 }
@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package probe persists the instance probe results in the datasource of service center
+package probe
+
+import (
+	"context"
+
+	"github.com/go-chassis/cari/discovery"
+)
+
+var ErrResultNotExists = discovery.NewError(discovery.ErrInstanceNotExists, "Instance probe result does not exist.")
+
+// Result is the probe result of an instance
+type Result struct {
+	Domain     string `json:"-"`
+	Project    string `json:"-"`
+	ServiceID  string `json:"serviceId"`
+	InstanceID string `json:"instanceId"`
+	URL        string `json:"url"`
+	Status     string `json:"status"`
+	// Failures is the consecutive failures
+	Failures      int    `json:"failures"`
+	LastError     string `json:"lastError,omitempty"`
+	LastProbeTime int64  `json:"lastProbeTime,omitempty"`
+	// FailingSince is the first failure time of the consecutive failures
+	FailingSince int64 `json:"failingSince,omitempty"`
+	// MarkedDown is true if the instance status is set DOWN by the probe
+	MarkedDown bool `json:"markedDown"`
+}
+
+// DAO manages the probe results of the domain project in context
+type DAO interface {
+	// PutResult creates or replaces the result of the instance
+	PutResult(ctx context.Context, r *Result) error
+	GetResult(ctx context.Context, serviceID, instanceID string) (*Result, error)
+	ListResults(ctx context.Context, serviceID string) ([]*Result, error)
+	// ListAllResults returns the results of all the domain projects
+	ListAllResults(ctx context.Context) ([]*Result, error)
+	DeleteResult(ctx context.Context, serviceID, instanceID string) error
+}
@@ -19,11 +19,62 @@ package datasource
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"strconv"
+	"time"
 )
 
-var ErrSyncAllKeyExists = errors.New("sync all key already exists")
+var (
+	ErrSyncAllKeyExists = errors.New("sync all key already exists")
+	ErrNotSupportDigest = errors.New("resource type does not support digest")
+)
+
+// Digest is the summary of a resource, peers compare the digests to find out the drift
+type Digest struct {
+	ResourceType string
+	Domain       string
+	Project      string
+	// ResourceID is the same as the tombstone resource id of the resource
+	ResourceID string
+	// Hash is the sha256 of the resource content
+	Hash string
+	// Timestamp is the last modified time in nanoseconds, 0 if unknown
+	Timestamp int64
+
+	// Resource is the request to create the resource
+	Resource interface{}
+	// Update is the request to update the resource, nil if Resource can be used to update
+	Update interface{}
+	// Opts is the options of the sync event
+	Opts map[string]string
+}
 
 type SyncManager interface {
 	SyncAll(ctx context.Context) error
+	// ListDigest lists the digests of all the resources of the type in all domains and projects
+	ListDigest(ctx context.Context, resourceType string) ([]*Digest, error)
+}
+
+// NewDigest returns the digest of the resource content
+func NewDigest(resourceType, domain, project, resourceID string, content []byte) *Digest {
+	sum := sha256.Sum256(content)
+	return &Digest{
+		ResourceType: resourceType,
+		Domain:       domain,
+		Project:      project,
+		ResourceID:   resourceID,
+		Hash:         hex.EncodeToString(sum[:]),
+	}
+}
+
+// WithSecondTimestamp sets the timestamp by the modified time in seconds, like ModTimestamp
+func (d *Digest) WithSecondTimestamp(sec string) *Digest {
+	t, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return d
+	}
+	d.Timestamp = t * int64(time.Second)
+	return d
 }
@@ -26,6 +26,7 @@ const (
 	split           = "/"
 	syncer          = "syncer"
 	task            = "task"
+	index           = "index"
 	tombstone       = "tombstone"
 	TombstoneKeyLen = 6
 )
@@ -43,6 +44,12 @@ func TaskKey(domain, project, taskID string, timestamp int64) string {
 	return strings.Join([]string{getSyncRootKey(), domain, project, strTimestamp, taskID}, split)
 }
 
+// TaskIndexKey is the key indexing the task by the id, the value is the task key,
+// it is not under the task root key, so that listing the tasks does not cover it
+func TaskIndexKey(taskID string) string {
+	return strings.Join([]string{split + syncer, index, task, taskID}, split)
+}
+
 func TaskList(domain, project string) string {
 	if len(domain) == 0 {
 		return getSyncRootKey()
@@ -20,6 +20,7 @@ package task
 import (
 	"context"
 	"encoding/json"
+	"strings"
 
 	"github.com/apache/servicecomb-service-center/eventbase/datasource"
 	"github.com/apache/servicecomb-service-center/eventbase/datasource/etcd/key"
@@ -37,11 +38,15 @@ func (d *Dao) Create(ctx context.Context, task *sync.Task) (*sync.Task, error) {
 	if err != nil {
 		return nil, err
 	}
-	ok, err := etcdadpt.InsertBytes(ctx, key.TaskKey(task.Domain, task.Project, task.ID, task.Timestamp), taskBytes)
+	taskKey := key.TaskKey(task.Domain, task.Project, task.ID, task.Timestamp)
+	resp, err := etcdadpt.TxnWithCmp(ctx, []etcdadpt.OpOptions{
+		etcdadpt.OpPut(etcdadpt.WithStrKey(taskKey), etcdadpt.WithValue(taskBytes)),
+		etcdadpt.OpPut(etcdadpt.WithStrKey(key.TaskIndexKey(task.ID)), etcdadpt.WithStrValue(taskKey)),
+	}, etcdadpt.If(etcdadpt.NotExistKey(taskKey)), nil)
 	if err != nil {
 		return nil, err
 	}
-	if !ok {
+	if !resp.Succeeded {
 		return nil, datasource.ErrTaskAlreadyExists
 	}
 	return task, nil
@@ -71,9 +76,11 @@ func (d *Dao) Update(ctx context.Context, task *sync.Task) error {
 }
 
 func (d *Dao) Delete(ctx context.Context, tasks ...*sync.Task) error {
-	delOptions := make([]etcdadpt.OpOptions, len(tasks))
-	for i, task := range tasks {
-		delOptions[i] = etcdadpt.OpDel(etcdadpt.WithStrKey(key.TaskKey(task.Domain, task.Project, task.ID, task.Timestamp)))
+	delOptions := make([]etcdadpt.OpOptions, 0, 2*len(tasks))
+	for _, task := range tasks {
+		delOptions = append(delOptions,
+			etcdadpt.OpDel(etcdadpt.WithStrKey(key.TaskKey(task.Domain, task.Project, task.ID, task.Timestamp))),
+			etcdadpt.OpDel(etcdadpt.WithStrKey(key.TaskIndexKey(task.ID))))
 	}
 	err := etcdadpt.Txn(ctx, delOptions)
 	if err != nil {
@@ -87,6 +94,9 @@ func (d Dao) List(ctx context.Context, options ...datasource.TaskFindOption) ([]
 	for _, o := range options {
 		o(&opts)
 	}
+	if opts.ID != "" {
+		return d.getByID(ctx, opts)
+	}
 	tasks := make([]*sync.Task, 0)
 	kvs, _, err := etcdadpt.List(ctx, key.TaskList(opts.Domain, opts.Project))
 	if err != nil {
@@ -107,7 +117,38 @@ func (d Dao) List(ctx context.Context, options ...datasource.TaskFindOption) ([]
 	return tasks, nil
 }
 
+// getByID gets the task by the key in the index of the id
+func (d Dao) getByID(ctx context.Context, opts datasource.TaskFindOptions) ([]*sync.Task, error) {
+	tasks := make([]*sync.Task, 0)
+	index, err := etcdadpt.Get(ctx, key.TaskIndexKey(opts.ID))
+	if err != nil {
+		return tasks, err
+	}
+	if index == nil || !strings.HasPrefix(string(index.Value), key.TaskList(opts.Domain, opts.Project)) {
+		return tasks, nil
+	}
+	kv, err := etcdadpt.Get(ctx, string(index.Value))
+	if err != nil {
+		return tasks, err
+	}
+	if kv == nil {
+		return tasks, nil
+	}
+	task := sync.Task{}
+	if err := json.Unmarshal(kv.Value, &task); err != nil {
+		datasource.Logger().Error("unmarshal task failed", openlog.WithErr(err))
+		return tasks, nil
+	}
+	if filterMatch(&task, opts) {
+		tasks = append(tasks, &task)
+	}
+	return tasks, nil
+}
+
 func filterMatch(task *sync.Task, options datasource.TaskFindOptions) bool {
+	if options.ID != "" && task.ID != options.ID {
+		return false
+	}
 	if options.Action != "" && task.Action != options.Action {
 		return false
 	}
@@ -87,6 +87,9 @@ func (d *Dao) List(ctx context.Context, options ...datasource.TaskFindOption) ([
 	}
 	collection := dmongo.GetClient().GetDB().Collection(model.CollectionTask)
 	filter := bson.M{}
+	if opts.ID != "" {
+		filter[model.ColumnID] = opts.ID
+	}
 	if opts.Domain != "" {
 		filter[model.ColumnDomain] = opts.Domain
 	}
@@ -18,6 +18,7 @@
 package datasource
 
 type TaskFindOptions struct {
+	ID           string
 	Domain       string
 	Project      string
 	Action       string
@@ -44,6 +45,13 @@ func NewTombstoneFindOptions() TombstoneFindOptions {
 	return TombstoneFindOptions{}
 }
 
+// WithID find task with id
+func WithID(id string) TaskFindOption {
+	return func(options *TaskFindOptions) {
+		options.ID = id
+	}
+}
+
 // WithDomain find task with domain
 func WithDomain(domain string) TaskFindOption {
 	return func(options *TaskFindOptions) {
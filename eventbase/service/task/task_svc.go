@@ -48,3 +48,15 @@ func List(ctx context.Context, request *model.ListTaskRequest) ([]*sync.Task, er
 	}
 	return tasks, nil
 }
+
+// Get returns the task of the id, nil if not exists
+func Get(ctx context.Context, id string) (*sync.Task, error) {
+	tasks, err := datasource.GetTaskDao().List(ctx, datasource.WithID(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+	return tasks[0], nil
+}
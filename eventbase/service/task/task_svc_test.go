@@ -94,6 +94,20 @@ func TestTaskService(t *testing.T) {
 		})
 	})
 
+	t.Run("get task service", func(t *testing.T) {
+		t.Run("get task by id should return the task", func(t *testing.T) {
+			got, err := task.Get(context.Background(), taskTwo.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, taskTwo.ID, got.ID)
+			assert.Equal(t, sync.UpdateAction, got.Action)
+		})
+		t.Run("get a not exist task should return nil", func(t *testing.T) {
+			got, err := task.Get(context.Background(), "not-exist")
+			assert.Nil(t, err)
+			assert.Nil(t, got)
+		})
+	})
+
 	t.Run("update task service", func(t *testing.T) {
 		t.Run("set the status of the taskOne to done should pass", func(t *testing.T) {
 			taskOne.Status = sync.DoneStatus
@@ -27,6 +27,7 @@ type GovernancePolicy struct {
 	Status     string   `json:"status,omitempty"`
 	CreatTime  int64    `json:"creatTime,omitempty"`
 	UpdateTime int64    `json:"updateTime,omitempty"`
+	Revision   int64    `json:"revision,omitempty"`
 	Selector   Selector `json:"selector,omitempty"`
 }
 
@@ -36,6 +37,12 @@ type DisplayData struct {
 	MatchGroup *Policy   `json:"matchGroup,omitempty"`
 }
 
+// WatchResponse define the policies of a project when its revision changed
+type WatchResponse struct {
+	Revision int64     `json:"revision"`
+	Policies []*Policy `json:"policies"`
+}
+
 // Policy define policy and fault tolerant policy
 type Policy struct {
 	*GovernancePolicy
@@ -0,0 +1,128 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+const (
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionDelete   = "delete"
+	ActionRollback = "rollback"
+)
+
+// History is an immutable version of a policy, saved on every change of the policy
+// Version starts from 1 and increases by one for each change
+// Policy is the policy after the change, or before the deletion
+// RollbackTo is the version restored by a rollback
+type History struct {
+	PolicyID   string    `json:"policyId"`
+	Kind       string    `json:"kind,omitempty"`
+	Version    int64     `json:"version"`
+	Action     string    `json:"action"`
+	Author     string    `json:"author,omitempty"`
+	Timestamp  int64     `json:"timestamp"`
+	Policy     *Policy   `json:"policy,omitempty"`
+	Diff       []*Change `json:"diff,omitempty"`
+	RollbackTo int64     `json:"rollbackTo,omitempty"`
+}
+
+// Change is a changed field of a policy, Path is joined by dots, like spec.rate
+type Change struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// Diff compares the name, status, selector and spec of the policies,
+// a nil policy is treated as an empty one, arrays are compared as a whole
+func Diff(oldPolicy, newPolicy *Policy) []*Change {
+	oldFields, newFields := map[string]interface{}{}, map[string]interface{}{}
+	flatten("", normalize(oldPolicy), oldFields)
+	flatten("", normalize(newPolicy), newFields)
+
+	var changes []*Change
+	for path, o := range oldFields {
+		n, ok := newFields[path]
+		if !ok {
+			changes = append(changes, &Change{Path: path, Old: o})
+			continue
+		}
+		if !reflect.DeepEqual(o, n) {
+			changes = append(changes, &Change{Path: path, Old: o, New: n})
+		}
+	}
+	for path, n := range newFields {
+		if _, ok := oldFields[path]; !ok {
+			changes = append(changes, &Change{Path: path, New: n})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// normalize converts the policy to the json types
+func normalize(p *Policy) map[string]interface{} {
+	m := map[string]interface{}{}
+	if p == nil {
+		return m
+	}
+	view := map[string]interface{}{"spec": p.Spec}
+	if p.GovernancePolicy != nil {
+		view["name"] = p.Name
+		view["status"] = p.Status
+		view["selector"] = p.Selector
+	}
+	b, err := json.Marshal(view)
+	if err != nil {
+		return m
+	}
+	_ = json.Unmarshal(b, &m)
+	return m
+}
+
+func flatten(prefix string, m map[string]interface{}, fields map[string]interface{}) {
+	for k, v := range m {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		switch t := v.(type) {
+		case nil:
+		case string:
+			if t != "" {
+				fields[path] = t
+			}
+		case map[string]interface{}:
+			flatten(path, t, fields)
+		default:
+			fields[path] = t
+		}
+	}
+}
+
+// RollbackRequest is the body of rolling back a policy
+type RollbackRequest struct {
+	Version int64 `json:"version"`
+}
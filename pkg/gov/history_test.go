@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+)
+
+func TestDiff(t *testing.T) {
+	old := &gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{Name: "limit", Status: "enabled", Selector: gov.Selector{"app": "a"}},
+		Spec: map[string]interface{}{
+			"rate":  100,
+			"burst": 10,
+			"match": map[string]interface{}{"apiPath": "/v1", "methods": []string{"GET"}},
+		},
+	}
+	t.Run("compare with nil, should return all fields", func(t *testing.T) {
+		changes := gov.Diff(nil, old)
+		assert.Equal(t, 7, len(changes))
+		assert.Equal(t, "name", changes[0].Path)
+		assert.Equal(t, "limit", changes[0].New)
+		assert.Nil(t, changes[0].Old)
+
+		changes = gov.Diff(old, nil)
+		assert.Equal(t, 7, len(changes))
+		assert.Nil(t, changes[0].New)
+	})
+	t.Run("compare the same policy, should return nothing", func(t *testing.T) {
+		assert.Empty(t, gov.Diff(old, old))
+	})
+	t.Run("compare changed policy, should return the changed fields", func(t *testing.T) {
+		changes := gov.Diff(old, &gov.Policy{
+			GovernancePolicy: &gov.GovernancePolicy{Name: "limit", Status: "disabled", Selector: gov.Selector{"app": "a"}},
+			Spec: map[string]interface{}{
+				"rate":  1000,
+				"match": map[string]interface{}{"apiPath": "/v1", "methods": []string{"GET", "POST"}},
+			},
+		})
+		assert.Equal(t, []*gov.Change{
+			{Path: "spec.burst", Old: float64(10)},
+			{Path: "spec.match.methods", Old: []interface{}{"GET"}, New: []interface{}{"GET", "POST"}},
+			{Path: "spec.rate", Old: float64(100), New: float64(1000)},
+			{Path: "status", Old: "enabled", New: "disabled"},
+		}, changes)
+	})
+}
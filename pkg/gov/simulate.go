@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+// SimulateRequest define a sample request to find the governance applied to it.
+// Drafts are the unpublished match groups and policies, they replace the published
+// ones having the same kind and name, so the result shows the effect of publishing them.
+type SimulateRequest struct {
+	Service string            `json:"service,omitempty"`
+	Path    string            `json:"path"`
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Drafts  []*Policy         `json:"drafts,omitempty"`
+}
+
+// SimulateResponse define the match groups selecting the sample request in precedence order,
+// and the policy of each kind taking effect, which belongs to the first group having the kind
+type SimulateResponse struct {
+	MatchGroups []*MatchedGroup `json:"matchGroups"`
+	Effective   []*Policy       `json:"effective"`
+}
+
+// MatchedGroup define a match group selecting the sample request,
+// Match is the name of the first matched item of the group
+type MatchedGroup struct {
+	Order      int       `json:"order"`
+	Match      string    `json:"match"`
+	MatchGroup *Policy   `json:"matchGroup"`
+	Policies   []*Policy `json:"policies,omitempty"`
+}
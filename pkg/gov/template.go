@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+// Template define a reusable bundle of policies, for example retry, circuit-breaker and bulkhead.
+// It is instantiated against targets as a match group and the policies named after the group.
+// Policies are keyed by the policy kind, like "circuit-breaker",
+// and Revision increases once the match group or policies changed.
+type Template struct {
+	ID          string                            `json:"id,omitempty"`
+	Name        string                            `json:"name"`
+	Description string                            `json:"description,omitempty"`
+	Revision    int64                             `json:"revision,omitempty"`
+	MatchGroup  map[string]interface{}            `json:"matchGroup"`
+	Policies    map[string]map[string]interface{} `json:"policies"`
+	CreateTime  int64                             `json:"createTime,omitempty"`
+	UpdateTime  int64                             `json:"updateTime,omitempty"`
+}
+
+// TemplateTarget define where a template is instantiated,
+// Services is set to the "services" of the match group if it is not empty
+type TemplateTarget struct {
+	App         string `json:"app"`
+	Environment string `json:"environment,omitempty"`
+	Services    string `json:"services,omitempty"`
+}
+
+// TemplateInstance define the match group and policies created from a template revision,
+// Policies maps the kind to the policy id, including the match group
+type TemplateInstance struct {
+	ID         string            `json:"id"`
+	TemplateID string            `json:"templateId"`
+	Revision   int64             `json:"revision"`
+	Target     *TemplateTarget   `json:"target"`
+	GroupName  string            `json:"groupName"`
+	Policies   map[string]string `json:"policies"`
+}
+
+// ApplyTemplateRequest define the targets to instantiate a template against
+type ApplyTemplateRequest struct {
+	Targets []*TemplateTarget `json:"targets"`
+}
+
+// PropagateTemplateRequest define the instances to update to the latest template revision,
+// the instances are never updated implicitly
+type PropagateTemplateRequest struct {
+	Instances []string `json:"instances"`
+}
+
+// TemplateResult define the instances succeeded and the failures of a bulk operation
+type TemplateResult struct {
+	Instances []*TemplateInstance `json:"instances"`
+	Failures  []*TemplateFailure  `json:"failures,omitempty"`
+}
+
+// TemplateFailure define why a target or an instance failed, the created policies of it are removed
+type TemplateFailure struct {
+	Target   *TemplateTarget `json:"target,omitempty"`
+	Instance string          `json:"instance,omitempty"`
+	Message  string          `json:"message"`
+}
+
+// TemplateDiff define the changes to an instance if the latest template revision is propagated,
+// the change path is prefixed by the policy kind
+type TemplateDiff struct {
+	Instance *TemplateInstance `json:"instance"`
+	Changes  []*Change         `json:"changes"`
+}
@@ -41,6 +41,8 @@ type Config struct {
 
 type TLSConfig struct {
 	InsecureSkipVerify bool
+	// Config is used to dial if not nil, the other fields are ignored
+	Config *tls.Config
 }
 
 func GetPickFirstLbConn(config *Config) (*grpc.ClientConn, error) {
@@ -73,9 +75,13 @@ func getLbConn(config *Config, dialOptions func() []grpc.DialOption) (*grpc.Clie
 
 	cred := insecure.NewCredentials()
 	if config.TLSConfig != nil {
-		cred = credentials.NewTLS(&tls.Config{
-			InsecureSkipVerify: config.TLSConfig.InsecureSkipVerify,
-		})
+		cfg := config.TLSConfig.Config
+		if cfg == nil {
+			cfg = &tls.Config{
+				InsecureSkipVerify: config.TLSConfig.InsecureSkipVerify,
+			}
+		}
+		cred = credentials.NewTLS(cfg)
 	}
 
 	opinions := append(dialOptions(),
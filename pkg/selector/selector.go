@@ -0,0 +1,245 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package selector implements a label selector like language to filter
+// the objects by their fields, e.g.
+//
+//	status=UP,properties.env in (prod,gray),!properties.canary
+//
+// The requirements are separated by ',' and all of them must be matched.
+// The supported operators are '=' (or '=='), '!=', 'in', 'notin', 'exists'
+// (or the bare key) and '!' before the key for not exists.
+package selector
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+var (
+	keyRegex = regexp.MustCompile(`^[a-zA-Z0-9_.\-/]+$`)
+	setRegex = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+
+	ErrEmptyRequirement = errors.New("empty requirement")
+)
+
+// Fields returns the value of the key and whether the key exists
+type Fields interface {
+	Get(key string) (string, bool)
+}
+
+// Set is a Fields implementation backed by a map
+type Set map[string]string
+
+func (s Set) Get(key string) (string, bool) {
+	v, ok := s[key]
+	return v, ok
+}
+
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+func (r Requirement) Matches(fields Fields) bool {
+	v, ok := fields.Get(r.Key)
+	switch r.Operator {
+	case Equals:
+		return ok && v == r.Values[0]
+	case NotEquals:
+		return !ok || v != r.Values[0]
+	case In:
+		return ok && contains(r.Values, v)
+	case NotIn:
+		return !ok || !contains(r.Values, v)
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	default:
+		return false
+	}
+}
+
+func (r Requirement) String() string {
+	switch r.Operator {
+	case Equals, NotEquals:
+		return r.Key + string(r.Operator) + r.Values[0]
+	case In, NotIn:
+		return r.Key + " " + string(r.Operator) + " (" + strings.Join(r.Values, ",") + ")"
+	case DoesNotExist:
+		return string(DoesNotExist) + r.Key
+	default:
+		return r.Key
+	}
+}
+
+// Selector is the requirements in canonical order
+type Selector []Requirement
+
+// Empty returns true if the selector matches everything
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+func (s Selector) Matches(fields Fields) bool {
+	for _, r := range s {
+		if !r.Matches(fields) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the canonical expression, the same requirements in
+// any order have the same result
+func (s Selector) String() string {
+	arr := make([]string, 0, len(s))
+	for _, r := range s {
+		arr = append(arr, r.String())
+	}
+	return strings.Join(arr, ",")
+}
+
+// Parse parses the expression to selector, the empty expression returns
+// an empty selector which matches everything
+func Parse(expr string) (Selector, error) {
+	if len(strings.TrimSpace(expr)) == 0 {
+		return nil, nil
+	}
+	var s Selector
+	for _, part := range split(expr) {
+		r, err := parseRequirement(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector '%s': %w", expr, err)
+		}
+		s = append(s, r)
+	}
+	sort.SliceStable(s, func(i, j int) bool {
+		return s[i].String() < s[j].String()
+	})
+	return s, nil
+}
+
+// split splits the expression by the commas outside the parentheses
+func split(expr string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i, c := range expr {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, expr[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, expr[start:])
+}
+
+func parseRequirement(s string) (Requirement, error) {
+	if len(s) == 0 {
+		return Requirement{}, ErrEmptyRequirement
+	}
+	if m := setRegex.FindStringSubmatch(s); m != nil {
+		values, err := parseValues(m[3])
+		if err != nil {
+			return Requirement{}, err
+		}
+		return newRequirement(m[1], Operator(m[2]), values)
+	}
+	if i := strings.Index(s, "!="); i >= 0 {
+		return newRequirement(s[:i], NotEquals, []string{s[i+2:]})
+	}
+	if i := strings.Index(s, "=="); i >= 0 {
+		return newRequirement(s[:i], Equals, []string{s[i+2:]})
+	}
+	if i := strings.Index(s, "="); i >= 0 {
+		return newRequirement(s[:i], Equals, []string{s[i+1:]})
+	}
+	if strings.HasPrefix(s, string(DoesNotExist)) {
+		return newRequirement(s[1:], DoesNotExist, nil)
+	}
+	if key := strings.TrimSuffix(s, " "+string(Exists)); key != s {
+		return newRequirement(key, Exists, nil)
+	}
+	return newRequirement(s, Exists, nil)
+}
+
+func parseValues(s string) ([]string, error) {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if len(v) == 0 {
+			continue
+		}
+		if strings.ContainsAny(v, "()") {
+			return nil, fmt.Errorf("invalid value '%s'", v)
+		}
+		values = append(values, v)
+	}
+	if len(values) == 0 {
+		return nil, errors.New("empty value set")
+	}
+	sort.Strings(values)
+	return values, nil
+}
+
+func newRequirement(key string, op Operator, values []string) (Requirement, error) {
+	key = strings.TrimSpace(key)
+	if !keyRegex.MatchString(key) {
+		return Requirement{}, fmt.Errorf("invalid key '%s'", key)
+	}
+	for i, v := range values {
+		values[i] = strings.TrimSpace(v)
+		if strings.ContainsAny(values[i], "(),!=") {
+			return Requirement{}, fmt.Errorf("invalid value '%s'", values[i])
+		}
+	}
+	return Requirement{Key: key, Operator: op, Values: values}, nil
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector_test
+
+import (
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/selector"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("empty expression should match everything", func(t *testing.T) {
+		s, err := selector.Parse(" ")
+		assert.NoError(t, err)
+		assert.True(t, s.Empty())
+		assert.True(t, s.Matches(selector.Set{}))
+	})
+
+	t.Run("all operators should be parsed", func(t *testing.T) {
+		s, err := selector.Parse("a=1, b==2,c!=3,d in (x, y),e notin (z),f,g exists,!h")
+		assert.NoError(t, err)
+		assert.Equal(t, 8, len(s))
+		assert.Equal(t, "!h,a=1,b=2,c!=3,d in (x,y),e notin (z),f,g", s.String())
+	})
+
+	t.Run("the same requirements in different order should be the same", func(t *testing.T) {
+		s1, err := selector.Parse("a=1,b in (y,x)")
+		assert.NoError(t, err)
+		s2, err := selector.Parse("b in (x,y), a==1")
+		assert.NoError(t, err)
+		assert.Equal(t, s1.String(), s2.String())
+	})
+
+	t.Run("invalid expression should return error", func(t *testing.T) {
+		for _, expr := range []string{"a=1,", "=1", "a b", "a in ()", "a in (x,(y))", "a=b=c", "!", "a!=(b)"} {
+			_, err := selector.Parse(expr)
+			assert.Error(t, err, expr)
+		}
+	})
+}
+
+func TestSelector_Matches(t *testing.T) {
+	fields := selector.Set{"status": "UP", "properties.env": "prod", "properties.empty": ""}
+	cases := map[string]bool{
+		"status=UP":                     true,
+		"status=DOWN":                   false,
+		"status!=DOWN":                  true,
+		"properties.zone!=az1":          true,
+		"properties.env in (gray,prod)": true,
+		"properties.env in (gray)":      false,
+		"properties.zone in (az1)":      false,
+		"properties.env notin (gray)":   true,
+		"properties.zone notin (az1)":   true,
+		"properties.env":                true,
+		"properties.zone exists":        false,
+		"!properties.zone":              true,
+		"!properties.empty":             false,
+		"properties.empty=":             true,
+		"status=UP,properties.env=prod": true,
+		"status=UP,properties.env=gray": false,
+	}
+	for expr, expected := range cases {
+		s, err := selector.Parse(expr)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, expected, s.Matches(fields), expr)
+	}
+}
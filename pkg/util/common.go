@@ -29,6 +29,8 @@ const (
 	CtxRequestRevision  CtxKey = "requestRev"
 	CtxResponseRevision CtxKey = "responseRev"
 	CtxEnableSync       CtxKey = "enableSync"
+	CtxSyncOrigin       CtxKey = "syncOrigin"
+	CtxSyncVisited      CtxKey = "syncVisited"
 )
 
 func GetAppRoot() string {
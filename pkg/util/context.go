@@ -230,6 +230,31 @@ func EnableSync(ctx context.Context) bool {
 	return ctx.Value(CtxEnableSync) == "1"
 }
 
+// WithSyncRelay enables sync to relay the change applied from a peer, origin is the
+// site where the change happened and visited is the sites it has passed through
+func WithSyncRelay(ctx context.Context, origin, visited string) context.Context {
+	ctx = SetContext(ctx, CtxEnableSync, "1")
+	ctx = SetContext(ctx, CtxSyncOrigin, origin)
+	return SetContext(ctx, CtxSyncVisited, visited)
+}
+
+// SyncRelayOpts returns a copy of opts with the origin and visited sites in ctx,
+// opts is returned directly if the ctx is not relaying
+func SyncRelayOpts(ctx context.Context, opts map[string]string) map[string]string {
+	origin, _ := ctx.Value(CtxSyncOrigin).(string)
+	if len(origin) == 0 {
+		return opts
+	}
+	visited, _ := ctx.Value(CtxSyncVisited).(string)
+	relayed := make(map[string]string, len(opts)+2)
+	for k, v := range opts {
+		relayed[k] = v
+	}
+	relayed[string(CtxSyncOrigin)] = origin
+	relayed[string(CtxSyncVisited)] = visited
+	return relayed
+}
+
 func WithRequestRev(ctx context.Context, rev string) context.Context {
 	return SetContext(ctx, CtxRequestRevision, rev)
 }
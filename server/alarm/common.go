@@ -34,6 +34,8 @@ const (
 	IDInternalError           model.ID = "InternalError"
 	IDIncrementPullError      model.ID = "IncrementPullError"
 	IDWebsocketOfScSyncerLost model.ID = "WebsocketOfScSyncerLost"
+	IDSyncConflict            model.ID = "SyncConflict"
+	IDSyncLag                 model.ID = "SyncLag"
 )
 
 const (
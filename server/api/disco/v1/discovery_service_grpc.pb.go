@@ -0,0 +1,418 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.19.3
+// source: discovery_service.proto
+
+package v1
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// DiscoveryServiceClient is the client API for DiscoveryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DiscoveryServiceClient interface {
+	RegisterService(ctx context.Context, in *RegisterServiceRequest, opts ...grpc.CallOption) (*RegisterServiceReply, error)
+	UnregisterService(ctx context.Context, in *UnregisterServiceRequest, opts ...grpc.CallOption) (*UnregisterServiceReply, error)
+	GetService(ctx context.Context, in *GetServiceRequest, opts ...grpc.CallOption) (*Service, error)
+	RegisterInstance(ctx context.Context, in *RegisterInstanceRequest, opts ...grpc.CallOption) (*RegisterInstanceReply, error)
+	UnregisterInstance(ctx context.Context, in *UnregisterInstanceRequest, opts ...grpc.CallOption) (*UnregisterInstanceReply, error)
+	Heartbeat(ctx context.Context, opts ...grpc.CallOption) (DiscoveryService_HeartbeatClient, error)
+	FindInstances(ctx context.Context, in *FindInstancesRequest, opts ...grpc.CallOption) (*FindInstancesReply, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (DiscoveryService_WatchClient, error)
+}
+
+type discoveryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDiscoveryServiceClient(cc grpc.ClientConnInterface) DiscoveryServiceClient {
+	return &discoveryServiceClient{cc}
+}
+
+func (c *discoveryServiceClient) RegisterService(ctx context.Context, in *RegisterServiceRequest, opts ...grpc.CallOption) (*RegisterServiceReply, error) {
+	out := new(RegisterServiceReply)
+	err := c.cc.Invoke(ctx, "/api.discovery.v1.DiscoveryService/RegisterService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *discoveryServiceClient) UnregisterService(ctx context.Context, in *UnregisterServiceRequest, opts ...grpc.CallOption) (*UnregisterServiceReply, error) {
+	out := new(UnregisterServiceReply)
+	err := c.cc.Invoke(ctx, "/api.discovery.v1.DiscoveryService/UnregisterService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *discoveryServiceClient) GetService(ctx context.Context, in *GetServiceRequest, opts ...grpc.CallOption) (*Service, error) {
+	out := new(Service)
+	err := c.cc.Invoke(ctx, "/api.discovery.v1.DiscoveryService/GetService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *discoveryServiceClient) RegisterInstance(ctx context.Context, in *RegisterInstanceRequest, opts ...grpc.CallOption) (*RegisterInstanceReply, error) {
+	out := new(RegisterInstanceReply)
+	err := c.cc.Invoke(ctx, "/api.discovery.v1.DiscoveryService/RegisterInstance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *discoveryServiceClient) UnregisterInstance(ctx context.Context, in *UnregisterInstanceRequest, opts ...grpc.CallOption) (*UnregisterInstanceReply, error) {
+	out := new(UnregisterInstanceReply)
+	err := c.cc.Invoke(ctx, "/api.discovery.v1.DiscoveryService/UnregisterInstance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *discoveryServiceClient) Heartbeat(ctx context.Context, opts ...grpc.CallOption) (DiscoveryService_HeartbeatClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DiscoveryService_ServiceDesc.Streams[0], "/api.discovery.v1.DiscoveryService/Heartbeat", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &discoveryServiceHeartbeatClient{stream}
+	return x, nil
+}
+
+type DiscoveryService_HeartbeatClient interface {
+	Send(*HeartbeatRequest) error
+	Recv() (*HeartbeatReply, error)
+	grpc.ClientStream
+}
+
+type discoveryServiceHeartbeatClient struct {
+	grpc.ClientStream
+}
+
+func (x *discoveryServiceHeartbeatClient) Send(m *HeartbeatRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *discoveryServiceHeartbeatClient) Recv() (*HeartbeatReply, error) {
+	m := new(HeartbeatReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *discoveryServiceClient) FindInstances(ctx context.Context, in *FindInstancesRequest, opts ...grpc.CallOption) (*FindInstancesReply, error) {
+	out := new(FindInstancesReply)
+	err := c.cc.Invoke(ctx, "/api.discovery.v1.DiscoveryService/FindInstances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *discoveryServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (DiscoveryService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DiscoveryService_ServiceDesc.Streams[1], "/api.discovery.v1.DiscoveryService/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &discoveryServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DiscoveryService_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type discoveryServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *discoveryServiceWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DiscoveryServiceServer is the server API for DiscoveryService service.
+// All implementations must embed UnimplementedDiscoveryServiceServer
+// for forward compatibility
+type DiscoveryServiceServer interface {
+	RegisterService(context.Context, *RegisterServiceRequest) (*RegisterServiceReply, error)
+	UnregisterService(context.Context, *UnregisterServiceRequest) (*UnregisterServiceReply, error)
+	GetService(context.Context, *GetServiceRequest) (*Service, error)
+	RegisterInstance(context.Context, *RegisterInstanceRequest) (*RegisterInstanceReply, error)
+	UnregisterInstance(context.Context, *UnregisterInstanceRequest) (*UnregisterInstanceReply, error)
+	Heartbeat(DiscoveryService_HeartbeatServer) error
+	FindInstances(context.Context, *FindInstancesRequest) (*FindInstancesReply, error)
+	Watch(*WatchRequest, DiscoveryService_WatchServer) error
+	mustEmbedUnimplementedDiscoveryServiceServer()
+}
+
+// UnimplementedDiscoveryServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDiscoveryServiceServer struct {
+}
+
+func (UnimplementedDiscoveryServiceServer) RegisterService(context.Context, *RegisterServiceRequest) (*RegisterServiceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterService not implemented")
+}
+func (UnimplementedDiscoveryServiceServer) UnregisterService(context.Context, *UnregisterServiceRequest) (*UnregisterServiceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterService not implemented")
+}
+func (UnimplementedDiscoveryServiceServer) GetService(context.Context, *GetServiceRequest) (*Service, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetService not implemented")
+}
+func (UnimplementedDiscoveryServiceServer) RegisterInstance(context.Context, *RegisterInstanceRequest) (*RegisterInstanceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterInstance not implemented")
+}
+func (UnimplementedDiscoveryServiceServer) UnregisterInstance(context.Context, *UnregisterInstanceRequest) (*UnregisterInstanceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterInstance not implemented")
+}
+func (UnimplementedDiscoveryServiceServer) Heartbeat(DiscoveryService_HeartbeatServer) error {
+	return status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedDiscoveryServiceServer) FindInstances(context.Context, *FindInstancesRequest) (*FindInstancesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindInstances not implemented")
+}
+func (UnimplementedDiscoveryServiceServer) Watch(*WatchRequest, DiscoveryService_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedDiscoveryServiceServer) mustEmbedUnimplementedDiscoveryServiceServer() {}
+
+// UnsafeDiscoveryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DiscoveryServiceServer will
+// result in compilation errors.
+type UnsafeDiscoveryServiceServer interface {
+	mustEmbedUnimplementedDiscoveryServiceServer()
+}
+
+func RegisterDiscoveryServiceServer(s grpc.ServiceRegistrar, srv DiscoveryServiceServer) {
+	s.RegisterService(&DiscoveryService_ServiceDesc, srv)
+}
+
+func _DiscoveryService_RegisterService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiscoveryServiceServer).RegisterService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.discovery.v1.DiscoveryService/RegisterService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiscoveryServiceServer).RegisterService(ctx, req.(*RegisterServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiscoveryService_UnregisterService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnregisterServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiscoveryServiceServer).UnregisterService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.discovery.v1.DiscoveryService/UnregisterService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiscoveryServiceServer).UnregisterService(ctx, req.(*UnregisterServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiscoveryService_GetService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiscoveryServiceServer).GetService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.discovery.v1.DiscoveryService/GetService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiscoveryServiceServer).GetService(ctx, req.(*GetServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiscoveryService_RegisterInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiscoveryServiceServer).RegisterInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.discovery.v1.DiscoveryService/RegisterInstance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiscoveryServiceServer).RegisterInstance(ctx, req.(*RegisterInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiscoveryService_UnregisterInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnregisterInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiscoveryServiceServer).UnregisterInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.discovery.v1.DiscoveryService/UnregisterInstance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiscoveryServiceServer).UnregisterInstance(ctx, req.(*UnregisterInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiscoveryService_Heartbeat_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DiscoveryServiceServer).Heartbeat(&discoveryServiceHeartbeatServer{stream})
+}
+
+type DiscoveryService_HeartbeatServer interface {
+	Send(*HeartbeatReply) error
+	Recv() (*HeartbeatRequest, error)
+	grpc.ServerStream
+}
+
+type discoveryServiceHeartbeatServer struct {
+	grpc.ServerStream
+}
+
+func (x *discoveryServiceHeartbeatServer) Send(m *HeartbeatReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *discoveryServiceHeartbeatServer) Recv() (*HeartbeatRequest, error) {
+	m := new(HeartbeatRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DiscoveryService_FindInstances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindInstancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiscoveryServiceServer).FindInstances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.discovery.v1.DiscoveryService/FindInstances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiscoveryServiceServer).FindInstances(ctx, req.(*FindInstancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiscoveryService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DiscoveryServiceServer).Watch(m, &discoveryServiceWatchServer{stream})
+}
+
+type DiscoveryService_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type discoveryServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *discoveryServiceWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// DiscoveryService_ServiceDesc is the grpc.ServiceDesc for DiscoveryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DiscoveryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.discovery.v1.DiscoveryService",
+	HandlerType: (*DiscoveryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterService",
+			Handler:    _DiscoveryService_RegisterService_Handler,
+		},
+		{
+			MethodName: "UnregisterService",
+			Handler:    _DiscoveryService_UnregisterService_Handler,
+		},
+		{
+			MethodName: "GetService",
+			Handler:    _DiscoveryService_GetService_Handler,
+		},
+		{
+			MethodName: "RegisterInstance",
+			Handler:    _DiscoveryService_RegisterInstance_Handler,
+		},
+		{
+			MethodName: "UnregisterInstance",
+			Handler:    _DiscoveryService_UnregisterInstance_Handler,
+		},
+		{
+			MethodName: "FindInstances",
+			Handler:    _DiscoveryService_FindInstances_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Heartbeat",
+			Handler:       _DiscoveryService_Heartbeat_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _DiscoveryService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "discovery_service.proto",
+}
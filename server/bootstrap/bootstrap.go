@@ -60,7 +60,9 @@ import (
 	_ "github.com/apache/servicecomb-service-center/server/rest/admin"
 
 	//governance
+	_ "github.com/apache/servicecomb-service-center/server/service/grc/istio"
 	_ "github.com/apache/servicecomb-service-center/server/service/grc/kie"
+	_ "github.com/apache/servicecomb-service-center/server/service/grc/native"
 
 	//metrics
 	_ "github.com/apache/servicecomb-service-center/server/rest/metrics"
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-chassis/cari/dlock"
+	"github.com/go-chassis/foundation/gopool"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
+)
+
+const (
+	defaultExpireDrainInterval = 10 * time.Second
+	expireDrainLockKey         = "expire-drain-job"
+)
+
+func init() {
+	if !config.GetBool("registry.instance.drain.enable", false) {
+		return
+	}
+	startExpireDrainJob()
+}
+
+// startExpireDrainJob unregisters the draining instances which grace period is passed
+func startExpireDrainJob() {
+	interval := config.GetDuration("registry.instance.drain.checkInterval", defaultExpireDrainInterval)
+	log.Info(fmt.Sprintf("start expire instance drain job(every %s)", interval))
+	gopool.Go(func(ctx context.Context) {
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				expireDrains(ctx, interval)
+			}
+		}
+	})
+}
+
+func expireDrains(ctx context.Context, interval time.Duration) {
+	if err := dlock.TryLock(expireDrainLockKey, int64(2*interval/time.Second)); err != nil {
+		log.Debug(fmt.Sprintf("try lock %s failed: %s", expireDrainLockKey, err))
+		return
+	}
+	defer func() {
+		if err := dlock.Unlock(expireDrainLockKey); err != nil {
+			log.Error("unlock failed", err)
+		}
+	}()
+
+	if err := discosvc.ExpireDrains(ctx); err != nil {
+		log.Error("expire instance drains failed", err)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
+	"github.com/apache/servicecomb-service-center/server/service/probe"
+)
+
+const (
+	defaultProbeWorkers        = 10
+	defaultProbeTimeout        = 5 * time.Second
+	defaultProbeResyncInterval = 30 * time.Second
+)
+
+func init() {
+	if !config.GetBool("registry.instance.probe.enable", false) {
+		return
+	}
+	startProbeJob()
+}
+
+func startProbeJob() {
+	opts := probe.Options{
+		Workers:         config.GetInt("registry.instance.probe.workers", defaultProbeWorkers),
+		Timeout:         config.GetDuration("registry.instance.probe.timeout", defaultProbeTimeout),
+		ResyncInterval:  config.GetDuration("registry.instance.probe.resyncInterval", defaultProbeResyncInterval),
+		UnregisterAfter: config.GetDuration("registry.instance.probe.unregisterAfter", 0),
+	}
+	log.Info(fmt.Sprintf("start instance probe job, options is %+v", opts))
+	probe.Init(opts, &probeActions{}).Run()
+}
+
+// probeActions changes the probed instances by the discovery service
+type probeActions struct{}
+
+func (a *probeActions) context(ctx context.Context, t *probe.Target) context.Context {
+	return util.SetDomainProject(util.CloneContext(ctx), t.Domain, t.Project)
+}
+
+func (a *probeActions) Heartbeat(ctx context.Context, t *probe.Target) error {
+	return discosvc.SendHeartbeat(a.context(ctx, t), &pb.HeartbeatRequest{
+		ServiceId:  t.ServiceID,
+		InstanceId: t.InstanceID,
+	})
+}
+
+func (a *probeActions) PutStatus(ctx context.Context, t *probe.Target, status string) error {
+	return discosvc.PutInstanceStatus(a.context(ctx, t), &pb.UpdateInstanceStatusRequest{
+		ServiceId:  t.ServiceID,
+		InstanceId: t.InstanceID,
+		Status:     status,
+	})
+}
+
+func (a *probeActions) Unregister(ctx context.Context, t *probe.Target) error {
+	return discosvc.UnregisterInstance(a.context(ctx, t), &pb.UnregisterInstanceRequest{
+		ServiceId:  t.ServiceID,
+		InstanceId: t.InstanceID,
+	})
+}
@@ -57,6 +57,8 @@ var (
 	APIBatchDiscovery = "/v4/:project/registry/instances/action"
 	// APIHeartbeats Apply by request body
 	APIHeartbeats = "/v4/:project/registry/heartbeats"
+	// APIBatchInstances Apply by request body
+	APIBatchInstances = "/v4/:project/registry/instances/batch"
 	// APIGovServicesList Apply by optional service key
 	// - /v4/:project/govern/microservices?appId=xxx
 	// Apply all:
@@ -81,6 +83,7 @@ func init() {
 	RegisterParseFunc(APIServicesList, ByRequestBody)
 	RegisterParseFunc(APIBatchDiscovery, ByDiscoveryRequestBody)
 	RegisterParseFunc(APIHeartbeats, ByHeartbeatRequestBody)
+	RegisterParseFunc(APIBatchInstances, ByInstancesRequestBody)
 }
 
 func ByServiceID(r *http.Request) (*auth.ResourceScope, error) {
@@ -310,3 +313,45 @@ func ByHeartbeatRequestBody(r *http.Request) (*auth.ResourceScope, error) {
 		Verb:   "update",
 	}, nil
 }
+
+// ByInstancesRequestBody parses the batch registration and deregistration request,
+// the instances in both of them have the serviceId
+func ByInstancesRequestBody(r *http.Request) (*auth.ResourceScope, error) {
+	apiPath, ok := r.Context().Value(rest.CtxMatchPattern).(string)
+	if !ok {
+		return nil, ErrCtxMatchPatternNotFound
+	}
+
+	message, err := rest.ReadBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &struct {
+		Instances []*discovery.HeartbeatSetElement `json:"instances"`
+	}{}
+
+	err = json.Unmarshal(message, request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := r.Context()
+	var labels []map[string]string
+	for _, instance := range request.Instances {
+		if instance == nil {
+			continue
+		}
+		ls, err := serviceIDToLabels(ctx, instance.ServiceId)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, ls...)
+	}
+
+	return &auth.ResourceScope{
+		Type:   rbacmodel.GetResource(apiPath),
+		Labels: labels,
+		Verb:   rbac.MethodToVerbs[r.Method],
+	}, nil
+}
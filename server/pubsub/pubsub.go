@@ -21,12 +21,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 
 	pb "github.com/go-chassis/cari/discovery"
 	"github.com/gorilla/websocket"
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/pubsub/sse"
 	"github.com/apache/servicecomb-service-center/server/pubsub/ws"
 )
 
@@ -41,6 +43,23 @@ func Watch(ctx context.Context, in *pb.WatchInstanceRequest, conn *websocket.Con
 	}
 	ws.Watch(ctx, in.SelfServiceId, conn)
 }
+
+// WatchSSE streams the provider instance events by serviceID in Server-Sent Events,
+// lastEventID is the revision the client received last for resuming
+func WatchSSE(ctx context.Context, in *pb.WatchInstanceRequest, lastEventID string, w http.ResponseWriter) error {
+	log.Info(fmt.Sprintf("new a sse watch with service[%s], last event id: %s", in.SelfServiceId, lastEventID))
+	if err := ExistService(ctx, in.SelfServiceId); err != nil {
+		if errors.Is(err, ErrRequiredServiceID) {
+			return pb.NewError(pb.ErrInvalidParams, err.Error())
+		}
+		if errors.Is(err, datasource.ErrServiceNotExists) {
+			return pb.NewError(pb.ErrServiceNotExists, err.Error())
+		}
+		return err
+	}
+	return sse.Watch(ctx, in.SelfServiceId, lastEventID, w)
+}
+
 func ExistService(ctx context.Context, selfServiceID string) error {
 	if len(selfServiceID) == 0 {
 		return ErrRequiredServiceID
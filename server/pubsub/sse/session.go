@@ -0,0 +1,192 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sse
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-chassis/foundation/gopool"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/event"
+	"github.com/apache/servicecomb-service-center/server/metrics"
+)
+
+const (
+	// BacklogSize is the max events buffered for a consumer to resume from
+	BacklogSize = 1000
+	// RetainTime is how long the session keeps subscribing after all the streams closed,
+	// the client reconnects in the time can resume without missing events
+	RetainTime = 2 * time.Minute
+)
+
+var (
+	sessionLock sync.Mutex
+	sessions    = make(map[string]*session)
+)
+
+type bufferedEvent struct {
+	seq int64
+	evt *event.InstanceEvent
+}
+
+// session subscribes the instance events of a consumer and buffers them,
+// it is shared by the streams of the consumer
+type session struct {
+	key        string
+	subscriber *event.InstanceSubscriber
+
+	lock   sync.Mutex
+	seq    int64
+	events []*bufferedEvent
+	// dropped is the revision of the last event dropped from the buffer
+	dropped  int64
+	notify   chan struct{}
+	attached int
+	idle     *time.Timer
+	closed   bool
+}
+
+// attach returns the session of the consumer, created is true if the session is new,
+// the events before it are not buffered
+func attach(domainProject, serviceID string) (s *session, created bool, err error) {
+	key := domainProject + "/" + serviceID
+
+	sessionLock.Lock()
+	defer sessionLock.Unlock()
+	s, ok := sessions[key]
+	if ok {
+		s.lock.Lock()
+		s.attached++
+		if s.idle != nil {
+			s.idle.Stop()
+			s.idle = nil
+		}
+		s.lock.Unlock()
+		return s, false, nil
+	}
+
+	s = &session{
+		key:        key,
+		subscriber: event.NewInstanceSubscriber(serviceID, domainProject),
+		events:     make([]*bufferedEvent, 0, BacklogSize),
+		notify:     make(chan struct{}),
+		attached:   1,
+	}
+	if err := event.Center().AddSubscriber(s.subscriber); err != nil {
+		return nil, false, err
+	}
+	sessions[key] = s
+	gopool.Go(s.receive)
+	return s, true, nil
+}
+
+// detach closes the session if no stream attached in the RetainTime
+func (s *session) detach() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.attached--
+	if s.attached > 0 {
+		return
+	}
+	s.idle = time.AfterFunc(RetainTime, s.close)
+}
+
+func (s *session) close() {
+	sessionLock.Lock()
+	s.lock.Lock()
+	if s.attached > 0 || s.closed {
+		s.lock.Unlock()
+		sessionLock.Unlock()
+		return
+	}
+	s.closed = true
+	delete(sessions, s.key)
+	s.lock.Unlock()
+	sessionLock.Unlock()
+
+	log.Info(fmt.Sprintf("sse session %s is idle, close it", s.key))
+	event.Center().RemoveSubscriber(s.subscriber)
+}
+
+func (s *session) receive(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-s.subscriber.Job:
+			if !ok {
+				return
+			}
+			s.append(evt)
+			metrics.ReportPublishCompleted(evt, nil)
+		}
+	}
+}
+
+func (s *session) append(evt *event.InstanceEvent) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.seq++
+	s.events = append(s.events, &bufferedEvent{seq: s.seq, evt: evt})
+	if over := len(s.events) - BacklogSize; over > 0 {
+		s.dropped = s.events[over-1].evt.Revision
+		s.events = append(s.events[:0:0], s.events[over:]...)
+	}
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
+
+// since returns the buffered events after the seq, and a channel closed when new events come
+func (s *session) since(seq int64) ([]*bufferedEvent, <-chan struct{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	i := 0
+	for i < len(s.events) && s.events[i].seq <= seq {
+		i++
+	}
+	events := make([]*bufferedEvent, len(s.events)-i)
+	copy(events, s.events[i:])
+	return events, s.notify
+}
+
+// resume returns the seq to stream from by the revision the client received last,
+// false if the events after the revision may be missed
+func (s *session) resume(revision int64) (int64, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if revision <= 0 {
+		return s.seq, false
+	}
+	for i, e := range s.events {
+		if e.evt.Revision <= 0 {
+			// the revision is unknown, can not resume
+			return s.seq, false
+		}
+		if e.evt.Revision > revision {
+			if i == 0 && revision < s.dropped {
+				return s.seq, false
+			}
+			return e.seq - 1, true
+		}
+	}
+	return s.seq, true
+}
@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sse
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/server/event"
+)
+
+func newTestSession() *session {
+	return &session{key: "test", notify: make(chan struct{})}
+}
+
+func newTestEvent(rev int64) *event.InstanceEvent {
+	return &event.InstanceEvent{
+		Revision: rev,
+		Response: &pb.WatchInstanceResponse{
+			Response: pb.CreateResponse(pb.ResponseSuccess, "ok"),
+			Action:   string(pb.EVT_UPDATE),
+			Key:      &pb.MicroServiceKey{AppId: "app", ServiceName: "svc", Version: "1.0.0"},
+			Instance: &pb.MicroServiceInstance{ServiceId: "svc", InstanceId: "inst"},
+		},
+	}
+}
+
+func TestSession(t *testing.T) {
+	t.Run("since should return the events after seq and notify new events", func(t *testing.T) {
+		s := newTestSession()
+		s.append(newTestEvent(1))
+		events, notify := s.since(0)
+		assert.Len(t, events, 1)
+
+		s.append(newTestEvent(2))
+		select {
+		case <-notify:
+		default:
+			t.Fatal("should notify the new event")
+		}
+		events, _ = s.since(events[0].seq)
+		assert.Len(t, events, 1)
+		assert.Equal(t, int64(2), events[0].evt.Revision)
+	})
+
+	t.Run("resume should stream from the event after the revision", func(t *testing.T) {
+		s := newTestSession()
+		for rev := int64(10); rev <= 12; rev++ {
+			s.append(newTestEvent(rev))
+		}
+		seq, ok := s.resume(10)
+		assert.True(t, ok)
+		events, _ := s.since(seq)
+		assert.Len(t, events, 2)
+		assert.Equal(t, int64(11), events[0].evt.Revision)
+
+		seq, ok = s.resume(12)
+		assert.True(t, ok)
+		events, _ = s.since(seq)
+		assert.Empty(t, events)
+
+		_, ok = s.resume(0)
+		assert.False(t, ok)
+	})
+
+	t.Run("resume should fail if the events after the revision are dropped", func(t *testing.T) {
+		s := newTestSession()
+		for rev := int64(1); rev <= BacklogSize+1; rev++ {
+			s.append(newTestEvent(rev))
+		}
+		assert.Len(t, s.events, BacklogSize)
+		_, ok := s.resume(0)
+		assert.False(t, ok)
+		_, ok = s.resume(1)
+		assert.True(t, ok)
+		s.append(newTestEvent(BacklogSize + 2))
+		_, ok = s.resume(1)
+		assert.False(t, ok)
+		_, ok = s.resume(-1)
+		assert.False(t, ok)
+	})
+
+	t.Run("resume should fail if the revision is unknown", func(t *testing.T) {
+		s := newTestSession()
+		s.append(newTestEvent(-1))
+		_, ok := s.resume(5)
+		assert.False(t, ok)
+	})
+}
+
+func TestWrite(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := write(w, &bufferedEvent{seq: 1, evt: newTestEvent(5)})
+	assert.NoError(t, err)
+	body := w.Body.String()
+	assert.Contains(t, body, "id: 5\ndata: {")
+	assert.NotContains(t, body, "response")
+
+	w = httptest.NewRecorder()
+	err = write(w, &bufferedEvent{seq: 1, evt: newTestEvent(-1)})
+	assert.NoError(t, err)
+	assert.NotContains(t, w.Body.String(), "id:")
+}
@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/metrics"
+)
+
+const (
+	SSE         = "SSE"
+	ContentType = "text/event-stream"
+	// EventResync tells the client the events may be missed, it should find the instances again
+	EventResync = "resync"
+)
+
+var (
+	// KeepaliveInterval is the interval of ping comments keeping the stream alive
+	KeepaliveInterval = 30 * time.Second
+
+	ErrStreamingUnsupported = errors.New("streaming unsupported")
+)
+
+// Watch streams the provider instance events of the consumer in Server-Sent Events,
+// lastEventID is the revision received last, the stream resumes from it if the events are buffered.
+// The error returned means the stream is not established, the response is not written yet
+func Watch(ctx context.Context, serviceID string, lastEventID string, w http.ResponseWriter) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return ErrStreamingUnsupported
+	}
+
+	domainProject := util.ParseDomainProject(ctx)
+	domain := util.ParseDomain(ctx)
+
+	s, created, err := attach(domainProject, serviceID)
+	if err != nil {
+		return err
+	}
+	defer s.detach()
+
+	metrics.ReportSubscriber(domain, SSE, 1)
+	defer metrics.ReportSubscriber(domain, SSE, -1)
+
+	seq, resumed := s.resume(parseRevision(lastEventID))
+	if created {
+		resumed = false
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", ContentType)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if len(lastEventID) > 0 && !resumed {
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: {}\n\n", EventResync); err != nil {
+			log.Error(fmt.Sprintf("write sse resync to subscriber[%s] failed", s.key), err)
+			return nil
+		}
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(KeepaliveInterval)
+	defer ticker.Stop()
+	for {
+		events, notify := s.since(seq)
+		for _, e := range events {
+			if err := write(w, e); err != nil {
+				log.Error(fmt.Sprintf("write sse event to subscriber[%s] failed", s.key), err)
+				return nil
+			}
+			seq = e.seq
+		}
+		if len(events) > 0 {
+			flusher.Flush()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notify:
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				log.Error(fmt.Sprintf("ping sse subscriber[%s] failed", s.key), err)
+				return nil
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func write(w http.ResponseWriter, e *bufferedEvent) error {
+	resp := *e.evt.Response
+	resp.Response = nil
+	data, err := json.Marshal(&resp)
+	if err != nil {
+		return err
+	}
+	if e.evt.Revision > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", e.evt.Revision); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
+
+func parseRevision(lastEventID string) int64 {
+	rev, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return rev
+}
@@ -22,6 +22,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chassis/go-chassis/v2/pkg/codec"
 
@@ -37,10 +38,24 @@ type InstanceResource struct {
 	//
 }
 
+// GetInstanceResponse is the instance with the heartbeat statistics
+type GetInstanceResponse struct {
+	*pb.GetOneInstanceResponse
+	HeartbeatStats *discosvc.HeartbeatStats `json:"heartbeatStats,omitempty"`
+}
+
+// HeartbeatResponse is replied if the heartbeat interval suggestion is enabled,
+// the instance keeps the interval if no suggestion
+type HeartbeatResponse struct {
+	SuggestedInterval int32 `json:"suggestedInterval,omitempty"`
+}
+
 func (s *InstanceResource) URLPatterns() []rest.Route {
 	return []rest.Route{
 		{Method: http.MethodGet, Path: "/v4/:project/registry/instances", Func: s.FindInstances},
 		{Method: http.MethodPost, Path: "/v4/:project/registry/instances/action", Func: s.InstancesAction},
+		{Method: http.MethodPost, Path: "/v4/:project/registry/instances/batch", Func: s.RegisterManyInstances},
+		{Method: http.MethodDelete, Path: "/v4/:project/registry/instances/batch", Func: s.UnregisterManyInstances},
 		{Method: http.MethodGet, Path: "/v4/:project/registry/microservices/:serviceId/instances", Func: s.ListInstance},
 		{Method: http.MethodGet, Path: "/v4/:project/registry/microservices/:serviceId/instances/:instanceId", Func: s.GetInstance},
 		{Method: http.MethodPost, Path: "/v4/:project/registry/microservices/:serviceId/instances",
@@ -52,6 +67,9 @@ func (s *InstanceResource) URLPatterns() []rest.Route {
 			Func: s.SendHeartbeat},
 		{Method: http.MethodPut, Path: "/v4/:project/registry/heartbeats", Func: s.SendManyHeartbeat},
 		{Method: http.MethodPut, Path: "/v4/:project/registry/instances/status", Func: s.UpdateManyInstanceStatus},
+		{Method: http.MethodPost, Path: "/v4/:project/registry/microservices/:serviceId/instances/:instanceId/drain", Func: s.DrainInstance},
+		{Method: http.MethodGet, Path: "/v4/:project/registry/microservices/:serviceId/instances/:instanceId/drain", Func: s.GetDrainStatus},
+		{Method: http.MethodPost, Path: "/v4/:project/registry/microservices/:serviceId/instances/:instanceId/drain/complete", Func: s.CompleteDrain},
 	}
 }
 func (s *InstanceResource) LegacyRegisterInstance(w http.ResponseWriter, r *http.Request) {
@@ -93,6 +111,10 @@ func (s *InstanceResource) SendHeartbeat(w http.ResponseWriter, r *http.Request)
 		rest.WriteServiceError(w, err)
 		return
 	}
+	if interval := discosvc.SuggestedInterval(r.Context(), request.ServiceId, request.InstanceId); interval > 0 {
+		rest.WriteResponse(w, r, nil, &HeartbeatResponse{SuggestedInterval: interval})
+		return
+	}
 	rest.WriteResponse(w, r, nil, nil)
 }
 
@@ -119,6 +141,52 @@ func (s *InstanceResource) SendManyHeartbeat(w http.ResponseWriter, r *http.Requ
 	rest.WriteResponse(w, r, nil, resp)
 }
 
+func (s *InstanceResource) RegisterManyInstances(w http.ResponseWriter, r *http.Request) {
+	message, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		rest.WriteError(w, pb.ErrInvalidParams, err.Error())
+		return
+	}
+
+	request := &discosvc.RegisterManyInstancesRequest{}
+	err = codec.Decode(message, request)
+	if err != nil {
+		log.Error(fmt.Sprintf("invalid json: %s", util.BytesToStringWithNoCopy(message)), err)
+		rest.WriteError(w, pb.ErrInvalidParams, "Unmarshal error")
+		return
+	}
+	resp, err := discosvc.RegisterManyInstances(r.Context(), request)
+	if err != nil {
+		rest.WriteServiceError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, resp)
+}
+
+func (s *InstanceResource) UnregisterManyInstances(w http.ResponseWriter, r *http.Request) {
+	message, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		rest.WriteError(w, pb.ErrInvalidParams, err.Error())
+		return
+	}
+
+	request := &discosvc.UnregisterManyInstancesRequest{}
+	err = codec.Decode(message, request)
+	if err != nil {
+		log.Error(fmt.Sprintf("invalid json: %s", util.BytesToStringWithNoCopy(message)), err)
+		rest.WriteError(w, pb.ErrInvalidParams, "Unmarshal error")
+		return
+	}
+	resp, err := discosvc.UnregisterManyInstances(r.Context(), request)
+	if err != nil {
+		rest.WriteServiceError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, resp)
+}
+
 func (s *InstanceResource) UnregisterInstance(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	request := &pb.UnregisterInstanceRequest{
@@ -151,9 +219,22 @@ func (s *InstanceResource) FindInstances(w http.ResponseWriter, r *http.Request)
 		Tags:              ids,
 	}
 
+	wait, err := discosvc.ParseWaitTimeout(query.Get("wait"))
+	if err != nil {
+		rest.WriteServiceError(w, err)
+		return
+	}
+
 	ctx := util.SetTargetDomainProject(r.Context(), r.Header.Get("X-Domain-Name"), query.Get(":project"))
+	ctx = discosvc.WithLocality(ctx, query.Get("region"), query.Get("availableZone"))
+	ctx, err = discosvc.WithSelector(ctx, query.Get("selector"))
+	if err != nil {
+		rest.WriteServiceError(w, err)
+		return
+	}
+	ctx = discosvc.WithConsumerHeaders(ctx, r.Header)
 
-	resp, err := discosvc.FindInstances(ctx, request)
+	resp, err := discosvc.WaitInstances(ctx, request, wait)
 	if err != nil {
 		log.Error("find instances failed", err)
 		rest.WriteServiceError(w, err)
@@ -199,7 +280,15 @@ func findManyInstances(w http.ResponseWriter, r *http.Request, body []byte) {
 	}
 	request.ConsumerServiceId = r.Header.Get("X-ConsumerId")
 
-	ctx := util.SetTargetDomainProject(r.Context(), r.Header.Get("X-Domain-Name"), r.URL.Query().Get(":project"))
+	query := r.URL.Query()
+	ctx := util.SetTargetDomainProject(r.Context(), r.Header.Get("X-Domain-Name"), query.Get(":project"))
+	ctx = discosvc.WithLocality(ctx, query.Get("region"), query.Get("availableZone"))
+	ctx, err = discosvc.WithSelector(ctx, query.Get("selector"))
+	if err != nil {
+		rest.WriteServiceError(w, err)
+		return
+	}
+	ctx = discosvc.WithConsumerHeaders(ctx, r.Header)
 	resp, err := discosvc.FindManyInstances(ctx, request)
 	if err != nil {
 		log.Error("find many instances failed", err)
@@ -237,6 +326,10 @@ func (s *InstanceResource) GetInstance(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotModified)
 		return
 	}
+	if stats, ok := discosvc.GetHeartbeatStats(r.Context(), request.ProviderServiceId, request.ProviderInstanceId); ok {
+		rest.WriteResponse(w, r, nil, &GetInstanceResponse{GetOneInstanceResponse: resp, HeartbeatStats: stats})
+		return
+	}
 	rest.WriteResponse(w, r, nil, resp)
 }
 
@@ -286,6 +379,51 @@ func (s *InstanceResource) PutInstanceStatus(w http.ResponseWriter, r *http.Requ
 	rest.WriteResponse(w, r, nil, nil)
 }
 
+func (s *InstanceResource) DrainInstance(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &discosvc.DrainInstanceRequest{
+		ServiceID:  query.Get(":serviceId"),
+		InstanceID: query.Get(":instanceId"),
+	}
+	if v := query.Get("gracePeriod"); len(v) > 0 {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			rest.WriteError(w, pb.ErrInvalidParams, "Invalid gracePeriod.")
+			return
+		}
+		request.GracePeriod = d
+	}
+	resp, err := discosvc.DrainInstance(r.Context(), request)
+	if err != nil {
+		log.Error("drain instance failed", err)
+		rest.WriteServiceError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, resp)
+}
+
+func (s *InstanceResource) GetDrainStatus(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	resp, err := discosvc.GetDrainStatus(r.Context(), query.Get(":serviceId"), query.Get(":instanceId"))
+	if err != nil {
+		log.Error("get instance drain status failed", err)
+		rest.WriteServiceError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, resp)
+}
+
+func (s *InstanceResource) CompleteDrain(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	err := discosvc.CompleteDrain(r.Context(), query.Get(":serviceId"), query.Get(":instanceId"))
+	if err != nil {
+		log.Error("complete instance drain failed", err)
+		rest.WriteServiceError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, nil)
+}
+
 func (s *InstanceResource) PutInstanceProperties(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	message, err := io.ReadAll(r.Body)
@@ -81,9 +81,22 @@ func FiberFindInstances(c *fiber.Ctx) error {
 		Tags:              ids,
 	}
 
+	wait, err := discosvc.ParseWaitTimeout(c.Query("wait"))
+	if err != nil {
+		rest.WriteFiberServiceError(c, err)
+		return nil
+	}
+
 	ctx := util.SetTargetDomainProject(c.UserContext(), c.Get("X-Domain-Name"), c.Params("project"))
+	ctx = discosvc.WithLocality(ctx, c.Query("region"), c.Query("availableZone"))
+	ctx, err = discosvc.WithSelector(ctx, c.Query("selector"))
+	if err != nil {
+		rest.WriteFiberServiceError(c, err)
+		return nil
+	}
+	ctx = discosvc.WithConsumerHeaders(ctx, fiberHeaders(c))
 
-	resp, err := discosvc.FindInstances(ctx, request)
+	resp, err := discosvc.WaitInstances(ctx, request, wait)
 	if err != nil {
 		log.Error("find instances failed", err)
 		rest.WriteFiberServiceError(c, err)
@@ -100,3 +113,11 @@ func FiberFindInstances(c *fiber.Ctx) error {
 	rest.WriteFiberResponse(c, nil, resp)
 	return nil
 }
+
+func fiberHeaders(c *fiber.Ctx) http.Header {
+	headers := make(http.Header)
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		headers.Add(string(key), string(value))
+	})
+	return headers
+}
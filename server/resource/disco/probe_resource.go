@@ -0,0 +1,59 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"net/http"
+
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/service/probe"
+)
+
+type ProbeResource struct {
+	//
+}
+
+type ListProbeResponse struct {
+	Probes []*probe.Result `json:"probes"`
+}
+
+func (s *ProbeResource) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{Method: http.MethodGet, Path: "/v4/:project/registry/microservices/:serviceId/probes", Func: s.ListProbe},
+		{Method: http.MethodGet, Path: "/v4/:project/registry/microservices/:serviceId/instances/:instanceId/probe", Func: s.GetProbe},
+	}
+}
+
+func (s *ProbeResource) ListProbe(w http.ResponseWriter, r *http.Request) {
+	results, err := probe.ListResults(r.Context(), r.URL.Query().Get(":serviceId"))
+	if err != nil {
+		rest.WriteServiceError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, &ListProbeResponse{Probes: results})
+}
+
+func (s *ProbeResource) GetProbe(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	result, err := probe.GetResult(r.Context(), query.Get(":serviceId"), query.Get(":instanceId"))
+	if err != nil {
+		rest.WriteServiceError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, result)
+}
@@ -46,6 +46,9 @@ func (s *ServiceResource) URLPatterns() []rest.Route {
 		{Method: http.MethodGet, Path: "/v4/:project/registry/microservices/:serviceId", Func: s.GetService},
 		{Method: http.MethodPost, Path: "/v4/:project/registry/microservices", Func: s.RegisterService},
 		{Method: http.MethodPut, Path: "/v4/:project/registry/microservices/:serviceId/properties", Func: s.PutServiceProperties},
+		{Method: http.MethodGet, Path: "/v4/:project/registry/microservices/:serviceId/canary", Func: s.GetCanaryRule},
+		{Method: http.MethodPut, Path: "/v4/:project/registry/microservices/:serviceId/canary", Func: s.PutCanaryRule},
+		{Method: http.MethodDelete, Path: "/v4/:project/registry/microservices/:serviceId/canary", Func: s.DeleteCanaryRule},
 		{Method: http.MethodDelete, Path: "/v4/:project/registry/microservices/:serviceId", Func: s.UnregisterService},
 		{Method: http.MethodDelete, Path: "/v4/:project/registry/microservices", Func: s.UnregisterManyService},
 		// tags
@@ -104,6 +107,49 @@ func (s *ServiceResource) PutServiceProperties(w http.ResponseWriter, r *http.Re
 	rest.WriteResponse(w, r, nil, nil)
 }
 
+func (s *ServiceResource) GetCanaryRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := discosvc.GetCanaryRule(r.Context(), r.URL.Query().Get(":serviceId"))
+	if err != nil {
+		log.Error("get canary rule failed", err)
+		rest.WriteServiceError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, rule)
+}
+
+func (s *ServiceResource) PutCanaryRule(w http.ResponseWriter, r *http.Request) {
+	message, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		rest.WriteError(w, pb.ErrInvalidParams, err.Error())
+		return
+	}
+	rule := &discosvc.CanaryRule{}
+	err = json.Unmarshal(message, rule)
+	if err != nil {
+		log.Error(fmt.Sprintf("invalid json: %s", util.BytesToStringWithNoCopy(message)), err)
+		rest.WriteError(w, pb.ErrInvalidParams, err.Error())
+		return
+	}
+	err = discosvc.PutCanaryRule(r.Context(), r.URL.Query().Get(":serviceId"), rule)
+	if err != nil {
+		log.Error("put canary rule failed", err)
+		rest.WriteServiceError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, nil)
+}
+
+func (s *ServiceResource) DeleteCanaryRule(w http.ResponseWriter, r *http.Request) {
+	err := discosvc.DeleteCanaryRule(r.Context(), r.URL.Query().Get(":serviceId"))
+	if err != nil {
+		log.Error("delete canary rule failed", err)
+		rest.WriteServiceError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, nil)
+}
+
 func (s *ServiceResource) UnregisterService(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	serviceID := query.Get(":serviceId")
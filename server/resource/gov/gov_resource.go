@@ -18,10 +18,13 @@
 package gov
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	model "github.com/apache/servicecomb-service-center/pkg/gov"
 	"github.com/apache/servicecomb-service-center/pkg/log"
@@ -40,6 +43,15 @@ const (
 	ProjectKey     = ":project"
 	IDKey          = ":id"
 	DisplayKey     = "display"
+	WatchKey       = "watch"
+	RevisionKey    = "revision"
+	WaitKey        = "wait"
+	VersionKey     = ":version"
+	FromKey        = "from"
+	ToKey          = "to"
+
+	defaultWatchWait = 30 * time.Second
+	maxWatchWait     = 5 * time.Minute
 )
 
 // Create gov config
@@ -117,6 +129,10 @@ func (t *Governance) ListOrDisPlay(w http.ResponseWriter, r *http.Request) {
 	project := query.Get(ProjectKey)
 	app := query.Get(AppKey)
 	environment := query.Get(EnvironmentKey)
+	if kind == WatchKey {
+		t.watch(w, r)
+		return
+	}
 	var body []byte
 	var err error
 	if kind == DisplayKey {
@@ -131,6 +147,50 @@ func (t *Governance) ListOrDisPlay(w http.ResponseWriter, r *http.Request) {
 	rest.WriteResponse(w, r, nil, body)
 }
 
+// watch long polls the policies of the project until its revision differs from
+// the revision in query, or responds 304 when nothing changed in the wait time
+func (t *Governance) watch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	project := query.Get(ProjectKey)
+	app := query.Get(AppKey)
+	environment := query.Get(EnvironmentKey)
+	var (
+		revision int64
+		wait     = defaultWatchWait
+		err      error
+	)
+	if v := query.Get(RevisionKey); v != "" {
+		revision, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			rest.WriteError(w, discovery.ErrInvalidParams, "invalid revision: "+err.Error())
+			return
+		}
+	}
+	if v := query.Get(WaitKey); v != "" {
+		wait, err = time.ParseDuration(v)
+		if err != nil || wait < 0 || wait > maxWatchWait {
+			rest.WriteError(w, discovery.ErrInvalidParams, fmt.Sprintf("wait must be a duration in [0, %s]", maxWatchWait))
+			return
+		}
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), wait)
+	defer cancel()
+	resp, err := grc.Watch(ctx, project, app, environment, revision)
+	if err == grc.ErrWatchNotSupported {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	if err != nil {
+		processError(w, err, "watch gov err")
+		return
+	}
+	if resp == nil {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	rest.WriteResponse(w, r, nil, resp)
+}
+
 // Get gov config
 func (t *Governance) Get(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
@@ -159,6 +219,117 @@ func (t *Governance) Delete(w http.ResponseWriter, r *http.Request) {
 	rest.WriteResponse(w, r, nil, nil)
 }
 
+// Simulate return the match groups and policies applying to a sample request
+func (t *Governance) Simulate(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	project := query.Get(ProjectKey)
+	app := query.Get(AppKey)
+	environment := query.Get(EnvironmentKey)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		processError(w, err, "read body err")
+		return
+	}
+	req := &model.SimulateRequest{}
+	err = json.Unmarshal(body, req)
+	if err != nil {
+		log.Error("json err", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	if req.Path == "" {
+		rest.WriteError(w, discovery.ErrInvalidParams, "path is required")
+		return
+	}
+	resp, err := grc.Simulate(r.Context(), project, app, environment, req)
+	if err != nil {
+		processError(w, err, "simulate gov err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, resp)
+}
+
+// ListHistory return all versions of the gov config
+func (t *Governance) ListHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	id := query.Get(IDKey)
+	project := query.Get(ProjectKey)
+	histories, err := grc.ListHistory(r.Context(), project, id)
+	if err != nil {
+		processError(w, err, "list gov history err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, histories)
+}
+
+// GetHistory return one version of the gov config
+func (t *Governance) GetHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	id := query.Get(IDKey)
+	project := query.Get(ProjectKey)
+	version, err := strconv.ParseInt(query.Get(VersionKey), 10, 64)
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, "invalid version: "+err.Error())
+		return
+	}
+	h, err := grc.GetHistory(r.Context(), project, id, version)
+	if err != nil {
+		processError(w, err, "get gov history err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, h)
+}
+
+// Diff return the changes of the gov config between two versions
+func (t *Governance) Diff(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	id := query.Get(IDKey)
+	project := query.Get(ProjectKey)
+	from, err := strconv.ParseInt(query.Get(FromKey), 10, 64)
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, "invalid from: "+err.Error())
+		return
+	}
+	to, err := strconv.ParseInt(query.Get(ToKey), 10, 64)
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, "invalid to: "+err.Error())
+		return
+	}
+	changes, err := grc.CompareHistory(r.Context(), project, id, from, to)
+	if err != nil {
+		processError(w, err, "diff gov history err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, changes)
+}
+
+// Rollback restore the gov config to a version
+func (t *Governance) Rollback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	kind := query.Get(KindKey)
+	id := query.Get(IDKey)
+	project := query.Get(ProjectKey)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		processError(w, err, "read body err")
+		return
+	}
+	req := &model.RollbackRequest{}
+	err = json.Unmarshal(body, req)
+	if err != nil {
+		log.Error("json err", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	err = grc.Rollback(r.Context(), kind, id, project, req.Version)
+	if err != nil {
+		processError(w, err, "rollback gov err")
+		return
+	}
+	log.Info(fmt.Sprintf("rollback %s/%s to version %d", kind, id, req.Version))
+	rest.WriteResponse(w, r, nil, nil)
+}
+
 func processError(w http.ResponseWriter, err error, msg string) {
 	log.Error(msg, err)
 	rest.WriteServiceError(w, err)
@@ -169,10 +340,15 @@ func (t *Governance) URLPatterns() []rest.Route {
 		//servicecomb.marker.{name}
 		//servicecomb.rateLimiter.{name}
 		//....
+		{Method: http.MethodPost, Path: "/v1/:project/gov/simulate", Func: t.Simulate},
 		{Method: http.MethodPost, Path: "/v1/:project/gov/" + KindKey, Func: t.Create},
 		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey, Func: t.ListOrDisPlay},
 		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey, Func: t.Get},
 		{Method: http.MethodPut, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey, Func: t.Put},
 		{Method: http.MethodDelete, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey, Func: t.Delete},
+		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/history", Func: t.ListHistory},
+		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/history/" + VersionKey, Func: t.GetHistory},
+		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/diff", Func: t.Diff},
+		{Method: http.MethodPost, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/rollback", Func: t.Rollback},
 	}
 }
@@ -85,3 +85,62 @@ func TestGovernance_Create(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
+
+func TestGovernance_Watch(t *testing.T) {
+	rest.RegisterServant(&v1.Governance{})
+
+	t.Run("watch with invalid wait, should failed", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodGet, "/v1/default/gov/watch?wait=1h", nil)
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("watch without native distributor, should failed", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodGet, "/v1/default/gov/watch?revision=1&wait=1s", nil)
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGovernance_History(t *testing.T) {
+	rest.RegisterServant(&v1.Governance{})
+
+	t.Run("get history with invalid version, should failed", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodGet, "/v1/default/gov/mock/id/history/v1", nil)
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("diff without versions, should failed", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodGet, "/v1/default/gov/mock/id/diff", nil)
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("rollback with invalid body, should failed", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodPost, "/v1/default/gov/mock/id/rollback", bytes.NewBufferString("{"))
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGovernance_Simulate(t *testing.T) {
+	rest.RegisterServant(&v1.Governance{})
+
+	t.Run("simulate without path, should failed", func(t *testing.T) {
+		b, _ := json.Marshal(&gov.SimulateRequest{Method: http.MethodGet})
+		r, _ := http.NewRequest(http.MethodPost, "/v1/default/gov/simulate", bytes.NewBuffer(b))
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("simulate a request, should success", func(t *testing.T) {
+		b, _ := json.Marshal(&gov.SimulateRequest{Path: "/orders", Method: http.MethodGet})
+		r, _ := http.NewRequest(http.MethodPost, "/v1/default/gov/simulate", bytes.NewBuffer(b))
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
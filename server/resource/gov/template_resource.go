@@ -0,0 +1,191 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chassis/cari/discovery"
+
+	model "github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/service/grc"
+)
+
+// Template manages the reusable policy bundles and the policies created from them
+type Template struct {
+}
+
+// Create policy template
+func (t *Template) Create(w http.ResponseWriter, r *http.Request) {
+	project := r.URL.Query().Get(ProjectKey)
+	tpl := &model.Template{}
+	if !readJSON(w, r, tpl) {
+		return
+	}
+	tpl, err := grc.CreateTemplate(r.Context(), project, tpl)
+	if err != nil {
+		processError(w, err, "create gov template err")
+		return
+	}
+	log.Info(fmt.Sprintf("created template %s", tpl.ID))
+	rest.WriteResponse(w, r, nil, tpl)
+}
+
+// Put policy template, the instances are not changed
+func (t *Template) Put(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	id := query.Get(IDKey)
+	project := query.Get(ProjectKey)
+	tpl := &model.Template{}
+	if !readJSON(w, r, tpl) {
+		return
+	}
+	tpl, err := grc.UpdateTemplate(r.Context(), project, id, tpl)
+	if err != nil {
+		processError(w, err, "put gov template err")
+		return
+	}
+	log.Info(fmt.Sprintf("update template %s to revision %d", id, tpl.Revision))
+	rest.WriteResponse(w, r, nil, tpl)
+}
+
+// List return all policy templates
+func (t *Template) List(w http.ResponseWriter, r *http.Request) {
+	list, err := grc.ListTemplates(r.Context(), r.URL.Query().Get(ProjectKey))
+	if err != nil {
+		processError(w, err, "list gov template err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, list)
+}
+
+// Get policy template
+func (t *Template) Get(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	tpl, err := grc.GetTemplate(r.Context(), query.Get(ProjectKey), query.Get(IDKey))
+	if err != nil {
+		processError(w, err, "get gov template err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, tpl)
+}
+
+// Delete policy template, the policies created from it are kept
+func (t *Template) Delete(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	err := grc.DeleteTemplate(r.Context(), query.Get(ProjectKey), query.Get(IDKey))
+	if err != nil {
+		processError(w, err, "delete gov template err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, nil)
+}
+
+// Apply create the match group and policies of the template for each target
+func (t *Template) Apply(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &model.ApplyTemplateRequest{}
+	if !readJSON(w, r, req) {
+		return
+	}
+	if len(req.Targets) == 0 {
+		rest.WriteError(w, discovery.ErrInvalidParams, "targets are required")
+		return
+	}
+	result, err := grc.ApplyTemplate(r.Context(), query.Get(ProjectKey), query.Get(IDKey), req.Targets)
+	if err != nil {
+		processError(w, err, "apply gov template err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, result)
+}
+
+// ListInstances return the instances created from the template
+func (t *Template) ListInstances(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	list, err := grc.ListTemplateInstances(r.Context(), query.Get(ProjectKey), query.Get(IDKey))
+	if err != nil {
+		processError(w, err, "list gov template instances err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, list)
+}
+
+// Diff return the changes to the instances not in the latest template revision
+func (t *Template) Diff(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	diffs, err := grc.DiffTemplate(r.Context(), query.Get(ProjectKey), query.Get(IDKey))
+	if err != nil {
+		processError(w, err, "diff gov template err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, diffs)
+}
+
+// Propagate update the chosen instances to the latest template revision
+func (t *Template) Propagate(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &model.PropagateTemplateRequest{}
+	if !readJSON(w, r, req) {
+		return
+	}
+	if len(req.Instances) == 0 {
+		rest.WriteError(w, discovery.ErrInvalidParams, "instances are required")
+		return
+	}
+	result, err := grc.PropagateTemplate(r.Context(), query.Get(ProjectKey), query.Get(IDKey), req.Instances)
+	if err != nil {
+		processError(w, err, "propagate gov template err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, result)
+}
+
+// readJSON decodes the body to v, writes the error and returns false if failed
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		processError(w, err, "read body err")
+		return false
+	}
+	if err = json.Unmarshal(body, v); err != nil {
+		log.Error("json err", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return false
+	}
+	return true
+}
+
+func (t *Template) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{Method: http.MethodPost, Path: "/v1/:project/gov-templates", Func: t.Create},
+		{Method: http.MethodGet, Path: "/v1/:project/gov-templates", Func: t.List},
+		{Method: http.MethodGet, Path: "/v1/:project/gov-templates/" + IDKey, Func: t.Get},
+		{Method: http.MethodPut, Path: "/v1/:project/gov-templates/" + IDKey, Func: t.Put},
+		{Method: http.MethodDelete, Path: "/v1/:project/gov-templates/" + IDKey, Func: t.Delete},
+		{Method: http.MethodPost, Path: "/v1/:project/gov-templates/" + IDKey + "/apply", Func: t.Apply},
+		{Method: http.MethodGet, Path: "/v1/:project/gov-templates/" + IDKey + "/instances", Func: t.ListInstances},
+		{Method: http.MethodGet, Path: "/v1/:project/gov-templates/" + IDKey + "/diff", Func: t.Diff},
+		{Method: http.MethodPost, Path: "/v1/:project/gov-templates/" + IDKey + "/propagate", Func: t.Propagate},
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	v1 "github.com/apache/servicecomb-service-center/server/resource/gov"
+)
+
+func TestTemplate_Create(t *testing.T) {
+	rest.RegisterServant(&v1.Template{})
+
+	t.Run("create template with invalid body, should failed", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodPost, "/v1/default/gov-templates", bytes.NewBufferString("{"))
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("apply template without targets, should failed", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodPost, "/v1/default/gov-templates/id/apply", bytes.NewBufferString("{}"))
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("propagate template without instances, should failed", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodPost, "/v1/default/gov-templates/id/propagate", bytes.NewBufferString("{}"))
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
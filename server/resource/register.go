@@ -38,6 +38,8 @@ func initRouter() {
 	roa.RegisterServant(&disco.ServiceResource{})
 	roa.RegisterServant(&disco.SchemaResource{})
 	roa.RegisterServant(&disco.InstanceResource{})
+	roa.RegisterServant(&disco.ProbeResource{})
 	roa.RegisterServant(&gov.Governance{})
+	roa.RegisterServant(&gov.Template{})
 	roa.RegisterServant(&govern.Resource{})
 }
@@ -19,11 +19,13 @@ package v4
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/server/handler/exception"
 	"github.com/apache/servicecomb-service-center/server/pubsub"
+	"github.com/apache/servicecomb-service-center/server/pubsub/sse"
 	"github.com/apache/servicecomb-service-center/server/service/heartbeat"
 	pb "github.com/go-chassis/cari/discovery"
 	"github.com/gorilla/websocket"
@@ -64,6 +66,10 @@ func upgrade(w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
 }
 
 func (s *WatchService) Watch(w http.ResponseWriter, r *http.Request) {
+	if strings.Contains(r.Header.Get("Accept"), sse.ContentType) {
+		s.WatchSSE(w, r)
+		return
+	}
 	conn, err := upgrade(w, r)
 	if err != nil {
 		return
@@ -76,6 +82,18 @@ func (s *WatchService) Watch(w http.ResponseWriter, r *http.Request) {
 	}, conn)
 }
 
+// WatchSSE is the Server-Sent Events variant of Watch, for the clients can not use websocket
+func (s *WatchService) WatchSSE(w http.ResponseWriter, r *http.Request) {
+	r.Method = "WATCH"
+	err := pubsub.WatchSSE(r.Context(), &pb.WatchInstanceRequest{
+		SelfServiceId: r.URL.Query().Get(":serviceId"),
+	}, r.Header.Get("Last-Event-ID"), w)
+	if err != nil {
+		log.Error("establish sse watch failed", err)
+		rest.WriteServiceError(w, err)
+	}
+}
+
 func (s *WatchService) Heartbeat(w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrade(w, r)
 	if err != nil {
@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/go-chassis/go-chassis/v2/server/restful"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
+	syncsvc "github.com/apache/servicecomb-service-center/server/service/sync"
+)
+
+const (
+	MetadataDomain  = "x-domain-name"
+	MetadataProject = "x-project-name"
+
+	headerConsumerID = "X-ConsumerId"
+)
+
+// route is the REST API equivalent to a grpc call, the RBAC checks are applied by it
+type route struct {
+	Method  string
+	Pattern string
+	// Params is the path params and query of the REST API
+	Params map[string]string
+	// Body is the REST request body
+	Body interface{}
+	// ConsumerID is the header X-ConsumerId of the REST API
+	ConsumerID string
+}
+
+func metadataValue(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// metadataHeaders converts the metadata to the REST request headers
+func metadataHeaders(ctx context.Context) http.Header {
+	md, _ := metadata.FromIncomingContext(ctx)
+	headers := make(http.Header, len(md))
+	for k, vs := range md {
+		for _, v := range vs {
+			headers.Add(k, v)
+		}
+	}
+	return headers
+}
+
+// newContext parses the domain and project from metadata as the context handler of REST API
+func newContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	domain := metadataValue(md, MetadataDomain)
+	if len(domain) == 0 {
+		domain = datasource.RegistryDomain
+	}
+	project := metadataValue(md, MetadataProject)
+	if len(project) == 0 {
+		project = datasource.RegistryProject
+	}
+	ctx = util.SetDomainProject(ctx, domain, project)
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			ctx = util.SetContext(ctx, util.CtxRemoteIP, host)
+		}
+	}
+	return syncsvc.SetContext(ctx)
+}
+
+// authorize applies the same RBAC checks of the REST API to the grpc call,
+// returns the context with the claims of the token
+func authorize(ctx context.Context, rt route) (context.Context, error) {
+	var body []byte
+	if rt.Body != nil {
+		var err error
+		body, err = json.Marshal(rt.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	project := util.ParseProject(ctx)
+	path := strings.ReplaceAll(rt.Pattern, ":project", project)
+	query := url.Values{}
+	query.Set(":project", project)
+	for k, v := range rt.Params {
+		if strings.HasPrefix(k, ":") {
+			path = strings.ReplaceAll(path, k, v)
+		}
+		query.Set(k, v)
+	}
+
+	r, err := http.NewRequestWithContext(ctx, rt.Method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	r.URL.RawQuery = query.Encode()
+	md, _ := metadata.FromIncomingContext(ctx)
+	if token := metadataValue(md, strings.ToLower(restful.HeaderAuth)); len(token) > 0 {
+		r.Header.Set(restful.HeaderAuth, token)
+	}
+	if len(rt.ConsumerID) > 0 {
+		r.Header.Set(headerConsumerID, rt.ConsumerID)
+	}
+	util.SetRequestContext(r, rest.CtxMatchPattern, rt.Pattern)
+
+	if err := auth.Identify(r); err != nil {
+		log.Error(fmt.Sprintf("authenticate grpc call failed, %s %s", rt.Method, rt.Pattern), err)
+		if _, ok := err.(*errsvc.Error); ok {
+			return nil, err
+		}
+		return nil, pb.NewError(rbac.ErrUnauthorized, err.Error())
+	}
+	return r.Context(), nil
+}
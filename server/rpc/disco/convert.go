@@ -0,0 +1,174 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	pb "github.com/go-chassis/cari/discovery"
+
+	v1 "github.com/apache/servicecomb-service-center/server/api/disco/v1"
+)
+
+func toServiceKey(in *pb.MicroServiceKey) *v1.ServiceKey {
+	if in == nil {
+		return nil
+	}
+	return &v1.ServiceKey{
+		Tenant:      in.Tenant,
+		Environment: in.Environment,
+		AppId:       in.AppId,
+		ServiceName: in.ServiceName,
+		Alias:       in.Alias,
+		Version:     in.Version,
+	}
+}
+
+func fromServiceKey(in *v1.ServiceKey) *pb.MicroServiceKey {
+	if in == nil {
+		return nil
+	}
+	return &pb.MicroServiceKey{
+		Tenant:      in.Tenant,
+		Environment: in.Environment,
+		AppId:       in.AppId,
+		ServiceName: in.ServiceName,
+		Alias:       in.Alias,
+		Version:     in.Version,
+	}
+}
+
+func toService(in *pb.MicroService) *v1.Service {
+	if in == nil {
+		return nil
+	}
+	out := &v1.Service{
+		ServiceId:    in.ServiceId,
+		AppId:        in.AppId,
+		ServiceName:  in.ServiceName,
+		Version:      in.Version,
+		Description:  in.Description,
+		Level:        in.Level,
+		Schemas:      in.Schemas,
+		Status:       in.Status,
+		Properties:   in.Properties,
+		Timestamp:    in.Timestamp,
+		Alias:        in.Alias,
+		LbStrategy:   in.LBStrategy,
+		ModTimestamp: in.ModTimestamp,
+		Environment:  in.Environment,
+		RegisterBy:   in.RegisterBy,
+	}
+	for _, p := range in.Paths {
+		out.Paths = append(out.Paths, &v1.ServicePath{Path: p.Path, Property: p.Property})
+	}
+	for _, key := range in.Providers {
+		out.Providers = append(out.Providers, toServiceKey(key))
+	}
+	if in.Framework != nil {
+		out.Framework = &v1.Framework{Name: in.Framework.Name, Version: in.Framework.Version}
+	}
+	return out
+}
+
+func fromService(in *v1.Service) *pb.MicroService {
+	if in == nil {
+		return nil
+	}
+	out := &pb.MicroService{
+		ServiceId:    in.ServiceId,
+		AppId:        in.AppId,
+		ServiceName:  in.ServiceName,
+		Version:      in.Version,
+		Description:  in.Description,
+		Level:        in.Level,
+		Schemas:      in.Schemas,
+		Status:       in.Status,
+		Properties:   in.Properties,
+		Timestamp:    in.Timestamp,
+		Alias:        in.Alias,
+		LBStrategy:   in.LbStrategy,
+		ModTimestamp: in.ModTimestamp,
+		Environment:  in.Environment,
+		RegisterBy:   in.RegisterBy,
+	}
+	for _, p := range in.Paths {
+		out.Paths = append(out.Paths, &pb.ServicePath{Path: p.Path, Property: p.Property})
+	}
+	for _, key := range in.Providers {
+		out.Providers = append(out.Providers, fromServiceKey(key))
+	}
+	if in.Framework != nil {
+		out.Framework = &pb.FrameWork{Name: in.Framework.Name, Version: in.Framework.Version}
+	}
+	return out
+}
+
+func toInstance(in *pb.MicroServiceInstance) *v1.Instance {
+	if in == nil {
+		return nil
+	}
+	out := &v1.Instance{
+		InstanceId:   in.InstanceId,
+		ServiceId:    in.ServiceId,
+		Endpoints:    in.Endpoints,
+		HostName:     in.HostName,
+		Status:       in.Status,
+		Properties:   in.Properties,
+		Timestamp:    in.Timestamp,
+		ModTimestamp: in.ModTimestamp,
+		Version:      in.Version,
+	}
+	if hc := in.HealthCheck; hc != nil {
+		out.HealthCheck = &v1.HealthCheck{Mode: hc.Mode, Port: hc.Port, Interval: hc.Interval, Times: hc.Times, Url: hc.Url}
+	}
+	if dc := in.DataCenterInfo; dc != nil {
+		out.DataCenterInfo = &v1.DataCenterInfo{Name: dc.Name, Region: dc.Region, AvailableZone: dc.AvailableZone}
+	}
+	return out
+}
+
+func fromInstance(in *v1.Instance) *pb.MicroServiceInstance {
+	if in == nil {
+		return nil
+	}
+	out := &pb.MicroServiceInstance{
+		InstanceId:   in.InstanceId,
+		ServiceId:    in.ServiceId,
+		Endpoints:    in.Endpoints,
+		HostName:     in.HostName,
+		Status:       in.Status,
+		Properties:   in.Properties,
+		Timestamp:    in.Timestamp,
+		ModTimestamp: in.ModTimestamp,
+		Version:      in.Version,
+	}
+	if hc := in.HealthCheck; hc != nil {
+		out.HealthCheck = &pb.HealthCheck{Mode: hc.Mode, Port: hc.Port, Interval: hc.Interval, Times: hc.Times, Url: hc.Url}
+	}
+	if dc := in.DataCenterInfo; dc != nil {
+		out.DataCenterInfo = &pb.DataCenterInfo{Name: dc.Name, Region: dc.Region, AvailableZone: dc.AvailableZone}
+	}
+	return out
+}
+
+func toInstances(in []*pb.MicroServiceInstance) []*v1.Instance {
+	out := make([]*v1.Instance, 0, len(in))
+	for _, instance := range in {
+		out = append(out, toInstance(instance))
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConvert(t *testing.T) {
+	t.Run("service should be converted both ways", func(t *testing.T) {
+		service := &pb.MicroService{
+			ServiceId:   "1",
+			AppId:       "app",
+			ServiceName: "svc",
+			Version:     "1.0.0",
+			Environment: "prod",
+			Schemas:     []string{"schema"},
+			Properties:  map[string]string{"a": "b"},
+			Paths:       []*pb.ServicePath{{Path: "/", Property: map[string]string{"c": "d"}}},
+			Providers:   []*pb.MicroServiceKey{{AppId: "app", ServiceName: "provider"}},
+			LBStrategy:  map[string]string{"name": "RoundRobin"},
+			Framework:   &pb.FrameWork{Name: "go-chassis", Version: "2.0"},
+		}
+		assert.Equal(t, service, fromService(toService(service)))
+		assert.Nil(t, toService(nil))
+		assert.Nil(t, fromService(nil))
+	})
+
+	t.Run("instance should be converted both ways", func(t *testing.T) {
+		instance := &pb.MicroServiceInstance{
+			InstanceId:     "1",
+			ServiceId:      "2",
+			Endpoints:      []string{"rest://127.0.0.1:8080"},
+			HostName:       "host",
+			Status:         pb.MSI_UP,
+			Properties:     map[string]string{"a": "b"},
+			HealthCheck:    &pb.HealthCheck{Mode: pb.CHECK_BY_HEARTBEAT, Interval: 30, Times: 3},
+			DataCenterInfo: &pb.DataCenterInfo{Name: "dc", Region: "r", AvailableZone: "az"},
+			Version:        "1.0.0",
+		}
+		assert.Equal(t, instance, fromInstance(toInstance(instance)))
+		assert.Len(t, toInstances([]*pb.MicroServiceInstance{instance}), 1)
+	})
+
+	t.Run("service key should be converted both ways", func(t *testing.T) {
+		key := &pb.MicroServiceKey{Tenant: "default/default", AppId: "app", ServiceName: "svc", Version: "1.0.0"}
+		assert.Equal(t, key, fromServiceKey(toServiceKey(key)))
+	})
+}
+
+func TestToStatusError(t *testing.T) {
+	t.Run("nil error should return nil", func(t *testing.T) {
+		assert.NoError(t, toStatusError(nil))
+	})
+
+	t.Run("discovery error should be converted with the error code", func(t *testing.T) {
+		err := toStatusError(pb.NewError(pb.ErrServiceNotExists, "not exist"))
+		st, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		details := st.Details()
+		assert.Len(t, details, 1)
+		info, ok := details[0].(*errdetails.ErrorInfo)
+		assert.True(t, ok)
+		assert.Equal(t, strconv.Itoa(int(pb.ErrServiceNotExists)), info.Reason)
+		assert.Equal(t, ErrorDomain, info.Domain)
+	})
+
+	t.Run("unknown error should be internal", func(t *testing.T) {
+		st, _ := status.FromError(toStatusError(errors.New("unknown")))
+		assert.Equal(t, codes.Internal, st.Code())
+	})
+
+	t.Run("status error should return directly", func(t *testing.T) {
+		err := status.Error(codes.Canceled, "canceled")
+		assert.Equal(t, err, toStatusError(err))
+	})
+}
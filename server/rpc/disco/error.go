@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"net/http"
+	"strconv"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo detail in grpc status,
+// the reason of it is the error code same as REST API
+const ErrorDomain = "servicecomb.apache.org"
+
+var statusCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.AlreadyExists,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusInternalServerError: codes.Internal,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+}
+
+// toStatusError converts the discovery error to grpc status error
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	e, ok := err.(*errsvc.Error)
+	if !ok {
+		e = pb.NewError(pb.ErrInternal, err.Error())
+	}
+	code, ok := statusCodes[e.StatusCode()]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := status.New(code, e.Error())
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: strconv.Itoa(int(e.Code)),
+		Domain: ErrorDomain,
+	}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// errorCode returns the error code same as REST API
+func errorCode(err error) (int32, string) {
+	if e, ok := err.(*errsvc.Error); ok {
+		return e.Code, e.Error()
+	}
+	return pb.ErrInternal, err.Error()
+}
@@ -0,0 +1,280 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	pb "github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	v1 "github.com/apache/servicecomb-service-center/server/api/disco/v1"
+	"github.com/apache/servicecomb-service-center/server/event"
+	"github.com/apache/servicecomb-service-center/server/metrics"
+	"github.com/apache/servicecomb-service-center/server/pubsub"
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
+)
+
+// the REST APIs equivalent to the grpc calls
+const (
+	APIServices  = "/v4/:project/registry/microservices"
+	APIService   = "/v4/:project/registry/microservices/:serviceId"
+	APIInstances = "/v4/:project/registry/microservices/:serviceId/instances"
+	APIInstance  = "/v4/:project/registry/microservices/:serviceId/instances/:instanceId"
+	APIHeartbeat = "/v4/:project/registry/microservices/:serviceId/instances/:instanceId/heartbeat"
+	APIDiscovery = "/v4/:project/registry/instances"
+	APIWatch     = "/v4/:project/registry/microservices/:serviceId/watcher"
+)
+
+const GRPC = "gRPC"
+
+var ErrRequiredInstance = errors.New("required the instance")
+
+func NewServer() *Server {
+	return &Server{}
+}
+
+// Server is the grpc variant of the discovery REST API,
+// it calls the same discovery service and applies the same RBAC checks
+type Server struct {
+	v1.UnimplementedDiscoveryServiceServer
+}
+
+func (s *Server) RegisterService(ctx context.Context, in *v1.RegisterServiceRequest) (*v1.RegisterServiceReply, error) {
+	request := &pb.CreateServiceRequest{Service: fromService(in.Service)}
+	ctx, err := authorize(newContext(ctx), route{Method: http.MethodPost, Pattern: APIServices, Body: request})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	resp, err := discosvc.RegisterService(ctx, request)
+	if err != nil {
+		log.Error("create service failed", err)
+		return nil, toStatusError(err)
+	}
+	return &v1.RegisterServiceReply{ServiceId: resp.ServiceId}, nil
+}
+
+func (s *Server) UnregisterService(ctx context.Context, in *v1.UnregisterServiceRequest) (*v1.UnregisterServiceReply, error) {
+	ctx, err := authorize(newContext(ctx), route{Method: http.MethodDelete, Pattern: APIService,
+		Params: map[string]string{":serviceId": in.ServiceId, "force": strconv.FormatBool(in.Force)}})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	err = discosvc.UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: in.ServiceId, Force: in.Force})
+	if err != nil {
+		log.Error(fmt.Sprintf("delete service[%s] failed", in.ServiceId), err)
+		return nil, toStatusError(err)
+	}
+	return &v1.UnregisterServiceReply{}, nil
+}
+
+func (s *Server) GetService(ctx context.Context, in *v1.GetServiceRequest) (*v1.Service, error) {
+	ctx, err := authorize(newContext(ctx), route{Method: http.MethodGet, Pattern: APIService,
+		Params: map[string]string{":serviceId": in.ServiceId}})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	service, err := discosvc.GetService(ctx, &pb.GetServiceRequest{ServiceId: in.ServiceId})
+	if err != nil {
+		log.Error(fmt.Sprintf("get service[%s] failed", in.ServiceId), err)
+		return nil, toStatusError(err)
+	}
+	return toService(service), nil
+}
+
+func (s *Server) RegisterInstance(ctx context.Context, in *v1.RegisterInstanceRequest) (*v1.RegisterInstanceReply, error) {
+	if in.Instance == nil {
+		return nil, toStatusError(pb.NewError(pb.ErrInvalidParams, ErrRequiredInstance.Error()))
+	}
+	request := &pb.RegisterInstanceRequest{Instance: fromInstance(in.Instance)}
+	ctx, err := authorize(newContext(ctx), route{Method: http.MethodPost, Pattern: APIInstances,
+		Params: map[string]string{":serviceId": in.Instance.ServiceId}, Body: request})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	resp, err := discosvc.RegisterInstance(ctx, request)
+	if err != nil {
+		log.Error("register instance failed", err)
+		return nil, toStatusError(err)
+	}
+	return &v1.RegisterInstanceReply{InstanceId: resp.InstanceId}, nil
+}
+
+func (s *Server) UnregisterInstance(ctx context.Context, in *v1.UnregisterInstanceRequest) (*v1.UnregisterInstanceReply, error) {
+	ctx, err := authorize(newContext(ctx), route{Method: http.MethodDelete, Pattern: APIInstance,
+		Params: map[string]string{":serviceId": in.ServiceId, ":instanceId": in.InstanceId}})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	err = discosvc.UnregisterInstance(ctx, &pb.UnregisterInstanceRequest{ServiceId: in.ServiceId, InstanceId: in.InstanceId})
+	if err != nil {
+		log.Error("unregister instance failed", err)
+		return nil, toStatusError(err)
+	}
+	return &v1.UnregisterInstanceReply{}, nil
+}
+
+// Heartbeat receives the heartbeats of instances in a stream and replies each of them,
+// the stream keeps open when a heartbeat failed, the client re-registers the instance by the reply code
+func (s *Server) Heartbeat(stream v1.DiscoveryService_HeartbeatServer) error {
+	ctx := newContext(stream.Context())
+	authorized := make(map[string]context.Context)
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		reply := &v1.HeartbeatReply{ServiceId: in.ServiceId, InstanceId: in.InstanceId}
+		interval, err := s.heartbeat(ctx, authorized, in)
+		if err != nil {
+			reply.Code, reply.Message = errorCode(err)
+		}
+		reply.SuggestedInterval = interval
+		if err := stream.Send(reply); err != nil {
+			return err
+		}
+	}
+}
+
+// heartbeat returns the heartbeat interval suggested to the instance
+func (s *Server) heartbeat(ctx context.Context, authorized map[string]context.Context, in *v1.HeartbeatRequest) (int32, error) {
+	key := in.ServiceId + "/" + in.InstanceId
+	instanceCtx, ok := authorized[key]
+	if !ok {
+		var err error
+		instanceCtx, err = authorize(ctx, route{Method: http.MethodPut, Pattern: APIHeartbeat,
+			Params: map[string]string{":serviceId": in.ServiceId, ":instanceId": in.InstanceId}})
+		if err != nil {
+			return 0, err
+		}
+		authorized[key] = instanceCtx
+	}
+	err := discosvc.SendHeartbeat(instanceCtx, &pb.HeartbeatRequest{ServiceId: in.ServiceId, InstanceId: in.InstanceId})
+	if err != nil {
+		log.Error(fmt.Sprintf("instance[%s] heartbeat failed", key), err)
+		return 0, err
+	}
+	return discosvc.SuggestedInterval(instanceCtx, in.ServiceId, in.InstanceId), nil
+}
+
+func (s *Server) FindInstances(ctx context.Context, in *v1.FindInstancesRequest) (*v1.FindInstancesReply, error) {
+	ctx, err := authorize(newContext(ctx), route{Method: http.MethodGet, Pattern: APIDiscovery,
+		Params: map[string]string{
+			"appId":       in.AppId,
+			"serviceName": in.ServiceName,
+			"env":         in.Environment,
+		}, ConsumerID: in.ConsumerServiceId})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	wait, err := discosvc.ParseWaitTimeout(in.Wait)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	ctx = util.SetTargetDomainProject(ctx, util.ParseDomain(ctx), util.ParseProject(ctx))
+	if len(in.Revision) > 0 {
+		ctx = util.WithRequestRev(ctx, in.Revision)
+	}
+	ctx = discosvc.WithLocality(ctx, in.Region, in.AvailableZone)
+	ctx, err = discosvc.WithSelector(ctx, in.Selector)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	ctx = discosvc.WithConsumerHeaders(ctx, metadataHeaders(ctx))
+	resp, err := discosvc.WaitInstances(ctx, &pb.FindInstancesRequest{
+		ConsumerServiceId: in.ConsumerServiceId,
+		AppId:             in.AppId,
+		ServiceName:       in.ServiceName,
+		Alias:             in.ServiceName,
+		Environment:       in.Environment,
+		Tags:              in.Tags,
+	}, wait)
+	if err != nil {
+		log.Error("find instances failed", err)
+		return nil, toStatusError(err)
+	}
+
+	ov, _ := ctx.Value(util.CtxResponseRevision).(string)
+	reply := &v1.FindInstancesReply{Revision: ov}
+	if len(in.Revision) > 0 && in.Revision == ov {
+		reply.NotModified = true
+		return reply, nil
+	}
+	reply.Instances = toInstances(resp.Instances)
+	return reply, nil
+}
+
+// Watch streams the provider instance events of the consumer
+func (s *Server) Watch(in *v1.WatchRequest, stream v1.DiscoveryService_WatchServer) error {
+	ctx, err := authorize(newContext(stream.Context()), route{Method: http.MethodGet, Pattern: APIWatch,
+		Params: map[string]string{":serviceId": in.ConsumerServiceId}})
+	if err != nil {
+		return toStatusError(err)
+	}
+	if err := pubsub.ExistService(ctx, in.ConsumerServiceId); err != nil {
+		if errors.Is(err, pubsub.ErrRequiredServiceID) {
+			err = pb.NewError(pb.ErrInvalidParams, err.Error())
+		}
+		return toStatusError(err)
+	}
+
+	domain, domainProject := util.ParseDomain(ctx), util.ParseDomainProject(ctx)
+	subscriber := event.NewInstanceSubscriber(in.ConsumerServiceId, domainProject)
+	if err := event.Center().AddSubscriber(subscriber); err != nil {
+		return toStatusError(err)
+	}
+	defer event.Center().RemoveSubscriber(subscriber)
+
+	metrics.ReportSubscriber(domain, GRPC, 1)
+	defer metrics.ReportSubscriber(domain, GRPC, -1)
+
+	log.Info(fmt.Sprintf("new a grpc watch with service[%s]", in.ConsumerServiceId))
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt, ok := <-subscriber.Job:
+			if !ok {
+				return nil
+			}
+			err := stream.Send(&v1.WatchEvent{
+				Action:   evt.Response.Action,
+				Key:      toServiceKey(evt.Response.Key),
+				Instance: toInstance(evt.Response.Instance),
+				Revision: evt.Revision,
+			})
+			metrics.ReportPublishCompleted(evt, err)
+			if err != nil {
+				log.Error(fmt.Sprintf("send watch event to service[%s] failed", in.ConsumerServiceId), err)
+				return err
+			}
+		}
+	}
+}
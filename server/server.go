@@ -35,10 +35,12 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/plugin"
 	"github.com/apache/servicecomb-service-center/pkg/signal"
 	"github.com/apache/servicecomb-service-center/server/alarm"
+	discov1 "github.com/apache/servicecomb-service-center/server/api/disco/v1"
 	"github.com/apache/servicecomb-service-center/server/command"
 	"github.com/apache/servicecomb-service-center/server/config"
 	"github.com/apache/servicecomb-service-center/server/event"
 	"github.com/apache/servicecomb-service-center/server/plugin/security/tlsconf"
+	discorpc "github.com/apache/servicecomb-service-center/server/rpc/disco"
 	"github.com/apache/servicecomb-service-center/server/service/grc"
 	"github.com/apache/servicecomb-service-center/server/service/rbac"
 	"github.com/go-chassis/foundation/gopool"
@@ -95,6 +97,11 @@ func (s *ServiceCenterServer) startChassis() {
 			chassis.RegisterSchema("rest", app)
 			log.Info("turbo is enabled")
 		}
+		if config.GetBool("server.grpc.enable", false) {
+			chassis.RegisterSchema("grpc", discorpc.NewServer(),
+				chassisServer.WithRPCServiceDesc(&discov1.DiscoveryService_ServiceDesc))
+			log.Info("grpc discovery api is enabled")
+		}
 		if err := chassis.Run(chassisServer.WithServerMask(mask...)); err != nil {
 			log.Warn(err.Error())
 		}
@@ -139,7 +139,12 @@ func UnregisterInstance(ctx context.Context, in *pb.UnregisterInstanceRequest) e
 		return pb.NewError(pb.ErrInvalidParams, err.Error())
 	}
 
-	return datasource.GetMetadataManager().UnregisterInstance(ctx, in)
+	err := datasource.GetMetadataManager().UnregisterInstance(ctx, in)
+	if err != nil {
+		return err
+	}
+	removeHeartbeatStats(ctx, in.ServiceId, in.InstanceId)
+	return nil
 }
 
 func SendHeartbeat(ctx context.Context, in *pb.HeartbeatRequest) error {
@@ -158,6 +163,7 @@ func SendHeartbeat(ctx context.Context, in *pb.HeartbeatRequest) error {
 		log.Error(fmt.Sprintf("send heartbeat[%s/%s] failed, operator %s", serviceID, instanceID, remoteIP), err)
 		return err
 	}
+	recordHeartbeat(ctx, serviceID, instanceID)
 
 	// append the inner properties
 	err = appendInnerProperties(ctx, serviceID, instanceID)
@@ -211,7 +217,16 @@ func SendManyHeartbeat(ctx context.Context, in *pb.HeartbeatSetRequest) (*pb.Hea
 		log.Error(fmt.Sprintf("invalid heartbeat set request. instances is empty, operator: %s", remoteIP), nil)
 		return nil, pb.NewError(pb.ErrInvalidParams, "Request format invalid.")
 	}
-	return datasource.GetMetadataManager().SendManyHeartbeat(ctx, in)
+	resp, err := datasource.GetMetadataManager().SendManyHeartbeat(ctx, in)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	for _, result := range resp.Instances {
+		if len(result.ErrMessage) == 0 {
+			recordHeartbeat(ctx, result.ServiceId, result.InstanceId)
+		}
+	}
+	return resp, nil
 }
 
 func GetInstance(ctx context.Context, in *pb.GetOneInstanceRequest) (*pb.GetOneInstanceResponse, error) {
@@ -244,7 +259,12 @@ func FindInstances(ctx context.Context, in *pb.FindInstancesRequest) (*pb.FindIn
 		return nil, pb.NewError(pb.ErrInvalidParams, err.Error())
 	}
 
-	return datasource.GetMetadataManager().FindInstances(ctx, in)
+	resp, err := findInstances(ctx, in)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	resp.Instances = SelectInstances(ctx, resp.Instances)
+	return resp, nil
 }
 
 func FindManyInstances(ctx context.Context, request *pb.BatchFindInstancesRequest) (*pb.BatchFindInstancesResponse, error) {
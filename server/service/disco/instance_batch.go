@@ -0,0 +1,245 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	apt "github.com/apache/servicecomb-service-center/server/core"
+	quotasvc "github.com/apache/servicecomb-service-center/server/service/quota"
+	"github.com/apache/servicecomb-service-center/server/service/validator"
+)
+
+type RegisterManyInstancesRequest struct {
+	Instances []*pb.MicroServiceInstance `json:"instances"`
+}
+
+type UnregisterManyInstancesRequest struct {
+	Instances []*pb.HeartbeatSetElement `json:"instances"`
+}
+
+// InstanceResult is the result of an instance in the batch request, the error is
+// the same for the instances of a service, as they are applied atomically
+type InstanceResult struct {
+	ServiceId  string        `json:"serviceId"`
+	InstanceId string        `json:"instanceId,omitempty"`
+	Error      *errsvc.Error `json:"error,omitempty"`
+}
+
+type ManyInstancesResponse struct {
+	Instances []*InstanceResult `json:"instances"`
+}
+
+// instanceGroup is the indexes of the same service instances in the request
+type instanceGroup struct {
+	serviceID string
+	indexes   []int
+}
+
+func groupByService(n int, serviceIDOf func(i int) string) []*instanceGroup {
+	var groups []*instanceGroup
+	m := make(map[string]*instanceGroup)
+	for i := 0; i < n; i++ {
+		serviceID := serviceIDOf(i)
+		g, ok := m[serviceID]
+		if !ok {
+			g = &instanceGroup{serviceID: serviceID}
+			m[serviceID] = g
+			groups = append(groups, g)
+		}
+		g.indexes = append(g.indexes, i)
+	}
+	return groups
+}
+
+func toServiceError(err error) *errsvc.Error {
+	if e, ok := err.(*errsvc.Error); ok {
+		return e
+	}
+	return pb.NewError(pb.ErrInternal, err.Error())
+}
+
+func checkManyInstancesSize(n int) error {
+	if n == 0 {
+		return pb.NewError(pb.ErrInvalidParams, "Required instances.")
+	}
+	if n > datasource.MaxBatchInstances {
+		return pb.NewError(pb.ErrInvalidParams,
+			fmt.Sprintf("The number of instances exceeds the limit %d.", datasource.MaxBatchInstances))
+	}
+	return nil
+}
+
+// RegisterManyInstances registers the instances of many services, the instances of a service are
+// registered atomically, if any of them is invalid, none of the service instances is registered
+func RegisterManyInstances(ctx context.Context, in *RegisterManyInstancesRequest) (*ManyInstancesResponse, error) {
+	remoteIP := util.GetIPFromContext(ctx)
+
+	if err := checkManyInstancesSize(len(in.Instances)); err != nil {
+		log.Error(fmt.Sprintf("batch register instances failed, invalid parameters, operator %s", remoteIP), err)
+		return nil, err
+	}
+	for _, instance := range in.Instances {
+		if instance == nil {
+			return nil, pb.NewError(pb.ErrInvalidParams, "Required instance.")
+		}
+	}
+	if !apt.IsSCInstance(ctx) {
+		if err := checkManyInstancesQuota(ctx, in.Instances); err != nil {
+			log.Error(fmt.Sprintf("batch register instances failed, operator %s", remoteIP), err)
+			return nil, err
+		}
+	}
+
+	resp := &ManyInstancesResponse{Instances: make([]*InstanceResult, len(in.Instances))}
+	groups := groupByService(len(in.Instances), func(i int) string { return in.Instances[i].ServiceId })
+	for _, g := range groups {
+		instances := make([]*pb.MicroServiceInstance, 0, len(g.indexes))
+		for _, i := range g.indexes {
+			instances = append(instances, in.Instances[i])
+		}
+		err := registerServiceInstances(ctx, g.serviceID, instances)
+		for _, i := range g.indexes {
+			result := &InstanceResult{ServiceId: g.serviceID}
+			if err != nil {
+				result.Error = toServiceError(err)
+			} else {
+				result.InstanceId = in.Instances[i].InstanceId
+			}
+			resp.Instances[i] = result
+		}
+	}
+	return resp, nil
+}
+
+// checkManyInstancesQuota applies the quota of the new instances,
+// the ones already exist are kept alive and not charged
+func checkManyInstancesQuota(ctx context.Context, instances []*pb.MicroServiceInstance) error {
+	var size int64
+	for _, instance := range instances {
+		if len(instance.InstanceId) == 0 {
+			size++
+			continue
+		}
+		resp, err := datasource.GetMetadataManager().ExistInstance(ctx, &pb.MicroServiceInstanceKey{
+			ServiceId:  instance.ServiceId,
+			InstanceId: instance.InstanceId,
+		})
+		if err != nil {
+			return err
+		}
+		if !resp.Exist {
+			size++
+		}
+	}
+	if size == 0 {
+		return nil
+	}
+	return quotasvc.ApplyInstance(ctx, size)
+}
+
+func registerServiceInstances(ctx context.Context, serviceID string, instances []*pb.MicroServiceInstance) error {
+	remoteIP := util.GetIPFromContext(ctx)
+	exist := make(map[string]struct{}, len(instances))
+	for _, instance := range instances {
+		if len(instance.InstanceId) > 0 {
+			if _, ok := exist[instance.InstanceId]; ok {
+				return pb.NewError(pb.ErrInvalidParams, fmt.Sprintf("Duplicate instance id %s.", instance.InstanceId))
+			}
+			exist[instance.InstanceId] = struct{}{}
+		}
+		if err := validator.ValidateRegisterInstanceRequest(&pb.RegisterInstanceRequest{Instance: instance}); err != nil {
+			log.Error(fmt.Sprintf("batch register service[%s] instances failed, invalid parameters, operator %s",
+				serviceID, remoteIP), err)
+			return pb.NewError(pb.ErrInvalidParams, err.Error())
+		}
+		if err := populateInstanceDefaultValue(ctx, instance); err != nil {
+			return err
+		}
+	}
+	return datasource.GetMetadataManager().RegisterManyInstances(ctx, serviceID, instances)
+}
+
+// UnregisterManyInstances unregisters the instances of many services, the instances of
+// a service are unregistered atomically
+func UnregisterManyInstances(ctx context.Context, in *UnregisterManyInstancesRequest) (*ManyInstancesResponse, error) {
+	remoteIP := util.GetIPFromContext(ctx)
+
+	if err := checkManyInstancesSize(len(in.Instances)); err != nil {
+		log.Error(fmt.Sprintf("batch unregister instances failed, invalid parameters, operator %s", remoteIP), err)
+		return nil, err
+	}
+	for _, instance := range in.Instances {
+		if instance == nil {
+			return nil, pb.NewError(pb.ErrInvalidParams, "Required instance.")
+		}
+	}
+
+	resp := &ManyInstancesResponse{Instances: make([]*InstanceResult, len(in.Instances))}
+	groups := groupByService(len(in.Instances), func(i int) string { return in.Instances[i].ServiceId })
+	for _, g := range groups {
+		err := unregisterServiceInstances(ctx, g.serviceID, in.Instances, g.indexes)
+		for _, i := range g.indexes {
+			result := &InstanceResult{ServiceId: g.serviceID, InstanceId: in.Instances[i].InstanceId}
+			if err != nil {
+				result.Error = toServiceError(err)
+			}
+			resp.Instances[i] = result
+		}
+	}
+	return resp, nil
+}
+
+func unregisterServiceInstances(ctx context.Context, serviceID string, elements []*pb.HeartbeatSetElement,
+	indexes []int) error {
+	remoteIP := util.GetIPFromContext(ctx)
+	instanceIDs := make([]string, 0, len(indexes))
+	exist := make(map[string]struct{}, len(indexes))
+	for _, i := range indexes {
+		instanceID := elements[i].InstanceId
+		err := validator.ValidateUnregisterInstanceRequest(&pb.UnregisterInstanceRequest{
+			ServiceId:  serviceID,
+			InstanceId: instanceID,
+		})
+		if err != nil {
+			log.Error(fmt.Sprintf("batch unregister service[%s] instances failed, invalid parameters, operator %s",
+				serviceID, remoteIP), err)
+			return pb.NewError(pb.ErrInvalidParams, err.Error())
+		}
+		if _, ok := exist[instanceID]; ok {
+			continue
+		}
+		exist[instanceID] = struct{}{}
+		instanceIDs = append(instanceIDs, instanceID)
+	}
+	err := datasource.GetMetadataManager().UnregisterManyInstances(ctx, serviceID, instanceIDs)
+	if err != nil {
+		return err
+	}
+	for _, instanceID := range instanceIDs {
+		removeHeartbeatStats(ctx, serviceID, instanceID)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco_test
+
+import (
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
+)
+
+func TestRegisterManyInstances(t *testing.T) {
+	var (
+		serviceID1 string
+		serviceID2 string
+	)
+	ctx := getContext()
+	defer func() {
+		discosvc.UnregisterManyService(ctx, &pb.DelServicesRequest{ServiceIds: []string{serviceID1, serviceID2}, Force: true})
+	}()
+
+	t.Run("prepare data, should be passed", func(t *testing.T) {
+		for i, name := range []string{"batch_instance_service1", "batch_instance_service2"} {
+			resp, err := discosvc.RegisterService(ctx, &pb.CreateServiceRequest{
+				Service: &pb.MicroService{
+					ServiceName: name,
+					AppId:       "batch_instance",
+					Version:     "1.0.0",
+					Level:       "FRONT",
+					Status:      pb.MS_UP,
+				},
+			})
+			assert.NoError(t, err)
+			if i == 0 {
+				serviceID1 = resp.ServiceId
+			} else {
+				serviceID2 = resp.ServiceId
+			}
+		}
+	})
+
+	t.Run("register with invalid size, should be failed", func(t *testing.T) {
+		_, err := discosvc.RegisterManyInstances(ctx, &discosvc.RegisterManyInstancesRequest{})
+		testErr := err.(*errsvc.Error)
+		assert.Equal(t, pb.ErrInvalidParams, testErr.Code)
+
+		instances := make([]*pb.MicroServiceInstance, datasource.MaxBatchInstances+1)
+		for i := range instances {
+			instances[i] = &pb.MicroServiceInstance{ServiceId: serviceID1}
+		}
+		_, err = discosvc.RegisterManyInstances(ctx, &discosvc.RegisterManyInstancesRequest{Instances: instances})
+		testErr = err.(*errsvc.Error)
+		assert.Equal(t, pb.ErrInvalidParams, testErr.Code)
+	})
+
+	var instanceIDs []string
+	t.Run("register instances of many services, should be atomic per service", func(t *testing.T) {
+		resp, err := discosvc.RegisterManyInstances(ctx, &discosvc.RegisterManyInstancesRequest{
+			Instances: []*pb.MicroServiceInstance{
+				{ServiceId: serviceID1, HostName: "UT-HOST", Endpoints: []string{"batch:127.0.0.1:8080"}},
+				{ServiceId: serviceID2, HostName: "UT-HOST", Endpoints: []string{"batch:127.0.0.1:8081"}},
+				{ServiceId: serviceID1, HostName: "UT-HOST", Endpoints: []string{"batch:127.0.0.1:8082"}},
+				{ServiceId: serviceID2, Endpoints: []string{"batch:127.0.0.1:8083"}},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, len(resp.Instances))
+		for _, i := range []int{0, 2} {
+			assert.Nil(t, resp.Instances[i].Error)
+			assert.Equal(t, serviceID1, resp.Instances[i].ServiceId)
+			assert.NotEmpty(t, resp.Instances[i].InstanceId)
+			instanceIDs = append(instanceIDs, resp.Instances[i].InstanceId)
+		}
+		for _, i := range []int{1, 3} {
+			assert.Equal(t, pb.ErrInvalidParams, resp.Instances[i].Error.Code)
+			assert.Empty(t, resp.Instances[i].InstanceId)
+		}
+
+		instances, err := discosvc.ListInstance(ctx, &pb.GetInstancesRequest{ProviderServiceId: serviceID2})
+		assert.NoError(t, err)
+		assert.Empty(t, instances.Instances)
+	})
+
+	t.Run("register existing and new instances, should keep alive the existing one", func(t *testing.T) {
+		resp, err := discosvc.RegisterManyInstances(ctx, &discosvc.RegisterManyInstancesRequest{
+			Instances: []*pb.MicroServiceInstance{
+				{ServiceId: serviceID1, InstanceId: instanceIDs[0], HostName: "UT-HOST",
+					Endpoints: []string{"batch:127.0.0.1:8080"}},
+				{ServiceId: serviceID1, InstanceId: "batch_instance_new", HostName: "UT-HOST",
+					Endpoints: []string{"batch:127.0.0.1:8084"}},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, resp.Instances[0].Error)
+		assert.Equal(t, instanceIDs[0], resp.Instances[0].InstanceId)
+		assert.Nil(t, resp.Instances[1].Error)
+
+		instances, err := discosvc.ListInstance(ctx, &pb.GetInstancesRequest{ProviderServiceId: serviceID1})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(instances.Instances))
+
+		err = discosvc.UnregisterInstance(ctx, &pb.UnregisterInstanceRequest{
+			ServiceId: serviceID1, InstanceId: "batch_instance_new"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("unregister instances of many services, should be atomic per service", func(t *testing.T) {
+		err := discosvc.SendHeartbeat(ctx, &pb.HeartbeatRequest{ServiceId: serviceID1, InstanceId: instanceIDs[0]})
+		assert.NoError(t, err)
+		_, ok := discosvc.GetHeartbeatStats(ctx, serviceID1, instanceIDs[0])
+		assert.True(t, ok)
+
+		resp, err := discosvc.UnregisterManyInstances(ctx, &discosvc.UnregisterManyInstancesRequest{
+			Instances: []*pb.HeartbeatSetElement{
+				{ServiceId: serviceID1, InstanceId: instanceIDs[0]},
+				{ServiceId: serviceID2, InstanceId: "not-exist"},
+				{ServiceId: serviceID1, InstanceId: instanceIDs[1]},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, resp.Instances[0].Error)
+		assert.Equal(t, pb.ErrInstanceNotExists, resp.Instances[1].Error.Code)
+		assert.Nil(t, resp.Instances[2].Error)
+
+		instances, err := discosvc.ListInstance(ctx, &pb.GetInstancesRequest{ProviderServiceId: serviceID1})
+		assert.NoError(t, err)
+		assert.Empty(t, instances.Instances)
+		_, ok = discosvc.GetHeartbeatStats(ctx, serviceID1, instanceIDs[0])
+		assert.False(t, ok)
+	})
+}
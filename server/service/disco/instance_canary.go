@@ -0,0 +1,344 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strings"
+
+	pb "github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+const (
+	// PropertyCanary is the service property of the canary rule, it makes the service
+	// version a canary, whose instances are only found by the consumers matched the rule
+	PropertyCanary = "canary"
+
+	CtxConsumerHeaders util.CtxKey = "consumerHeaders"
+
+	// canaryRevSeparator joins the instances revision and the canary rules revision
+	canaryRevSeparator = "-"
+	maxCanaryPercent   = 100
+)
+
+// CanaryRule selects the consumers of the canary service version, a consumer is
+// matched if it is in the percent, or it has all the headers, or it is one of the consumers
+type CanaryRule struct {
+	// Percent of the consumers, which are hashed by the consumer service id and address
+	Percent int `json:"percent,omitempty"`
+	// Headers of the find instances request
+	Headers map[string]string `json:"headers,omitempty"`
+	// Consumers is the consumer service names
+	Consumers []string `json:"consumers,omitempty"`
+}
+
+// canaryConsumer is the consumer identity to match the canary rules
+type canaryConsumer struct {
+	ctx        context.Context
+	serviceID  string
+	address    string
+	headers    http.Header
+	name       string
+	nameLoaded bool
+}
+
+func canaryEnabled() bool {
+	return config.GetBool("registry.instance.canary.enable", false)
+}
+
+// WithConsumerHeaders sets the request headers to match the canary rules
+func WithConsumerHeaders(ctx context.Context, headers http.Header) context.Context {
+	if !canaryEnabled() || len(headers) == 0 {
+		return ctx
+	}
+	return util.SetContext(ctx, CtxConsumerHeaders, headers)
+}
+
+func ValidateCanaryRule(rule *CanaryRule) error {
+	if rule.Percent < 0 || rule.Percent > maxCanaryPercent {
+		return fmt.Errorf("canary percent %d is out of range [0, %d]", rule.Percent, maxCanaryPercent)
+	}
+	for key := range rule.Headers {
+		if len(key) == 0 {
+			return errors.New("canary header name is empty")
+		}
+	}
+	for _, name := range rule.Consumers {
+		if len(name) == 0 {
+			return errors.New("canary consumer name is empty")
+		}
+	}
+	return nil
+}
+
+// ParseCanaryRule returns nil if the service is not a canary
+func ParseCanaryRule(service *pb.MicroService) (*CanaryRule, error) {
+	value, ok := service.Properties[PropertyCanary]
+	if !ok {
+		return nil, nil
+	}
+	rule := &CanaryRule{}
+	if err := json.Unmarshal([]byte(value), rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func GetCanaryRule(ctx context.Context, serviceID string) (*CanaryRule, error) {
+	service, err := GetService(ctx, &pb.GetServiceRequest{ServiceId: serviceID})
+	if err != nil {
+		return nil, err
+	}
+	rule, err := ParseCanaryRule(service)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	if rule == nil {
+		return nil, pb.NewError(pb.ErrInvalidParams, fmt.Sprintf("service %s is not a canary", serviceID))
+	}
+	return rule, nil
+}
+
+// PutCanaryRule makes the service a canary, the other properties are kept
+func PutCanaryRule(ctx context.Context, serviceID string, rule *CanaryRule) error {
+	if err := ValidateCanaryRule(rule); err != nil {
+		return pb.NewError(pb.ErrInvalidParams, err.Error())
+	}
+	value, err := json.Marshal(rule)
+	if err != nil {
+		return pb.NewError(pb.ErrInternal, err.Error())
+	}
+	return updateCanaryProperty(ctx, serviceID, func(properties map[string]string) {
+		properties[PropertyCanary] = string(value)
+	})
+}
+
+// DeleteCanaryRule promotes the canary service to be found by all consumers
+func DeleteCanaryRule(ctx context.Context, serviceID string) error {
+	return updateCanaryProperty(ctx, serviceID, func(properties map[string]string) {
+		delete(properties, PropertyCanary)
+	})
+}
+
+func updateCanaryProperty(ctx context.Context, serviceID string, update func(properties map[string]string)) error {
+	service, err := GetService(ctx, &pb.GetServiceRequest{ServiceId: serviceID})
+	if err != nil {
+		return err
+	}
+	properties := make(map[string]string, len(service.Properties)+1)
+	for k, v := range service.Properties {
+		properties[k] = v
+	}
+	update(properties)
+	return PutServiceProperties(ctx, &pb.UpdateServicePropsRequest{
+		ServiceId:  serviceID,
+		Properties: properties,
+	})
+}
+
+// findInstances excludes the draining instances and selects the rest by the canary rules
+// of the providers if enabled, the revision of the rules is appended to the instances
+// revision, so that the consumers polling with the revision get the instances again
+// when the rules change
+func findInstances(ctx context.Context, in *pb.FindInstancesRequest) (*pb.FindInstancesResponse, error) {
+	// the request revision is absent if the REST consumer does not poll with rev
+	reqRev, _ := ctx.Value(util.CtxRequestRevision).(string)
+	if !canaryEnabled() {
+		resp, err := datasource.GetMetadataManager().FindInstances(ctx, in)
+		if err != nil || resp == nil {
+			return resp, err
+		}
+		resp.Instances = filterDraining(resp.Instances)
+		return resp, nil
+	}
+
+	instancesRev := reqRev
+	if i := strings.Index(reqRev, canaryRevSeparator); i >= 0 {
+		instancesRev = reqRev[:i]
+	}
+	findCtx := util.WithRequestRev(ctx, instancesRev)
+	defer util.WithRequestRev(findCtx, reqRev)
+
+	resp, err := datasource.GetMetadataManager().FindInstances(findCtx, in)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	respRev, _ := findCtx.Value(util.CtxResponseRevision).(string)
+	if len(instancesRev) > 0 && instancesRev == respRev {
+		// the instances are not modified, but the rules may be
+		util.WithRequestRev(findCtx, "")
+		resp, err = datasource.GetMetadataManager().FindInstances(findCtx, in)
+		if err != nil || resp == nil {
+			return resp, err
+		}
+	}
+
+	instances, rulesRev := selectCanary(findCtx, in, filterDraining(resp.Instances))
+	if len(rulesRev) > 0 {
+		respRev += canaryRevSeparator + rulesRev
+	}
+	util.WithResponseRev(findCtx, respRev)
+	if reqRev == respRev {
+		instances = nil // for gRPC
+	}
+	resp.Instances = instances
+	return resp, nil
+}
+
+// selectCanary returns the instances of the canary services the consumer matched,
+// or the instances of the stable services if no one matched, it returns all the
+// instances if only the canary services have instances
+func selectCanary(ctx context.Context, in *pb.FindInstancesRequest,
+	instances []*pb.MicroServiceInstance) ([]*pb.MicroServiceInstance, string) {
+	rules, rulesRev := loadCanaryRules(ctx, instances)
+	if len(rules) == 0 {
+		return instances, ""
+	}
+
+	headers, _ := ctx.Value(CtxConsumerHeaders).(http.Header)
+	consumer := &canaryConsumer{
+		ctx:       ctx,
+		serviceID: in.ConsumerServiceId,
+		address:   util.GetIPFromContext(ctx),
+		headers:   headers,
+	}
+	matched := make(map[string]bool, len(rules))
+	for serviceID, rule := range rules {
+		matched[serviceID] = consumer.match(serviceID, rule)
+	}
+
+	var canary, stable []*pb.MicroServiceInstance
+	for _, instance := range instances {
+		isMatched, isCanary := matched[instance.ServiceId]
+		switch {
+		case !isCanary:
+			stable = append(stable, instance)
+		case isMatched:
+			canary = append(canary, instance)
+		}
+	}
+	if len(canary) > 0 {
+		return canary, rulesRev
+	}
+	if len(stable) > 0 {
+		return stable, rulesRev
+	}
+	return instances, rulesRev
+}
+
+// loadCanaryRules returns the rules by service id and the revision of them
+func loadCanaryRules(ctx context.Context, instances []*pb.MicroServiceInstance) (map[string]*CanaryRule, string) {
+	getCtx := util.SetDomainProject(util.CloneContext(ctx), util.ParseTargetDomain(ctx), util.ParseTargetProject(ctx))
+	rules := make(map[string]*CanaryRule)
+	var values []string
+	loaded := make(map[string]struct{})
+	for _, instance := range instances {
+		if _, ok := loaded[instance.ServiceId]; ok {
+			continue
+		}
+		loaded[instance.ServiceId] = struct{}{}
+
+		service, err := datasource.GetMetadataManager().GetService(getCtx, &pb.GetServiceRequest{ServiceId: instance.ServiceId})
+		if err != nil {
+			log.Warn(fmt.Sprintf("get service %s to load the canary rule failed: %s", instance.ServiceId, err))
+			continue
+		}
+		rule, err := ParseCanaryRule(service)
+		if err != nil {
+			log.Warn(fmt.Sprintf("invalid canary rule of service %s: %s", instance.ServiceId, err))
+			continue
+		}
+		if rule == nil {
+			continue
+		}
+		rules[instance.ServiceId] = rule
+		values = append(values, instance.ServiceId+service.Properties[PropertyCanary])
+	}
+	if len(rules) == 0 {
+		return nil, ""
+	}
+	sort.Strings(values)
+	h := fnv.New32a()
+	for _, value := range values {
+		_, _ = h.Write([]byte(value))
+	}
+	return rules, fmt.Sprintf("%x", h.Sum32())
+}
+
+func (c *canaryConsumer) match(serviceID string, rule *CanaryRule) bool {
+	if rule.Percent > 0 && c.bucket(serviceID) < uint32(rule.Percent) {
+		return true
+	}
+	if len(rule.Headers) > 0 && c.hasHeaders(rule.Headers) {
+		return true
+	}
+	if len(rule.Consumers) > 0 {
+		name := c.serviceName()
+		for _, consumer := range rule.Consumers {
+			if len(name) > 0 && consumer == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bucket hashes the consumer into [0, 100), the same consumer is always in the
+// same bucket of a canary service, so increasing the percent only adds consumers
+func (c *canaryConsumer) bucket(serviceID string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(serviceID + "/" + c.serviceID + "/" + c.address))
+	return h.Sum32() % maxCanaryPercent
+}
+
+func (c *canaryConsumer) hasHeaders(headers map[string]string) bool {
+	if c.headers == nil {
+		return false
+	}
+	for key, value := range headers {
+		if c.headers.Get(key) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *canaryConsumer) serviceName() string {
+	if c.nameLoaded || len(c.serviceID) == 0 {
+		return c.name
+	}
+	c.nameLoaded = true
+	service, err := datasource.GetMetadataManager().GetService(c.ctx, &pb.GetServiceRequest{ServiceId: c.serviceID})
+	if err != nil {
+		log.Warn(fmt.Sprintf("get consumer %s to match the canary rules failed: %s", c.serviceID, err))
+		return ""
+	}
+	c.name = service.ServiceName
+	return c.name
+}
@@ -0,0 +1,171 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/go-archaius"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
+)
+
+func TestFindInstancesWithCanary(t *testing.T) {
+	var (
+		stableID   string
+		canaryID   string
+		consumerID string
+	)
+	ctx := getContext()
+	_ = archaius.Set("registry.instance.canary.enable", true)
+	defer func() {
+		_ = archaius.Set("registry.instance.canary.enable", false)
+		for _, id := range []string{stableID, canaryID, consumerID} {
+			discosvc.UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: id, Force: true})
+		}
+	}()
+
+	t.Run("prepare data, should be passed", func(t *testing.T) {
+		for _, version := range []string{"1.0.0", "2.0.0"} {
+			respCreate, err := discosvc.RegisterService(ctx, &pb.CreateServiceRequest{
+				Service: &pb.MicroService{
+					ServiceName: "canary_instance_service",
+					AppId:       "canary_instance",
+					Version:     version,
+					Level:       "FRONT",
+					Status:      pb.MS_UP,
+				},
+			})
+			assert.NoError(t, err)
+			_, err = discosvc.RegisterInstance(ctx, &pb.RegisterInstanceRequest{
+				Instance: &pb.MicroServiceInstance{
+					ServiceId: respCreate.ServiceId,
+					Endpoints: []string{"canary:127.0.0.1:" + version},
+					HostName:  "UT-HOST",
+					Status:    pb.MSI_UP,
+				},
+			})
+			assert.NoError(t, err)
+			if version == "1.0.0" {
+				stableID = respCreate.ServiceId
+			} else {
+				canaryID = respCreate.ServiceId
+			}
+		}
+
+		respCreate, err := discosvc.RegisterService(ctx, &pb.CreateServiceRequest{
+			Service: &pb.MicroService{
+				ServiceName: "canary_consumer",
+				AppId:       "canary_instance",
+				Version:     "1.0.0",
+				Level:       "FRONT",
+				Status:      pb.MS_UP,
+			},
+		})
+		assert.NoError(t, err)
+		consumerID = respCreate.ServiceId
+	})
+
+	find := func(findCtx context.Context, consumerID string) ([]*pb.MicroServiceInstance, string) {
+		resp, err := discosvc.FindInstances(findCtx, &pb.FindInstancesRequest{
+			ConsumerServiceId: consumerID,
+			AppId:             "canary_instance",
+			ServiceName:       "canary_instance_service",
+		})
+		assert.NoError(t, err)
+		ov, _ := findCtx.Value(util.CtxResponseRevision).(string)
+		return resp.Instances, ov
+	}
+	findBy := func(consumerID string, headers http.Header, rev string) ([]*pb.MicroServiceInstance, string) {
+		return find(discosvc.WithConsumerHeaders(util.WithRequestRev(getContext(), rev), headers), consumerID)
+	}
+
+	t.Run("put invalid canary rule, should be failed", func(t *testing.T) {
+		err := discosvc.PutCanaryRule(ctx, canaryID, &discosvc.CanaryRule{Percent: 101})
+		testErr := err.(*errsvc.Error)
+		assert.Equal(t, pb.ErrInvalidParams, testErr.Code)
+
+		_, err = discosvc.GetCanaryRule(ctx, canaryID)
+		testErr = err.(*errsvc.Error)
+		assert.Equal(t, pb.ErrInvalidParams, testErr.Code)
+	})
+
+	var revBefore string
+	t.Run("no canary rule, should find all instances", func(t *testing.T) {
+		var instances []*pb.MicroServiceInstance
+		instances, revBefore = findBy("", nil, "")
+		assert.Equal(t, 2, len(instances))
+	})
+
+	t.Run("put canary rule, only the matched consumers find the canary", func(t *testing.T) {
+		err := discosvc.PutCanaryRule(ctx, canaryID, &discosvc.CanaryRule{
+			Headers:   map[string]string{"X-Canary": "true"},
+			Consumers: []string{"canary_consumer"},
+		})
+		assert.NoError(t, err)
+		rule, err := discosvc.GetCanaryRule(ctx, canaryID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"canary_consumer"}, rule.Consumers)
+
+		instances, rev := findBy("", nil, revBefore)
+		assert.NotEqual(t, revBefore, rev, "the rule change should change the revision")
+		assert.Equal(t, 1, len(instances))
+		assert.Equal(t, stableID, instances[0].ServiceId)
+
+		instances, _ = findBy("", http.Header{"X-Canary": []string{"true"}}, "")
+		assert.Equal(t, 1, len(instances))
+		assert.Equal(t, canaryID, instances[0].ServiceId)
+
+		instances, _ = findBy(consumerID, nil, "")
+		assert.Equal(t, 1, len(instances))
+		assert.Equal(t, canaryID, instances[0].ServiceId)
+
+		instances, again := findBy("", nil, rev)
+		assert.Equal(t, rev, again)
+		assert.Empty(t, instances, "should be not modified")
+	})
+
+	t.Run("find without revision, should also apply the canary rule", func(t *testing.T) {
+		instances, rev := find(util.NewStringContext(getContext()), "")
+		assert.NotEmpty(t, rev)
+		assert.Equal(t, 1, len(instances))
+		assert.Equal(t, stableID, instances[0].ServiceId)
+	})
+
+	t.Run("put 100 percent canary rule, all consumers find the canary", func(t *testing.T) {
+		err := discosvc.PutCanaryRule(ctx, canaryID, &discosvc.CanaryRule{Percent: 100})
+		assert.NoError(t, err)
+		instances, _ := findBy("", nil, "")
+		assert.Equal(t, 1, len(instances))
+		assert.Equal(t, canaryID, instances[0].ServiceId)
+	})
+
+	t.Run("delete canary rule, should find all instances", func(t *testing.T) {
+		err := discosvc.DeleteCanaryRule(ctx, canaryID)
+		assert.NoError(t, err)
+		instances, rev := findBy("", nil, "")
+		assert.Equal(t, 2, len(instances))
+		assert.Equal(t, revBefore, rev)
+	})
+}
@@ -0,0 +1,333 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/drain"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+const (
+	// InstanceStatusDraining is the status of the instance in draining, it is removed from
+	// the find instances results, while the watchers are still notified of its changes
+	InstanceStatusDraining = "DRAINING"
+
+	// PropertyDrainStartTime and PropertyDrainDeadline are the instance properties of
+	// the draining, in unix seconds
+	PropertyDrainStartTime = "drainStartTime"
+	PropertyDrainDeadline  = "drainDeadline"
+
+	DrainStepStarted      = "STARTED"
+	DrainStepCompleted    = "COMPLETED"
+	DrainStepExpired      = "EXPIRED"
+	DrainStepUnregistered = "UNREGISTERED"
+	DrainStepFailed       = "FAILED"
+
+	defaultDrainGracePeriod = 30 * time.Second
+	// drainRetention is the time to keep the drain after the instance is unregistered
+	drainRetention = time.Hour
+)
+
+type DrainInstanceRequest struct {
+	ServiceID  string
+	InstanceID string
+	// GracePeriod is the time to unregister the instance if the drain is not completed, 0 to use the default
+	GracePeriod time.Duration
+}
+
+// DrainEvent is a step of the instance draining
+type DrainEvent struct {
+	ServiceID  string `json:"serviceId"`
+	InstanceID string `json:"instanceId"`
+	Step       string `json:"step"`
+	Message    string `json:"message,omitempty"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+// DrainStatus is the draining of the instance, the status is empty if the instance is unregistered
+type DrainStatus struct {
+	ServiceID  string        `json:"serviceId"`
+	InstanceID string        `json:"instanceId"`
+	Status     string        `json:"status,omitempty"`
+	StartTime  int64         `json:"startTime,omitempty"`
+	Deadline   int64         `json:"deadline,omitempty"`
+	Events     []*DrainEvent `json:"events"`
+}
+
+func drainEnabled() bool {
+	return config.GetBool("registry.instance.drain.enable", false)
+}
+
+func drainGracePeriod() time.Duration {
+	return config.GetDuration("registry.instance.drain.gracePeriod", defaultDrainGracePeriod)
+}
+
+// IsDraining returns true if the instance is in draining
+func IsDraining(instance *pb.MicroServiceInstance) bool {
+	return instance.Status == InstanceStatusDraining
+}
+
+// filterDraining removes the draining instances from the find results
+func filterDraining(instances []*pb.MicroServiceInstance) []*pb.MicroServiceInstance {
+	for i, instance := range instances {
+		if !IsDraining(instance) {
+			continue
+		}
+		filtered := append(make([]*pb.MicroServiceInstance, 0, len(instances)-1), instances[:i]...)
+		for _, instance := range instances[i+1:] {
+			if !IsDraining(instance) {
+				filtered = append(filtered, instance)
+			}
+		}
+		return filtered
+	}
+	return instances
+}
+
+// DrainInstance moves the instance into DRAINING, it is unregistered when CompleteDrain is called
+// or the grace period is passed. Draining a draining instance returns the current status
+func DrainInstance(ctx context.Context, in *DrainInstanceRequest) (*DrainStatus, error) {
+	remoteIP := util.GetIPFromContext(ctx)
+	drainFlag := util.StringJoin([]string{in.ServiceID, in.InstanceID}, "/")
+
+	if !drainEnabled() {
+		return nil, pb.NewError(pb.ErrForbidden, "Instance drain is disabled.")
+	}
+	if len(in.ServiceID) == 0 || len(in.InstanceID) == 0 || in.GracePeriod < 0 {
+		log.Error(fmt.Sprintf("drain instance[%s] failed, invalid parameters, operator %s", drainFlag, remoteIP), nil)
+		return nil, pb.NewError(pb.ErrInvalidParams, "Invalid service id, instance id or grace period.")
+	}
+	instance, err := getInstance(ctx, in.ServiceID, in.InstanceID)
+	if err != nil {
+		log.Error(fmt.Sprintf("drain instance[%s] failed, operator %s", drainFlag, remoteIP), err)
+		return nil, err
+	}
+	if IsDraining(instance) {
+		return GetDrainStatus(ctx, in.ServiceID, in.InstanceID)
+	}
+
+	gracePeriod := in.GracePeriod
+	if gracePeriod == 0 {
+		gracePeriod = drainGracePeriod()
+	}
+	now := time.Now()
+	message := fmt.Sprintf("grace period %s, operator %s", gracePeriod, remoteIP)
+	d := &drain.Drain{
+		Domain:     util.ParseDomain(ctx),
+		Project:    util.ParseProject(ctx),
+		ServiceID:  in.ServiceID,
+		InstanceID: in.InstanceID,
+		StartTime:  now.Unix(),
+		Deadline:   now.Add(gracePeriod).Unix(),
+		Events:     []*drain.Event{{Step: DrainStepStarted, Message: message, Timestamp: now.Unix()}},
+	}
+	// track the drain before the status, then the expiry job always finds the draining instance
+	if err = drain.Instance().PutDrain(ctx, d); err != nil {
+		log.Error(fmt.Sprintf("drain instance[%s] failed, operator %s", drainFlag, remoteIP), err)
+		return nil, err
+	}
+	props := make(map[string]string, len(instance.Properties)+2)
+	for k, v := range instance.Properties {
+		props[k] = v
+	}
+	props[PropertyDrainStartTime] = strconv.FormatInt(d.StartTime, 10)
+	props[PropertyDrainDeadline] = strconv.FormatInt(d.Deadline, 10)
+	err = datasource.GetMetadataManager().PutInstanceProperties(ctx, &pb.UpdateInstancePropsRequest{
+		ServiceId:  in.ServiceID,
+		InstanceId: in.InstanceID,
+		Properties: props,
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("drain instance[%s] failed, operator %s", drainFlag, remoteIP), err)
+		return nil, err
+	}
+	err = datasource.GetMetadataManager().PutInstanceStatus(ctx, &pb.UpdateInstanceStatusRequest{
+		ServiceId:  in.ServiceID,
+		InstanceId: in.InstanceID,
+		Status:     InstanceStatusDraining,
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("drain instance[%s] failed, operator %s", drainFlag, remoteIP), err)
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("instance[%s] drain %s %s", drainFlag, DrainStepStarted, message))
+	return GetDrainStatus(ctx, in.ServiceID, in.InstanceID)
+}
+
+// CompleteDrain unregisters the draining instance at once
+func CompleteDrain(ctx context.Context, serviceID, instanceID string) error {
+	remoteIP := util.GetIPFromContext(ctx)
+	instance, err := getInstance(ctx, serviceID, instanceID)
+	if err != nil {
+		log.Error(fmt.Sprintf("complete instance[%s/%s] drain failed, operator %s", serviceID, instanceID, remoteIP), err)
+		return err
+	}
+	if !IsDraining(instance) {
+		return pb.NewError(pb.ErrInvalidParams, "Service instance is not draining.")
+	}
+	return finishDrain(ctx, serviceID, instanceID, DrainStepCompleted, "operator "+remoteIP)
+}
+
+// ExpireDrains unregisters the draining instances of all domains which grace period is passed,
+// and removes the drains which are finished or canceled
+func ExpireDrains(ctx context.Context) error {
+	drains, err := drain.Instance().ListAllDrains(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	for _, d := range drains {
+		drainCtx := util.SetDomainProject(util.CloneContext(ctx), d.Domain, d.Project)
+		if d.FinishTime > 0 {
+			if now-d.FinishTime > int64(drainRetention/time.Second) {
+				removeDrain(drainCtx, d)
+			}
+			continue
+		}
+		if d.Deadline > now {
+			continue
+		}
+		expireDrain(drainCtx, d)
+	}
+	return nil
+}
+
+func expireDrain(ctx context.Context, d *drain.Drain) {
+	instance, err := getInstance(ctx, d.ServiceID, d.InstanceID)
+	if err != nil {
+		if code, _, _ := parseError(err); code == pb.ErrInstanceNotExists {
+			// unregistered by the instance itself
+			recordDrainEvent(ctx, d.ServiceID, d.InstanceID, DrainStepUnregistered, "")
+			return
+		}
+		log.Error(fmt.Sprintf("expire instance[%s/%s/%s] drain failed", d.Domain, d.Project, d.InstanceID), err)
+		return
+	}
+	if !IsDraining(instance) {
+		// the status is set back to cancel the draining
+		removeDrain(ctx, d)
+		return
+	}
+	err = finishDrain(ctx, d.ServiceID, d.InstanceID, DrainStepExpired, "grace period is passed")
+	if err != nil {
+		log.Error(fmt.Sprintf("expire instance[%s/%s/%s] drain failed", d.Domain, d.Project, d.InstanceID), err)
+	}
+}
+
+func removeDrain(ctx context.Context, d *drain.Drain) {
+	if err := drain.Instance().DeleteDrain(ctx, d.ServiceID, d.InstanceID); err != nil {
+		log.Error(fmt.Sprintf("remove instance[%s/%s/%s] drain failed", d.Domain, d.Project, d.InstanceID), err)
+	}
+}
+
+func finishDrain(ctx context.Context, serviceID, instanceID, step, message string) error {
+	recordDrainEvent(ctx, serviceID, instanceID, step, message)
+	err := datasource.GetMetadataManager().UnregisterInstance(ctx, &pb.UnregisterInstanceRequest{
+		ServiceId:  serviceID,
+		InstanceId: instanceID,
+	})
+	if err != nil {
+		recordDrainEvent(ctx, serviceID, instanceID, DrainStepFailed, err.Error())
+		return err
+	}
+	recordDrainEvent(ctx, serviceID, instanceID, DrainStepUnregistered, "")
+	return nil
+}
+
+// GetDrainStatus returns the drain of the instance, and the events if the instance is unregistered
+func GetDrainStatus(ctx context.Context, serviceID, instanceID string) (*DrainStatus, error) {
+	status := &DrainStatus{
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+		Events:     make([]*DrainEvent, 0),
+	}
+	d, err := drain.Instance().GetDrain(ctx, serviceID, instanceID)
+	if err != nil && err != drain.ErrDrainNotExists {
+		log.Error(fmt.Sprintf("get instance[%s/%s] drain failed", serviceID, instanceID), err)
+		return nil, err
+	}
+	if d != nil {
+		for _, evt := range d.Events {
+			status.Events = append(status.Events, &DrainEvent{
+				ServiceID:  serviceID,
+				InstanceID: instanceID,
+				Step:       evt.Step,
+				Message:    evt.Message,
+				Timestamp:  evt.Timestamp,
+			})
+		}
+	}
+	instance, err := getInstance(ctx, serviceID, instanceID)
+	if err != nil {
+		if code, _, _ := parseError(err); code == pb.ErrInstanceNotExists && len(status.Events) > 0 {
+			return status, nil
+		}
+		return nil, err
+	}
+	status.Status = instance.Status
+	if IsDraining(instance) {
+		status.StartTime, _ = strconv.ParseInt(instance.Properties[PropertyDrainStartTime], 10, 64)
+		status.Deadline, _ = strconv.ParseInt(instance.Properties[PropertyDrainDeadline], 10, 64)
+	}
+	return status, nil
+}
+
+// getInstance reads the latest instance, the drain steps must not be based on the stale cache
+func getInstance(ctx context.Context, serviceID, instanceID string) (*pb.MicroServiceInstance, error) {
+	getCtx := util.WithRequestRev(util.WithNoCache(util.CloneContext(ctx)), "")
+	resp, err := datasource.GetMetadataManager().GetInstance(getCtx, &pb.GetOneInstanceRequest{
+		ProviderServiceId:  serviceID,
+		ProviderInstanceId: instanceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Instance == nil {
+		return nil, pb.NewError(pb.ErrInstanceNotExists, "Service instance does not exist.")
+	}
+	return resp.Instance, nil
+}
+
+// recordDrainEvent appends the step to the drain, the drain is finished once the instance is unregistered
+func recordDrainEvent(ctx context.Context, serviceID, instanceID, step, message string) {
+	drainFlag := util.StringJoin([]string{util.ParseDomainProject(ctx), serviceID, instanceID}, "/")
+	log.Info(fmt.Sprintf("instance[%s] drain %s %s", drainFlag, step, message))
+	d, err := drain.Instance().GetDrain(ctx, serviceID, instanceID)
+	if err != nil {
+		log.Error(fmt.Sprintf("record instance[%s] drain %s failed", drainFlag, step), err)
+		return
+	}
+	now := time.Now().Unix()
+	d.Events = append(d.Events, &drain.Event{Step: step, Message: message, Timestamp: now})
+	if step == DrainStepUnregistered {
+		d.FinishTime = now
+	}
+	if err = drain.Instance().PutDrain(ctx, d); err != nil {
+		log.Error(fmt.Sprintf("record instance[%s] drain %s failed", drainFlag, step), err)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco_test
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/go-archaius"
+	"github.com/stretchr/testify/assert"
+
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
+)
+
+func TestDrainInstance(t *testing.T) {
+	var (
+		serviceID  string
+		instanceID string
+	)
+	ctx := getContext()
+	_ = archaius.Set("registry.instance.drain.enable", true)
+	defer func() {
+		_ = archaius.Set("registry.instance.drain.enable", false)
+		discosvc.UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: serviceID, Force: true})
+	}()
+
+	t.Run("prepare data, should be passed", func(t *testing.T) {
+		respCreate, err := discosvc.RegisterService(ctx, &pb.CreateServiceRequest{
+			Service: &pb.MicroService{
+				ServiceName: "drain_instance_service",
+				AppId:       "drain_instance",
+				Version:     "1.0.0",
+				Level:       "FRONT",
+				Status:      pb.MS_UP,
+			},
+		})
+		assert.NoError(t, err)
+		serviceID = respCreate.ServiceId
+
+		for _, ep := range []string{"drain:127.0.0.1:8080", "drain:127.0.0.1:8081"} {
+			resp, err := discosvc.RegisterInstance(ctx, &pb.RegisterInstanceRequest{
+				Instance: &pb.MicroServiceInstance{
+					ServiceId: serviceID,
+					Endpoints: []string{ep},
+					HostName:  "UT-HOST",
+					Status:    pb.MSI_UP,
+				},
+			})
+			assert.NoError(t, err)
+			instanceID = resp.InstanceId
+		}
+	})
+
+	t.Run("drain when disabled, should be failed", func(t *testing.T) {
+		_ = archaius.Set("registry.instance.drain.enable", false)
+		defer archaius.Set("registry.instance.drain.enable", true)
+		_, err := discosvc.DrainInstance(ctx, &discosvc.DrainInstanceRequest{ServiceID: serviceID, InstanceID: instanceID})
+		testErr := err.(*errsvc.Error)
+		assert.Equal(t, pb.ErrForbidden, testErr.Code)
+	})
+
+	t.Run("drain with invalid params, should be failed", func(t *testing.T) {
+		_, err := discosvc.DrainInstance(ctx, &discosvc.DrainInstanceRequest{ServiceID: serviceID})
+		testErr := err.(*errsvc.Error)
+		assert.Equal(t, pb.ErrInvalidParams, testErr.Code)
+
+		_, err = discosvc.DrainInstance(ctx, &discosvc.DrainInstanceRequest{ServiceID: serviceID, InstanceID: "notexist"})
+		testErr = err.(*errsvc.Error)
+		assert.Equal(t, pb.ErrInstanceNotExists, testErr.Code)
+
+		err = discosvc.CompleteDrain(ctx, serviceID, instanceID)
+		testErr = err.(*errsvc.Error)
+		assert.Equal(t, pb.ErrInvalidParams, testErr.Code)
+	})
+
+	t.Run("drain instance, should be removed from find results", func(t *testing.T) {
+		status, err := discosvc.DrainInstance(ctx, &discosvc.DrainInstanceRequest{
+			ServiceID:   serviceID,
+			InstanceID:  instanceID,
+			GracePeriod: time.Minute,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, discosvc.InstanceStatusDraining, status.Status)
+		assert.Equal(t, int64(60), status.Deadline-status.StartTime)
+
+		again, err := discosvc.DrainInstance(ctx, &discosvc.DrainInstanceRequest{ServiceID: serviceID, InstanceID: instanceID})
+		assert.NoError(t, err)
+		assert.Equal(t, status.Deadline, again.Deadline)
+
+		resp, err := discosvc.FindInstances(ctx, &pb.FindInstancesRequest{
+			AppId:       "drain_instance",
+			ServiceName: "drain_instance_service",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(resp.Instances))
+		assert.NotEqual(t, instanceID, resp.Instances[0].InstanceId)
+
+		// the expiry should skip the draining instance in grace period
+		assert.NoError(t, discosvc.ExpireDrains(ctx))
+		_, err = discosvc.GetInstance(ctx, &pb.GetOneInstanceRequest{ProviderServiceId: serviceID, ProviderInstanceId: instanceID})
+		assert.NoError(t, err)
+	})
+
+	t.Run("complete drain, should unregister the instance", func(t *testing.T) {
+		err := discosvc.CompleteDrain(ctx, serviceID, instanceID)
+		assert.NoError(t, err)
+
+		_, err = discosvc.GetInstance(ctx, &pb.GetOneInstanceRequest{ProviderServiceId: serviceID, ProviderInstanceId: instanceID})
+		assert.Error(t, err)
+
+		status, err := discosvc.GetDrainStatus(ctx, serviceID, instanceID)
+		assert.NoError(t, err)
+		assert.Empty(t, status.Status)
+		steps := make([]string, 0, len(status.Events))
+		for _, evt := range status.Events {
+			steps = append(steps, evt.Step)
+		}
+		assert.Equal(t, []string{discosvc.DrainStepStarted, discosvc.DrainStepCompleted, discosvc.DrainStepUnregistered}, steps)
+	})
+}
@@ -0,0 +1,273 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+const (
+	// recentHeartbeats is the window of the heartbeats to suggest the interval
+	recentHeartbeats = 10
+	// jitterGain is the gain of the jitter smoothing, the same as RFC 3550
+	jitterGain = 16
+	// heartbeatStatsSweepInterval is the interval to remove the stats of the
+	// instances which stop sending heartbeats
+	heartbeatStatsSweepInterval = time.Minute
+	// clusterSizeCheckInterval is the interval to check the number of service centers
+	clusterSizeCheckInterval = time.Minute
+)
+
+// HeartbeatStats is the statistics of the instance heartbeats received by this
+// service center, the durations are in seconds
+type HeartbeatStats struct {
+	// LastHeartbeat is the unix seconds of the last heartbeat
+	LastHeartbeat int64 `json:"lastHeartbeat"`
+	Count         int64 `json:"count"`
+	// Interval and Times are of the instance health check
+	Interval int32 `json:"interval"`
+	Times    int32 `json:"times"`
+	// MeanInterval is the moving average of the intervals between the heartbeats
+	MeanInterval float64 `json:"meanInterval"`
+	// Jitter is the smoothed deviation of the intervals from the health check interval
+	Jitter float64 `json:"jitter"`
+	// Missed is the count of the heartbeats expected but not received
+	Missed int64 `json:"missed"`
+	// SuggestedInterval is the interval suggested to the instance if it misses the
+	// heartbeats recently, 0 if no suggestion or the suggestion is disabled, it is
+	// always 0 in a cluster, as the heartbeats may be received by other service centers
+	SuggestedInterval int32 `json:"suggestedInterval,omitempty"`
+}
+
+type heartbeatEntry struct {
+	stats  HeartbeatStats
+	ttl    int64
+	last   time.Time
+	recent [recentHeartbeats]int64
+	cursor int
+}
+
+type heartbeatRecorder struct {
+	lock      sync.Mutex
+	entries   map[string]*heartbeatEntry
+	lastSweep time.Time
+}
+
+// clusterSize caches the number of the service centers
+type clusterSize struct {
+	lock    sync.Mutex
+	size    int
+	checked time.Time
+}
+
+var (
+	heartbeatStats = &heartbeatRecorder{entries: make(map[string]*heartbeatEntry)}
+	scClusterSize  = &clusterSize{}
+)
+
+func suggestIntervalEnabled() bool {
+	return config.GetBool("registry.instance.heartbeat.suggestInterval", false)
+}
+
+// canSuggestInterval returns true if this service center is the only one, otherwise the
+// heartbeats of an instance may be sent to the others, then the gaps between the
+// heartbeats received by this one do not mean the heartbeats are missed
+func canSuggestInterval(ctx context.Context) bool {
+	if !suggestIntervalEnabled() {
+		return false
+	}
+	return scClusterSize.get(ctx) <= 1
+}
+
+func (c *clusterSize) get(ctx context.Context) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if time.Since(c.checked) < clusterSizeCheckInterval {
+		return c.size
+	}
+	c.checked = time.Now()
+	resp, err := ClusterHealth(ctx)
+	if err != nil {
+		// unknown, do not suggest until the next check
+		log.Error("get the number of service centers failed", err)
+		c.size = math.MaxInt32
+		return c.size
+	}
+	c.size = len(resp.Instances)
+	return c.size
+}
+
+func heartbeatKey(ctx context.Context, serviceID, instanceID string) string {
+	return util.StringJoin([]string{util.ParseDomainProject(ctx), serviceID, instanceID}, "/")
+}
+
+// GetHeartbeatStats returns the heartbeat statistics of the instance, false if
+// this service center does not receive its heartbeat
+func GetHeartbeatStats(ctx context.Context, serviceID, instanceID string) (*HeartbeatStats, bool) {
+	return heartbeatStats.get(heartbeatKey(ctx, serviceID, instanceID))
+}
+
+// SuggestedInterval returns the heartbeat interval suggested to the instance, 0 if no suggestion
+func SuggestedInterval(ctx context.Context, serviceID, instanceID string) int32 {
+	stats, ok := GetHeartbeatStats(ctx, serviceID, instanceID)
+	if !ok {
+		return 0
+	}
+	return stats.SuggestedInterval
+}
+
+func recordHeartbeat(ctx context.Context, serviceID, instanceID string) {
+	key := heartbeatKey(ctx, serviceID, instanceID)
+	if heartbeatStats.record(key, time.Now(), canSuggestInterval(ctx)) {
+		return
+	}
+	// the first heartbeat received, get the health check of instance
+	resp, err := datasource.GetMetadataManager().GetInstance(ctx,
+		&pb.GetOneInstanceRequest{ProviderServiceId: serviceID, ProviderInstanceId: instanceID})
+	if err != nil || resp.Instance == nil {
+		log.Warn(fmt.Sprintf("get instance[%s/%s] to record heartbeat stats failed: %v", serviceID, instanceID, err))
+		return
+	}
+	heartbeatStats.init(key, resp.Instance.HealthCheck, time.Now())
+}
+
+func removeHeartbeatStats(ctx context.Context, serviceID, instanceID string) {
+	heartbeatStats.remove(heartbeatKey(ctx, serviceID, instanceID))
+}
+
+func (r *heartbeatRecorder) init(key string, check *pb.HealthCheck, now time.Time) {
+	if check == nil || check.Interval <= 0 {
+		return
+	}
+	ttl := int64(check.Interval * (check.Times + 1))
+	if instanceTTL := config.GetRegistry().InstanceTTL; instanceTTL > 0 {
+		ttl = instanceTTL
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.entries[key]; ok {
+		return
+	}
+	r.entries[key] = &heartbeatEntry{
+		stats: HeartbeatStats{
+			LastHeartbeat: now.Unix(),
+			Count:         1,
+			Interval:      check.Interval,
+			Times:         check.Times,
+			MeanInterval:  float64(check.Interval),
+		},
+		ttl:  ttl,
+		last: now,
+	}
+}
+
+// record returns false if the instance is not recorded yet
+func (r *heartbeatRecorder) record(key string, now time.Time, suggest bool) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.sweep(now)
+
+	e, ok := r.entries[key]
+	if !ok {
+		return false
+	}
+	interval := float64(e.stats.Interval)
+	gap := now.Sub(e.last).Seconds()
+	missed := int64(math.Round(gap/interval)) - 1
+	if missed < 0 {
+		missed = 0
+	}
+
+	e.stats.LastHeartbeat = now.Unix()
+	e.stats.Count++
+	e.stats.MeanInterval += (gap - e.stats.MeanInterval) / recentHeartbeats
+	e.stats.Jitter += (math.Abs(gap-interval) - e.stats.Jitter) / jitterGain
+	e.stats.Missed += missed
+	e.recent[e.cursor] = missed
+	e.cursor = (e.cursor + 1) % recentHeartbeats
+	e.last = now
+	e.stats.SuggestedInterval = 0
+	if suggest {
+		e.stats.SuggestedInterval = e.suggest()
+	}
+	return true
+}
+
+// suggest shortens the interval to send one more heartbeat in the TTL for
+// each heartbeat missed recently, so that the instance is not evicted by the
+// lost heartbeats, and the interval is not less than the minimum interval
+func (e *heartbeatEntry) suggest() int32 {
+	var missed int64
+	for _, m := range e.recent {
+		missed += m
+	}
+	if missed == 0 {
+		return 0
+	}
+	minInterval := int64(config.GetDuration("registry.instance.minInterval", defaultMinInterval) / time.Second)
+	suggested := e.ttl / (int64(e.stats.Times) + 1 + missed)
+	if suggested < minInterval {
+		suggested = minInterval
+	}
+	if suggested >= int64(e.stats.Interval) {
+		return 0
+	}
+	return int32(suggested)
+}
+
+// sweep removes the stats of the instances which do not send heartbeats in the TTL
+func (r *heartbeatRecorder) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < heartbeatStatsSweepInterval {
+		return
+	}
+	r.lastSweep = now
+	for key, e := range r.entries {
+		if now.Sub(e.last) > time.Duration(e.ttl)*time.Second {
+			delete(r.entries, key)
+		}
+	}
+}
+
+func (r *heartbeatRecorder) get(key string) (*HeartbeatStats, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	e, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+	stats := e.stats
+	return &stats, true
+}
+
+func (r *heartbeatRecorder) remove(key string) {
+	r.lock.Lock()
+	delete(r.entries, key)
+	r.lock.Unlock()
+}
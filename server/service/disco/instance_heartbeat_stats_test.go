@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco_test
+
+import (
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
+)
+
+func TestHeartbeatStats(t *testing.T) {
+	var (
+		serviceID  string
+		instanceID string
+	)
+	ctx := getContext()
+	defer func() {
+		discosvc.UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: serviceID, Force: true})
+	}()
+
+	t.Run("prepare data, should be passed", func(t *testing.T) {
+		respCreate, err := discosvc.RegisterService(ctx, &pb.CreateServiceRequest{
+			Service: &pb.MicroService{
+				ServiceName: "heartbeat_stats_service",
+				AppId:       "heartbeat_stats",
+				Version:     "1.0.0",
+				Level:       "FRONT",
+				Status:      pb.MS_UP,
+			},
+		})
+		assert.NoError(t, err)
+		serviceID = respCreate.ServiceId
+
+		resp, err := discosvc.RegisterInstance(ctx, &pb.RegisterInstanceRequest{
+			Instance: &pb.MicroServiceInstance{
+				ServiceId: serviceID,
+				Endpoints: []string{"heartbeat_stats:127.0.0.1:8080"},
+				HostName:  "UT-HOST",
+				Status:    pb.MSI_UP,
+				HealthCheck: &pb.HealthCheck{
+					Mode:     pb.CHECK_BY_HEARTBEAT,
+					Interval: 30,
+					Times:    3,
+				},
+			},
+		})
+		assert.NoError(t, err)
+		instanceID = resp.InstanceId
+	})
+
+	t.Run("no heartbeat, should have no stats", func(t *testing.T) {
+		_, ok := discosvc.GetHeartbeatStats(ctx, serviceID, instanceID)
+		assert.False(t, ok)
+	})
+
+	t.Run("send heartbeats, should have the stats", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			err := discosvc.SendHeartbeat(ctx, &pb.HeartbeatRequest{ServiceId: serviceID, InstanceId: instanceID})
+			assert.NoError(t, err)
+		}
+		stats, ok := discosvc.GetHeartbeatStats(ctx, serviceID, instanceID)
+		assert.True(t, ok)
+		assert.Equal(t, int64(2), stats.Count)
+		assert.Equal(t, int32(30), stats.Interval)
+		assert.Equal(t, int32(3), stats.Times)
+		assert.Equal(t, int64(0), stats.Missed)
+		assert.Equal(t, int32(0), discosvc.SuggestedInterval(ctx, serviceID, instanceID))
+	})
+
+	t.Run("unregister instance, should remove the stats", func(t *testing.T) {
+		err := discosvc.UnregisterInstance(ctx, &pb.UnregisterInstanceRequest{ServiceId: serviceID, InstanceId: instanceID})
+		assert.NoError(t, err)
+		_, ok := discosvc.GetHeartbeatStats(ctx, serviceID, instanceID)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,167 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	pb "github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+const (
+	// LocalityModeOrder sorts the provider instances by the consumer locality
+	LocalityModeOrder = "order"
+	// LocalityModeFilter returns the nearest provider instances only,
+	// it spills over to the farther ones if the healthy instances are not enough
+	LocalityModeFilter = "filter"
+
+	// PropertyWeight is the instance property of the weight in selection, 0 to 100
+	PropertyWeight = "weight"
+	DefaultWeight  = 100
+
+	CtxLocality util.CtxKey = "locality"
+)
+
+const (
+	tierSameZone = iota
+	tierSameRegion
+	tierOthers
+	tierCount
+)
+
+// Locality is the datacenter where the consumer is
+type Locality struct {
+	Region        string
+	AvailableZone string
+}
+
+// WithLocality sets the consumer locality to find the nearest provider instances
+func WithLocality(ctx context.Context, region, availableZone string) context.Context {
+	if len(region) == 0 && len(availableZone) == 0 {
+		return ctx
+	}
+	return util.SetContext(ctx, CtxLocality, &Locality{Region: region, AvailableZone: availableZone})
+}
+
+func localityMode() string {
+	return config.GetString("registry.instance.locality.mode", "")
+}
+
+func localityMinHealthy() int {
+	return config.GetInt("registry.instance.locality.minHealthy", 1)
+}
+
+// SelectInstances orders or filters the provider instances by the locality mode, the instances in
+// the same available zone of the consumer come first, then the same region, then others, and
+// the instances in the same tier are shuffled by the weight. It returns the instances as is if the
+// locality mode is disabled, and never modifies them
+func SelectInstances(ctx context.Context, instances []*pb.MicroServiceInstance) []*pb.MicroServiceInstance {
+	mode := localityMode()
+	if mode != LocalityModeOrder && mode != LocalityModeFilter {
+		return instances
+	}
+	if len(instances) == 0 {
+		return instances
+	}
+
+	locality, _ := ctx.Value(CtxLocality).(*Locality)
+	tiers := make([][]*pb.MicroServiceInstance, tierCount)
+	for _, instance := range instances {
+		tier := localityTier(locality, instance.DataCenterInfo)
+		tiers[tier] = append(tiers[tier], instance)
+	}
+
+	minHealthy := localityMinHealthy()
+	selected := make([]*pb.MicroServiceInstance, 0, len(instances))
+	healthy := 0
+	for _, tier := range tiers {
+		if mode == LocalityModeFilter && len(selected) > 0 && healthy >= minHealthy {
+			break
+		}
+		for _, instance := range shuffleByWeight(tier) {
+			if instance.Status == pb.MSI_UP && InstanceWeight(instance) > 0 {
+				healthy++
+			}
+			selected = append(selected, instance)
+		}
+	}
+	return selected
+}
+
+func localityTier(locality *Locality, dc *pb.DataCenterInfo) int {
+	if locality == nil || dc == nil {
+		return tierOthers
+	}
+	if len(locality.Region) > 0 && locality.Region != dc.Region {
+		return tierOthers
+	}
+	if len(locality.AvailableZone) > 0 && locality.AvailableZone == dc.AvailableZone {
+		return tierSameZone
+	}
+	if len(locality.Region) > 0 {
+		return tierSameRegion
+	}
+	return tierOthers
+}
+
+// InstanceWeight returns the weight in instance properties, DefaultWeight if not set or invalid
+func InstanceWeight(instance *pb.MicroServiceInstance) int {
+	v, ok := instance.Properties[PropertyWeight]
+	if !ok {
+		return DefaultWeight
+	}
+	weight, err := strconv.Atoi(v)
+	if err != nil || weight < 0 || weight > DefaultWeight {
+		return DefaultWeight
+	}
+	return weight
+}
+
+// shuffleByWeight is the weighted random permutation, the instance with larger
+// weight comes first in higher probability, and the zero weight ones come last
+func shuffleByWeight(instances []*pb.MicroServiceInstance) []*pb.MicroServiceInstance {
+	if len(instances) <= 1 {
+		return instances
+	}
+	keys := make([]float64, len(instances))
+	indexes := make([]int, len(instances))
+	for i, instance := range instances {
+		indexes[i] = i
+		weight := InstanceWeight(instance)
+		if weight == 0 {
+			keys[i] = math.Inf(1)
+			continue
+		}
+		keys[i] = rand.ExpFloat64() / float64(weight)
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return keys[indexes[i]] < keys[indexes[j]]
+	})
+	shuffled := make([]*pb.MicroServiceInstance, len(instances))
+	for i, index := range indexes {
+		shuffled[i] = instances[index]
+	}
+	return shuffled
+}
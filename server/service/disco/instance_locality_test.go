@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco_test
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/go-archaius"
+	"github.com/stretchr/testify/assert"
+
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
+)
+
+func newLocalityInstance(id, region, zone, status string, props map[string]string) *pb.MicroServiceInstance {
+	return &pb.MicroServiceInstance{
+		InstanceId:     id,
+		Status:         status,
+		Properties:     props,
+		DataCenterInfo: &pb.DataCenterInfo{Region: region, AvailableZone: zone},
+	}
+}
+
+func instanceIDs(instances []*pb.MicroServiceInstance) []string {
+	var ids []string
+	for _, instance := range instances {
+		ids = append(ids, instance.InstanceId)
+	}
+	return ids
+}
+
+func TestSelectInstances(t *testing.T) {
+	instances := []*pb.MicroServiceInstance{
+		newLocalityInstance("other", "r2", "r2-az1", pb.MSI_UP, nil),
+		newLocalityInstance("region", "r1", "r1-az2", pb.MSI_UP, nil),
+		newLocalityInstance("zone", "r1", "r1-az1", pb.MSI_UP, nil),
+	}
+	ctx := discosvc.WithLocality(context.Background(), "r1", "r1-az1")
+	defer archaius.Set("registry.instance.locality.mode", "")
+
+	t.Run("disabled should return instances as is", func(t *testing.T) {
+		_ = archaius.Set("registry.instance.locality.mode", "")
+		assert.Equal(t, instances, discosvc.SelectInstances(ctx, instances))
+	})
+
+	t.Run("order mode should sort by zone, region, then others", func(t *testing.T) {
+		_ = archaius.Set("registry.instance.locality.mode", discosvc.LocalityModeOrder)
+		selected := discosvc.SelectInstances(ctx, instances)
+		assert.Equal(t, []string{"zone", "region", "other"}, instanceIDs(selected))
+		assert.Equal(t, "other", instances[0].InstanceId, "should not modify the origin")
+
+		selected = discosvc.SelectInstances(context.Background(), instances)
+		assert.Len(t, selected, 3)
+	})
+
+	t.Run("filter mode should return the nearest healthy instances", func(t *testing.T) {
+		_ = archaius.Set("registry.instance.locality.mode", discosvc.LocalityModeFilter)
+		_ = archaius.Set("registry.instance.locality.minHealthy", 1)
+		assert.Equal(t, []string{"zone"}, instanceIDs(discosvc.SelectInstances(ctx, instances)))
+
+		_ = archaius.Set("registry.instance.locality.minHealthy", 2)
+		assert.Equal(t, []string{"zone", "region"}, instanceIDs(discosvc.SelectInstances(ctx, instances)))
+	})
+
+	t.Run("filter mode should spill over if the nearest are unhealthy", func(t *testing.T) {
+		_ = archaius.Set("registry.instance.locality.mode", discosvc.LocalityModeFilter)
+		_ = archaius.Set("registry.instance.locality.minHealthy", 1)
+		unhealthy := []*pb.MicroServiceInstance{
+			newLocalityInstance("down", "r1", "r1-az1", pb.MSI_DOWN, nil),
+			newLocalityInstance("zero", "r1", "r1-az1", pb.MSI_UP, map[string]string{discosvc.PropertyWeight: "0"}),
+			newLocalityInstance("region", "r1", "r1-az2", pb.MSI_UP, nil),
+			newLocalityInstance("other", "r2", "r2-az1", pb.MSI_UP, nil),
+		}
+		selected := discosvc.SelectInstances(ctx, unhealthy)
+		assert.ElementsMatch(t, []string{"down", "zero", "region"}, instanceIDs(selected))
+		assert.Equal(t, "region", selected[2].InstanceId)
+	})
+
+	t.Run("zero weight instances should come last in the tier", func(t *testing.T) {
+		_ = archaius.Set("registry.instance.locality.mode", discosvc.LocalityModeOrder)
+		weighted := []*pb.MicroServiceInstance{
+			newLocalityInstance("zero", "r1", "r1-az1", pb.MSI_UP, map[string]string{discosvc.PropertyWeight: "0"}),
+			newLocalityInstance("default", "r1", "r1-az1", pb.MSI_UP, nil),
+		}
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, []string{"default", "zero"}, instanceIDs(discosvc.SelectInstances(ctx, weighted)))
+		}
+	})
+}
+
+func TestInstanceWeight(t *testing.T) {
+	assert.Equal(t, discosvc.DefaultWeight, discosvc.InstanceWeight(&pb.MicroServiceInstance{}))
+	assert.Equal(t, 10, discosvc.InstanceWeight(&pb.MicroServiceInstance{
+		Properties: map[string]string{discosvc.PropertyWeight: "10"}}))
+	assert.Equal(t, discosvc.DefaultWeight, discosvc.InstanceWeight(&pb.MicroServiceInstance{
+		Properties: map[string]string{discosvc.PropertyWeight: "x"}}))
+}
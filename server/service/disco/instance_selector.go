@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"context"
+
+	pb "github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/selector"
+)
+
+// WithSelector parses the selector expression to filter the instances found,
+// see pkg/selector for the syntax and datasource.InstanceFields for the keys
+func WithSelector(ctx context.Context, expr string) (context.Context, error) {
+	s, err := selector.Parse(expr)
+	if err == nil {
+		err = datasource.ValidateInstanceSelector(s)
+	}
+	if err != nil {
+		return ctx, pb.NewError(pb.ErrInvalidParams, err.Error())
+	}
+	if s.Empty() {
+		return ctx, nil
+	}
+	return datasource.WithInstanceSelector(ctx, s), nil
+}
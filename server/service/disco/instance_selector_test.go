@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco_test
+
+import (
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
+)
+
+func TestFindInstancesWithSelector(t *testing.T) {
+	var serviceID string
+	ctx := getContext()
+	defer func() {
+		discosvc.UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: serviceID, Force: true})
+	}()
+
+	t.Run("prepare data, should be passed", func(t *testing.T) {
+		respCreate, err := discosvc.RegisterService(ctx, &pb.CreateServiceRequest{
+			Service: &pb.MicroService{
+				ServiceName: "selector_instance_service",
+				AppId:       "selector_instance",
+				Version:     "1.0.0",
+				Level:       "FRONT",
+				Status:      pb.MS_UP,
+			},
+		})
+		assert.NoError(t, err)
+		serviceID = respCreate.ServiceId
+
+		for ep, env := range map[string]string{"selector:127.0.0.1:8080": "prod", "selector:127.0.0.1:8081": "gray"} {
+			_, err := discosvc.RegisterInstance(ctx, &pb.RegisterInstanceRequest{
+				Instance: &pb.MicroServiceInstance{
+					ServiceId:  serviceID,
+					Endpoints:  []string{ep},
+					HostName:   "UT-HOST",
+					Status:     pb.MSI_UP,
+					Properties: map[string]string{"env": env},
+				},
+			})
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("invalid selector, should be failed", func(t *testing.T) {
+		for _, expr := range []string{"env=prod", "properties.env in ()", "status=UP,"} {
+			_, err := discosvc.WithSelector(ctx, expr)
+			testErr := err.(*errsvc.Error)
+			assert.Equal(t, pb.ErrInvalidParams, testErr.Code, expr)
+		}
+	})
+
+	t.Run("find with selector, should return the matched instances", func(t *testing.T) {
+		find := func(expr string) []*pb.MicroServiceInstance {
+			findCtx, err := discosvc.WithSelector(getContext(), expr)
+			assert.NoError(t, err)
+			resp, err := discosvc.FindInstances(findCtx, &pb.FindInstancesRequest{
+				AppId:       "selector_instance",
+				ServiceName: "selector_instance_service",
+			})
+			assert.NoError(t, err)
+			return resp.Instances
+		}
+
+		assert.Equal(t, 2, len(find("")))
+		assert.Equal(t, 2, len(find("status=UP,properties.env in (prod,gray)")))
+		assert.Equal(t, 0, len(find("properties.env notin (prod,gray)")))
+
+		instances := find("properties.env=gray")
+		assert.Equal(t, 1, len(instances))
+		assert.Equal(t, "gray", instances[0].Properties["env"])
+
+		instances = find("properties.env!=gray,hostName=UT-HOST")
+		assert.Equal(t, 1, len(instances))
+		assert.Equal(t, "prod", instances[0].Properties["env"])
+	})
+	t.Run("find with another selector and the same revision, should return the instances", func(t *testing.T) {
+		find := func(rev, expr string) (string, []*pb.MicroServiceInstance) {
+			findCtx, err := discosvc.WithSelector(util.WithRequestRev(getContext(), rev), expr)
+			assert.NoError(t, err)
+			resp, err := discosvc.FindInstances(findCtx, &pb.FindInstancesRequest{
+				AppId:       "selector_instance",
+				ServiceName: "selector_instance_service",
+			})
+			assert.NoError(t, err)
+			respRev, _ := findCtx.Value(util.CtxResponseRevision).(string)
+			return respRev, resp.Instances
+		}
+
+		rev, instances := find("", "properties.env=gray")
+		assert.Equal(t, 1, len(instances))
+		_, instances = find(rev, "properties.env=gray")
+		assert.Equal(t, 0, len(instances))
+		prodRev, instances := find(rev, "properties.env=prod")
+		assert.NotEqual(t, rev, prodRev)
+		assert.Equal(t, 1, len(instances))
+	})
+}
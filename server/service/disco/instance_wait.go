@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/event"
+	"github.com/apache/servicecomb-service-center/server/metrics"
+)
+
+// MaxWaitTimeout is the max time a find instances request can be held
+const MaxWaitTimeout = 60 * time.Second
+
+// ParseWaitTimeout parses the wait timeout of the long polling request, an empty
+// value means not to wait, and the timeout is limited to MaxWaitTimeout
+func ParseWaitTimeout(wait string) (time.Duration, error) {
+	if len(wait) == 0 {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(wait)
+	if err != nil {
+		return 0, pb.NewError(pb.ErrInvalidParams, fmt.Sprintf("invalid wait timeout %s", wait))
+	}
+	if d < 0 {
+		return 0, pb.NewError(pb.ErrInvalidParams, "wait timeout can not be negative")
+	}
+	if d > MaxWaitTimeout {
+		return MaxWaitTimeout, nil
+	}
+	return d, nil
+}
+
+// WaitInstances is the long polling version of FindInstances, if the instances are not modified
+// since the request revision, it holds the request until the instance events of the consumer come
+// and the revision changes, or the wait timeout expires. The request is not held if the consumer
+// is not specified, since the instance events are only published to the consumers
+func WaitInstances(ctx context.Context, in *pb.FindInstancesRequest, wait time.Duration) (*pb.FindInstancesResponse, error) {
+	iv, _ := ctx.Value(util.CtxRequestRevision).(string)
+	if wait <= 0 || len(iv) == 0 || len(in.ConsumerServiceId) == 0 {
+		return FindInstances(ctx, in)
+	}
+
+	// subscribe before finding, so that no event is missed in between
+	subscriber := event.NewInstanceSubscriber(in.ConsumerServiceId, util.ParseDomainProject(ctx))
+	if err := event.Center().AddSubscriber(subscriber); err != nil {
+		log.Error(fmt.Sprintf("subscribe the instance events of consumer %s failed", in.ConsumerServiceId), err)
+		return FindInstances(ctx, in)
+	}
+	defer event.Center().RemoveSubscriber(subscriber)
+
+	resp, err := FindInstances(ctx, in)
+	if err != nil || !notModified(ctx) {
+		return resp, err
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case evt, ok := <-subscriber.Job:
+			if !ok {
+				return resp, nil
+			}
+			metrics.ReportPublishCompleted(evt, nil)
+			// bypass the cache, it may not be refreshed when the event comes
+			findCtx := util.WithNoCache(util.CloneContext(ctx))
+			latest, err := FindInstances(findCtx, in)
+			if err != nil {
+				log.Error(fmt.Sprintf("find instances of consumer %s after changed failed", in.ConsumerServiceId), err)
+				continue
+			}
+			if notModified(findCtx) {
+				continue
+			}
+			ov, _ := findCtx.Value(util.CtxResponseRevision).(string)
+			util.WithResponseRev(ctx, ov)
+			return latest, nil
+		case <-timer.C:
+			return resp, nil
+		case <-ctx.Done():
+			return resp, nil
+		}
+	}
+}
+
+func notModified(ctx context.Context) bool {
+	iv, _ := ctx.Value(util.CtxRequestRevision).(string)
+	ov, _ := ctx.Value(util.CtxResponseRevision).(string)
+	return len(iv) > 0 && iv == ov
+}
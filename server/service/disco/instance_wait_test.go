@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disco_test
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
+)
+
+func TestParseWaitTimeout(t *testing.T) {
+	d, err := discosvc.ParseWaitTimeout("")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), d)
+
+	d, err = discosvc.ParseWaitTimeout("30s")
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, d)
+
+	d, err = discosvc.ParseWaitTimeout("10m")
+	assert.NoError(t, err)
+	assert.Equal(t, discosvc.MaxWaitTimeout, d)
+
+	_, err = discosvc.ParseWaitTimeout("-1s")
+	assert.Error(t, err)
+
+	_, err = discosvc.ParseWaitTimeout("xxx")
+	assert.Error(t, err)
+}
+
+func TestWaitInstances(t *testing.T) {
+	var (
+		providerID string
+		consumerID string
+	)
+	ctx := getContext()
+	defer discosvc.UnregisterManyService(ctx, &pb.DelServicesRequest{ServiceIds: []string{
+		providerID, consumerID,
+	}, Force: true})
+
+	t.Run("prepare data, should be passed", func(t *testing.T) {
+		respCreate, err := discosvc.RegisterService(ctx, &pb.CreateServiceRequest{
+			Service: &pb.MicroService{
+				AppId:       "wait_instance",
+				ServiceName: "wait_instance_provider",
+				Version:     "1.0.0",
+				Level:       "FRONT",
+				Status:      pb.MS_UP,
+			},
+		})
+		assert.NoError(t, err)
+		providerID = respCreate.ServiceId
+
+		respCreate, err = discosvc.RegisterService(ctx, &pb.CreateServiceRequest{
+			Service: &pb.MicroService{
+				AppId:       "wait_instance",
+				ServiceName: "wait_instance_consumer",
+				Version:     "1.0.0",
+				Level:       "FRONT",
+				Status:      pb.MS_UP,
+			},
+		})
+		assert.NoError(t, err)
+		consumerID = respCreate.ServiceId
+
+		_, err = discosvc.RegisterInstance(ctx, &pb.RegisterInstanceRequest{
+			Instance: &pb.MicroServiceInstance{
+				ServiceId: providerID,
+				HostName:  "UT-HOST-WAIT",
+				Endpoints: []string{"wait:127.0.0.1:8080"},
+				Status:    pb.MSI_UP,
+			},
+		})
+		assert.NoError(t, err)
+	})
+
+	request := &pb.FindInstancesRequest{
+		ConsumerServiceId: consumerID,
+		AppId:             "wait_instance",
+		ServiceName:       "wait_instance_provider",
+	}
+	findCtx := util.CloneContext(ctx)
+	resp, err := discosvc.FindInstances(findCtx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(resp.Instances))
+	rev, _ := findCtx.Value(util.CtxResponseRevision).(string)
+	assert.NotEmpty(t, rev)
+
+	t.Run("not modified until timeout, should return the same revision", func(t *testing.T) {
+		waitCtx := util.WithRequestRev(util.CloneContext(ctx), rev)
+		start := time.Now()
+		_, err := discosvc.WaitInstances(waitCtx, request, 500*time.Millisecond)
+		assert.NoError(t, err)
+		assert.True(t, time.Since(start) >= 500*time.Millisecond)
+		ov, _ := waitCtx.Value(util.CtxResponseRevision).(string)
+		assert.Equal(t, rev, ov)
+	})
+
+	t.Run("instance registered, should return the latest instances", func(t *testing.T) {
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			_, err := discosvc.RegisterInstance(ctx, &pb.RegisterInstanceRequest{
+				Instance: &pb.MicroServiceInstance{
+					ServiceId: providerID,
+					HostName:  "UT-HOST-WAIT",
+					Endpoints: []string{"wait:127.0.0.1:8081"},
+					Status:    pb.MSI_UP,
+				},
+			})
+			assert.NoError(t, err)
+		}()
+
+		waitCtx := util.WithRequestRev(util.CloneContext(ctx), rev)
+		resp, err := discosvc.WaitInstances(waitCtx, request, 10*time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(resp.Instances))
+		ov, _ := waitCtx.Value(util.CtxResponseRevision).(string)
+		assert.NotEqual(t, rev, ov)
+	})
+}
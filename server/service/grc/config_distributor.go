@@ -20,6 +20,8 @@ package grc
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 
 	model "github.com/apache/servicecomb-service-center/pkg/gov"
 	"github.com/apache/servicecomb-service-center/pkg/log"
@@ -28,12 +30,16 @@ import (
 )
 
 const (
-	ConfigDistributorKie   = "kie"
-	ConfigDistributorIstio = "istio"
-	ConfigDistributorMock  = "mock"
+	ConfigDistributorKie    = "kie"
+	ConfigDistributorIstio  = "istio"
+	ConfigDistributorMock   = "mock"
+	ConfigDistributorNative = "native"
 )
 
-var ErrNoConfig = errors.New("no gov config")
+var (
+	ErrNoConfig          = errors.New("no gov config")
+	ErrWatchNotSupported = errors.New("the config distributor does not support watch")
+)
 
 type NewDistributors func(opts config.DistributorOptions) (ConfigDistributor, error)
 
@@ -55,6 +61,13 @@ type ConfigDistributor interface {
 	Name() string
 }
 
+// Watcher is implemented by the ConfigDistributor which can notify the policy changes,
+// Watch blocks until the project revision differs from revision or ctx is done,
+// it returns nil response when ctx is done.
+type Watcher interface {
+	Watch(ctx context.Context, project, app, env string, revision int64) (*model.WatchResponse, error)
+}
+
 // InstallDistributor install a plugin to distribute and persist config
 func InstallDistributor(t string, newDistributors NewDistributors) {
 	distributorPlugins[t] = newDistributors
@@ -100,7 +113,19 @@ func Init() error {
 
 func Create(ctx context.Context, kind, project string, spec *model.Policy) ([]byte, error) {
 	for _, cd := range distributors {
-		return cd.Create(ctx, kind, project, spec)
+		id, err := cd.Create(ctx, kind, project, spec)
+		if err != nil {
+			return nil, err
+		}
+		created := &model.Policy{Kind: util.ToSnake(kind), Spec: spec.Spec, GovernancePolicy: &model.GovernancePolicy{ID: string(id)}}
+		if spec.GovernancePolicy != nil {
+			created.Name, created.Status, created.Selector = spec.Name, spec.Status, spec.Selector
+		}
+		if created.Status == "" {
+			created.Status = StatusEnabled
+		}
+		recordHistory(ctx, kind, project, &model.History{PolicyID: string(id), Action: model.ActionCreate, Policy: created})
+		return id, nil
 	}
 	return nil, nil
 }
@@ -128,14 +153,63 @@ func Get(ctx context.Context, kind, id, project string) ([]byte, error) {
 
 func Delete(ctx context.Context, kind, id, project string) error {
 	for _, cd := range distributors {
-		return cd.Delete(ctx, kind, id, project)
+		// keep the deleted policies in history, including the ones of the match group
+		deleted := snapshot(ctx, cd, kind, id, project)
+		var policies []*model.Policy
+		if deleted != nil && util.ToSnake(kind) == util.ToSnake(KindMatchGroup) {
+			var err error
+			policies, err = cascaded(ctx, cd, deleted, project)
+			if err != nil {
+				log.Error(fmt.Sprintf("list policies of match group [%s] failed", id), err)
+				return err
+			}
+		}
+		err := cd.Delete(ctx, kind, id, project)
+		if err != nil {
+			return err
+		}
+		if deleted == nil {
+			return nil
+		}
+		recordHistory(ctx, kind, project, &model.History{PolicyID: id, Action: model.ActionDelete, Policy: deleted})
+		for _, p := range policies {
+			recordHistory(ctx, p.Kind, project, &model.History{PolicyID: p.ID, Action: model.ActionDelete, Policy: p})
+		}
+		return nil
 	}
 	return nil
 }
 
 func Update(ctx context.Context, kind, id, project string, p *model.Policy) error {
 	for _, cd := range distributors {
-		return cd.Update(ctx, kind, id, project, p)
+		return update(ctx, cd, kind, id, project, p, &model.History{PolicyID: id, Action: model.ActionUpdate})
+	}
+	return nil
+}
+
+func update(ctx context.Context, cd ConfigDistributor, kind, id, project string, p *model.Policy, h *model.History) error {
+	old := snapshot(ctx, cd, kind, id, project)
+	err := cd.Update(ctx, kind, id, project, p)
+	if err != nil {
+		return err
 	}
+	h.Policy = applyUpdate(old, p)
+	recordHistory(ctx, kind, project, h)
 	return nil
 }
+
+// Watch uses the first distributor supporting watch in the order of names,
+// so that the same distributor is watched every time
+func Watch(ctx context.Context, project, app, env string, revision int64) (*model.WatchResponse, error) {
+	names := make([]string, 0, len(distributors))
+	for name := range distributors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if w, ok := distributors[name].(Watcher); ok {
+			return w.Watch(ctx, project, app, env, revision)
+		}
+	}
+	return nil, ErrWatchNotSupported
+}
@@ -0,0 +1,192 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-chassis/cari/discovery"
+
+	govds "github.com/apache/servicecomb-service-center/datasource/gov"
+	model "github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+// WithProject sets the project of governance to ctx, the domain is default if it is absent
+func WithProject(ctx context.Context, project string) context.Context {
+	domain := util.ParseDomain(ctx)
+	if domain == "" {
+		domain = "default"
+	}
+	return util.SetDomainProject(ctx, domain, project)
+}
+
+var ErrHistoryNotSupported = discovery.NewError(discovery.ErrInvalidParams, "policy history is not supported")
+
+// ListHistory returns all versions of the policy, including the deletion
+func ListHistory(ctx context.Context, project, id string) ([]*model.History, error) {
+	if govds.Instance() == nil {
+		return nil, ErrHistoryNotSupported
+	}
+	return govds.Instance().ListHistory(WithProject(ctx, project), id)
+}
+
+func GetHistory(ctx context.Context, project, id string, version int64) (*model.History, error) {
+	if govds.Instance() == nil {
+		return nil, ErrHistoryNotSupported
+	}
+	return govds.Instance().GetHistory(WithProject(ctx, project), id, version)
+}
+
+// CompareHistory returns the changes from version from to version to
+func CompareHistory(ctx context.Context, project, id string, from, to int64) ([]*model.Change, error) {
+	fromHistory, err := GetHistory(ctx, project, id, from)
+	if err != nil {
+		return nil, err
+	}
+	toHistory, err := GetHistory(ctx, project, id, to)
+	if err != nil {
+		return nil, err
+	}
+	return model.Diff(fromHistory.Policy, toHistory.Policy), nil
+}
+
+// Rollback restores the spec and status of the policy to the version,
+// the rollback itself is saved as a new version.
+func Rollback(ctx context.Context, kind, id, project string, version int64) error {
+	h, err := GetHistory(ctx, project, id, version)
+	if err != nil {
+		return err
+	}
+	if h.Action == model.ActionDelete || h.Policy == nil {
+		return discovery.NewError(discovery.ErrInvalidParams,
+			fmt.Sprintf("can not roll back to version %d of action %s", version, h.Action))
+	}
+	if err = ValidatePolicySpec(kind, h.Policy.Spec); err != nil {
+		return discovery.NewError(discovery.ErrInvalidParams, err.Error())
+	}
+	p := &model.Policy{
+		GovernancePolicy: &model.GovernancePolicy{
+			Name:     h.Policy.Name,
+			Status:   h.Policy.Status,
+			Selector: h.Policy.Selector,
+		},
+		Kind: h.Policy.Kind,
+		Spec: h.Policy.Spec,
+	}
+	for _, cd := range distributors {
+		return update(ctx, cd, kind, id, project, p, &model.History{PolicyID: id, Action: model.ActionRollback, RollbackTo: version})
+	}
+	return nil
+}
+
+// recordHistory saves h as the next version of the policy, it runs after the change is saved,
+// so a failure is only logged, failing the change would make the client retry a saved change
+func recordHistory(ctx context.Context, kind, project string, h *model.History) {
+	if govds.Instance() == nil {
+		return
+	}
+	hctx := WithProject(util.CloneContext(ctx), project)
+	list, err := govds.Instance().ListHistory(hctx, h.PolicyID)
+	if err != nil {
+		log.Error(fmt.Sprintf("list history of policy [%s] failed", h.PolicyID), err)
+		return
+	}
+	var last *model.Policy
+	if len(list) > 0 && list[len(list)-1].Action != model.ActionDelete {
+		last = list[len(list)-1].Policy
+	}
+	if h.Action == model.ActionDelete {
+		h.Diff = model.Diff(h.Policy, nil)
+	} else {
+		h.Diff = model.Diff(last, h.Policy)
+	}
+	h.Kind = util.ToSnake(kind)
+	h.Author = rbacsvc.UserFromContext(ctx)
+	h.Timestamp = time.Now().Unix()
+	if err = govds.Instance().AddHistory(hctx, h); err != nil {
+		log.Error(fmt.Sprintf("save history of policy [%s] failed", h.PolicyID), err)
+	}
+}
+
+// applyUpdate returns the policy after updating old with p, the same as the distributors do,
+// so the history does not depend on reading the distributor again after the change
+func applyUpdate(old, p *model.Policy) *model.Policy {
+	if old == nil {
+		return p
+	}
+	r := &model.Policy{Kind: old.Kind, Spec: p.Spec}
+	if old.GovernancePolicy != nil {
+		gp := *old.GovernancePolicy
+		r.GovernancePolicy = &gp
+	} else {
+		r.GovernancePolicy = &model.GovernancePolicy{}
+	}
+	if p.GovernancePolicy != nil && p.Status != "" {
+		r.Status = p.Status
+	}
+	return r
+}
+
+// cascaded returns the policies removed together with the match group
+func cascaded(ctx context.Context, cd ConfigDistributor, group *model.Policy, project string) ([]*model.Policy, error) {
+	if group.GovernancePolicy == nil {
+		return nil, nil
+	}
+	app, env := group.Selector[KeyApp], group.Selector[KeyEnvironment]
+	var r []*model.Policy
+	for _, kind := range PolicyNames {
+		b, err := cd.List(ctx, kind, project, app, env)
+		if err != nil {
+			return nil, err
+		}
+		var list []*model.Policy
+		if err = json.Unmarshal(b, &list); err != nil {
+			return nil, err
+		}
+		for _, p := range list {
+			if p.GovernancePolicy == nil || p.Name != group.Name || p.ID == group.ID {
+				continue
+			}
+			if p.Kind == "" {
+				p.Kind = kind
+			}
+			r = append(r, p)
+		}
+	}
+	return r, nil
+}
+
+// snapshot returns the policy in distributor before the change, or nil if it can not be got
+func snapshot(ctx context.Context, cd ConfigDistributor, kind, id, project string) *model.Policy {
+	b, err := cd.Get(ctx, kind, id, project)
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+	p := &model.Policy{}
+	if err = json.Unmarshal(b, p); err != nil {
+		log.Error(fmt.Sprintf("decode policy [%s] failed", id), err)
+		return nil
+	}
+	return p
+}
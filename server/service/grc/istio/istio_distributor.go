@@ -0,0 +1,446 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/gofrs/uuid"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	grcsvc "github.com/apache/servicecomb-service-center/server/service/grc"
+)
+
+const (
+	LabelManagedBy   = "app.kubernetes.io/managed-by"
+	LabelKind        = "servicecomb.io/gov-kind"
+	LabelApp         = "servicecomb.io/app"
+	LabelEnvironment = "servicecomb.io/environment"
+	LabelMatchGroup  = "servicecomb.io/match-group"
+
+	ManagedBy = "servicecomb-service-center"
+	// DataKey is the config map data key holding the servicecomb policy
+	DataKey = "policy"
+)
+
+// Distributor persists the servicecomb policies as config maps in the namespace named
+// after the project, and distributes each match group with its policies as istio resources,
+// see Translate.
+type Distributor struct {
+	name   string
+	kube   kubernetes.Interface
+	client dynamic.Interface
+}
+
+func (d *Distributor) Create(ctx context.Context, kind, project string, p *gov.Policy) ([]byte, error) {
+	kind = util.ToSnake(kind)
+	if kind == kindMatchGroup {
+		if err := d.generateName(ctx, project, p); err != nil {
+			return nil, err
+		}
+		grcsvc.SetAliasIfEmpty(p.Spec, p.Name)
+	}
+	if err := CheckSpec(kind, p.Spec); err != nil {
+		return nil, discovery.NewError(discovery.ErrInvalidParams, err.Error())
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	p.ID = id.String()
+	p.Kind = kind
+	p.CreatTime = now
+	p.UpdateTime = now
+	if p.Status == "" {
+		p.Status = grcsvc.StatusEnabled
+	}
+	cm, err := toConfigMap(project, p)
+	if err != nil {
+		return nil, err
+	}
+	_, err = d.kube.CoreV1().ConfigMaps(project).Create(ctx, cm, metav1.CreateOptions{})
+	if err != nil {
+		log.Error("istio create policy failed", err)
+		return nil, err
+	}
+	if err = d.sync(ctx, project, p); err != nil {
+		return nil, err
+	}
+	return []byte(p.ID), nil
+}
+
+func (d *Distributor) Update(ctx context.Context, kind, id, project string, p *gov.Policy) error {
+	kind = util.ToSnake(kind)
+	cm, err := d.kube.CoreV1().ConfigMaps(project).Get(ctx, id, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	old, err := fromConfigMap(cm)
+	if err != nil {
+		return err
+	}
+	if kind == kindMatchGroup {
+		grcsvc.SetAliasIfEmpty(p.Spec, old.Name)
+	}
+	if err = CheckSpec(kind, p.Spec); err != nil {
+		return discovery.NewError(discovery.ErrInvalidParams, err.Error())
+	}
+	old.Spec = p.Spec
+	if p.Status != "" {
+		old.Status = p.Status
+	}
+	old.UpdateTime = time.Now().Unix()
+	b, err := json.Marshal(old)
+	if err != nil {
+		return err
+	}
+	cm.Data = map[string]string{DataKey: string(b)}
+	_, err = d.kube.CoreV1().ConfigMaps(project).Update(ctx, cm, metav1.UpdateOptions{})
+	if err != nil {
+		log.Error("istio update policy failed", err)
+		return err
+	}
+	return d.sync(ctx, project, old)
+}
+
+func (d *Distributor) Delete(ctx context.Context, kind, id, project string) error {
+	cm, err := d.kube.CoreV1().ConfigMaps(project).Get(ctx, id, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	p, err := fromConfigMap(cm)
+	if err != nil {
+		return err
+	}
+	if p.Kind != kindMatchGroup {
+		if err = d.deleteConfigMap(ctx, project, id); err != nil {
+			return err
+		}
+		return d.sync(ctx, project, p)
+	}
+	// should remove all policies of this group
+	policies, err := d.list(ctx, project, "", p.Selector[grcsvc.KeyApp], p.Selector[grcsvc.KeyEnvironment])
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if policy.Name != p.Name || policy.ID == id {
+			continue
+		}
+		if err = d.deleteConfigMap(ctx, project, policy.ID); err != nil {
+			return err
+		}
+	}
+	if err = d.apply(ctx, project, id, nil); err != nil {
+		return err
+	}
+	return d.deleteConfigMap(ctx, project, id)
+}
+
+func (d *Distributor) Display(ctx context.Context, project, app, env string) ([]byte, error) {
+	list, err := d.list(ctx, project, "", app, env)
+	if err != nil {
+		return nil, err
+	}
+	policyMap := make(map[string]*gov.Policy)
+	for _, p := range list {
+		policyMap[p.Name+p.Kind] = p
+	}
+	r := make([]*gov.DisplayData, 0)
+	for _, match := range list {
+		if match.Kind != kindMatchGroup {
+			continue
+		}
+		var policies []*gov.Policy
+		for _, kind := range grcsvc.PolicyNames {
+			if policyMap[match.Name+kind] != nil {
+				policies = append(policies, policyMap[match.Name+kind])
+			}
+		}
+		r = append(r, &gov.DisplayData{
+			Policies:   policies,
+			MatchGroup: match,
+		})
+	}
+	b, _ := json.MarshalIndent(r, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) List(ctx context.Context, kind, project, app, env string) ([]byte, error) {
+	r, err := d.list(ctx, project, util.ToSnake(kind), app, env)
+	if err != nil {
+		return nil, err
+	}
+	b, _ := json.MarshalIndent(r, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) Get(ctx context.Context, _, id, project string) ([]byte, error) {
+	cm, err := d.kube.CoreV1().ConfigMaps(project).Get(ctx, id, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	p, err := fromConfigMap(cm)
+	if err != nil {
+		return nil, err
+	}
+	b, _ := json.MarshalIndent(p, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) Type() string {
+	return grcsvc.ConfigDistributorIstio
+}
+func (d *Distributor) Name() string {
+	return d.name
+}
+
+func (d *Distributor) list(ctx context.Context, project, kind, app, env string) ([]*gov.Policy, error) {
+	selector := labels.Set{LabelManagedBy: ManagedBy}
+	if kind != "" {
+		selector[LabelKind] = kind
+	}
+	if app != "" {
+		selector[LabelApp] = app
+	}
+	if env != grcsvc.EnvAll {
+		selector[LabelEnvironment] = env
+	}
+	cms, err := d.kube.CoreV1().ConfigMaps(project).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		log.Error("istio list policies failed", err)
+		return nil, err
+	}
+	r := make([]*gov.Policy, 0, len(cms.Items))
+	for i := range cms.Items {
+		p, err := fromConfigMap(&cms.Items[i])
+		if err != nil {
+			log.Warn(fmt.Sprintf("transform config map [%s] failed: %s", cms.Items[i].Name, err))
+			continue
+		}
+		r = append(r, p)
+	}
+	return r, nil
+}
+
+func (d *Distributor) deleteConfigMap(ctx context.Context, project, id string) error {
+	err := d.kube.CoreV1().ConfigMaps(project).Delete(ctx, id, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Error("istio delete policy failed", err)
+		return err
+	}
+	return nil
+}
+
+// sync re-distributes the match group the policy p belongs to
+func (d *Distributor) sync(ctx context.Context, project string, p *gov.Policy) error {
+	app, env := p.Selector[grcsvc.KeyApp], p.Selector[grcsvc.KeyEnvironment]
+	list, err := d.list(ctx, project, "", app, env)
+	if err != nil {
+		return err
+	}
+	var group *gov.Policy
+	policies := make(map[string]*gov.Policy)
+	for _, item := range list {
+		if item.Name != p.Name || item.Selector[grcsvc.KeyApp] != app || item.Selector[grcsvc.KeyEnvironment] != env {
+			continue
+		}
+		if item.Kind == kindMatchGroup {
+			group = item
+			continue
+		}
+		if item.Status == grcsvc.StatusEnabled {
+			policies[item.Kind] = item
+		}
+	}
+	if group == nil {
+		// nothing to distribute until the match group is created
+		return nil
+	}
+	var objects []*unstructured.Unstructured
+	if group.Status == grcsvc.StatusEnabled {
+		objects, err = Translate(project, group, policies)
+		if err != nil {
+			return discovery.NewError(discovery.ErrInvalidParams, err.Error())
+		}
+	}
+	return d.apply(ctx, project, group.ID, objects)
+}
+
+// apply makes the istio resources of the match group the same as objects
+func (d *Distributor) apply(ctx context.Context, namespace, groupID string, objects []*unstructured.Unstructured) error {
+	desired := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		desired[obj.GetKind()+"/"+obj.GetName()] = true
+		l := obj.GetLabels()
+		if l == nil {
+			l = map[string]string{}
+		}
+		l[LabelManagedBy] = ManagedBy
+		l[LabelMatchGroup] = groupID
+		obj.SetLabels(l)
+		if err := d.put(ctx, namespace, obj); err != nil {
+			log.Error(fmt.Sprintf("istio put %s [%s] failed", obj.GetKind(), obj.GetName()), err)
+			return err
+		}
+	}
+	selector := labels.Set{LabelManagedBy: ManagedBy, LabelMatchGroup: groupID}.String()
+	for kind, gvr := range resources {
+		list, err := d.client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			log.Error(fmt.Sprintf("istio list %s failed", kind), err)
+			return err
+		}
+		for _, item := range list.Items {
+			if desired[kind+"/"+item.GetName()] {
+				continue
+			}
+			err = d.client.Resource(gvr).Namespace(namespace).Delete(ctx, item.GetName(), metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				log.Error(fmt.Sprintf("istio delete %s [%s] failed", kind, item.GetName()), err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *Distributor) put(ctx context.Context, namespace string, obj *unstructured.Unstructured) error {
+	c := d.client.Resource(resources[obj.GetKind()]).Namespace(namespace)
+	existing, err := c.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = c.Create(ctx, obj, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	_, err = c.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+func (d *Distributor) generateName(ctx context.Context, project string, p *gov.Policy) error {
+	if p.Name != "" {
+		return nil
+	}
+	list, err := d.list(ctx, project, kindMatchGroup, p.Selector[grcsvc.KeyApp], p.Selector[grcsvc.KeyEnvironment])
+	if err != nil {
+		return err
+	}
+	names := make(map[string]bool, len(list))
+	for _, item := range list {
+		names[item.Name] = true
+	}
+	for {
+		name := grcsvc.NewGroupName()
+		if !names[name] {
+			p.Name = name
+			return nil
+		}
+	}
+}
+
+func toConfigMap(project string, p *gov.Policy) (*corev1.ConfigMap, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      p.ID,
+			Namespace: project,
+			Labels: map[string]string{
+				LabelManagedBy:   ManagedBy,
+				LabelKind:        p.Kind,
+				LabelApp:         p.Selector[grcsvc.KeyApp],
+				LabelEnvironment: p.Selector[grcsvc.KeyEnvironment],
+			},
+		},
+		Data: map[string]string{DataKey: string(b)},
+	}, nil
+}
+
+func fromConfigMap(cm *corev1.ConfigMap) (*gov.Policy, error) {
+	p := &gov.Policy{GovernancePolicy: &gov.GovernancePolicy{Selector: gov.Selector{}}}
+	if err := json.Unmarshal([]byte(cm.Data[DataKey]), p); err != nil {
+		return nil, err
+	}
+	p.ID = cm.Name
+	return p, nil
+}
+
+// newKubeConfig creates the in-cluster config if path is empty,
+// the same as the istio connector does
+func newKubeConfig(path string) (*rest.Config, error) {
+	if path == "" {
+		conf, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("build default in cluster kube config failed: %w", err)
+		}
+		return conf, nil
+	}
+	conf, err := clientcmd.BuildConfigFromFlags("", path)
+	if err != nil {
+		return nil, fmt.Errorf("build kube client config from config file failed: %w", err)
+	}
+	return conf, nil
+}
+
+// newDistributor uses the endpoint as the kubeconfig path
+func newDistributor(opts config.DistributorOptions) (grcsvc.ConfigDistributor, error) {
+	conf, err := newKubeConfig(opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	kube, err := kubernetes.NewForConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &Distributor{name: opts.Name, kube: kube, client: client}, nil
+}
+
+func init() {
+	grcsvc.InstallDistributor(grcsvc.ConfigDistributorIstio, newDistributor)
+}
@@ -0,0 +1,543 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	grcsvc "github.com/apache/servicecomb-service-center/server/service/grc"
+)
+
+const (
+	KindVirtualService  = "VirtualService"
+	KindDestinationRule = "DestinationRule"
+	KindEnvoyFilter     = "EnvoyFilter"
+
+	apiVersion = "networking.istio.io/v1alpha3"
+
+	defaultRoute            = "default"
+	defaultAbortStatus      = 500
+	defaultEjectionTime     = time.Minute
+	defaultDetectInterval   = 10 * time.Second
+	defaultRefreshPeriod    = time.Second
+	localRateLimitFilter    = "envoy.filters.http.local_ratelimit"
+	httpConnManagerFilter   = "envoy.filters.network.http_connection_manager"
+	localRateLimitStatsName = "http_local_rate_limiter"
+)
+
+var (
+	kindRetry          = util.ToSnake("retry")
+	kindRateLimiting   = util.ToSnake("rate-limiting")
+	kindCircuitBreaker = util.ToSnake("circuit-breaker")
+	kindFaultInjection = util.ToSnake("fault-injection")
+	kindLoadBalance    = util.ToSnake("loadbalance")
+	kindMatchGroup     = util.ToSnake(grcsvc.KindMatchGroup)
+
+	// defaultRetryOn follows the sdk, which retries on network errors, 502 and 503
+	defaultRetryOn = []string{"connect-failure", "refused-stream", "502", "503"}
+
+	loadBalancers = map[string]string{
+		"roundrobin":   "ROUND_ROBIN",
+		"round_robin":  "ROUND_ROBIN",
+		"random":       "RANDOM",
+		"leastrequest": "LEAST_CONN",
+		"least_conn":   "LEAST_CONN",
+		"passthrough":  "PASSTHROUGH",
+	}
+
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+	resources = map[string]schema.GroupVersionResource{
+		KindVirtualService:  {Group: "networking.istio.io", Version: "v1alpha3", Resource: "virtualservices"},
+		KindDestinationRule: {Group: "networking.istio.io", Version: "v1alpha3", Resource: "destinationrules"},
+		KindEnvoyFilter:     {Group: "networking.istio.io", Version: "v1alpha3", Resource: "envoyfilters"},
+	}
+)
+
+type matchGroupSpec struct {
+	Alias    string       `json:"alias,omitempty"`
+	Services string       `json:"services,omitempty"`
+	Matches  []*matchItem `json:"matches,omitempty"`
+}
+
+type matchItem struct {
+	Name    string                       `json:"name,omitempty"`
+	APIPath map[string]string            `json:"apiPath,omitempty"`
+	Headers map[string]map[string]string `json:"headers,omitempty"`
+	Method  []string                     `json:"method,omitempty"`
+	Methods []string                     `json:"methods,omitempty"`
+}
+
+type retrySpec struct {
+	MaxAttempts           int64   `json:"maxAttempts"`
+	RetryOnResponseStatus []int64 `json:"retryOnResponseStatus,omitempty"`
+}
+
+type faultSpec struct {
+	Type       string      `json:"type,omitempty"`
+	Percentage float64     `json:"percentage"`
+	DelayTime  interface{} `json:"delayTime,omitempty"`
+	ErrorCode  int64       `json:"errorCode,omitempty"`
+}
+
+type loadBalanceSpec struct {
+	Rule string `json:"rule"`
+}
+
+type circuitBreakerSpec struct {
+	MinimumNumberOfCalls    int64       `json:"minimumNumberOfCalls"`
+	SlidingWindowSize       interface{} `json:"slidingWindowSize,omitempty"`
+	WaitDurationInOpenState interface{} `json:"waitDurationInOpenState,omitempty"`
+}
+
+type rateLimitingSpec struct {
+	Rate               float64     `json:"rate"`
+	Burst              float64     `json:"burst,omitempty"`
+	LimitRefreshPeriod interface{} `json:"limitRefreshPeriod,omitempty"`
+}
+
+// Translate converts a match group and the policies sharing its name into istio resources.
+// Each service listed in the group's "services" gets a VirtualService carrying the matches,
+// retries and faults, a DestinationRule carrying load balancing and circuit breaking,
+// and an EnvoyFilter carrying the local rate limit of the service workloads.
+// Policies are keyed by the snake kind, see util.ToSnake.
+func Translate(namespace string, group *gov.Policy, policies map[string]*gov.Policy) ([]*unstructured.Unstructured, error) {
+	mg := &matchGroupSpec{}
+	if err := decodeSpec(group.Spec, mg); err != nil {
+		return nil, err
+	}
+	route, err := toHTTPRoute(group.Name, mg, policies)
+	if err != nil {
+		return nil, err
+	}
+	trafficPolicy, err := toTrafficPolicy(policies)
+	if err != nil {
+		return nil, err
+	}
+	var limit map[string]interface{}
+	if p, ok := policies[kindRateLimiting]; ok {
+		if limit, err = toLocalRateLimit(p.Spec); err != nil {
+			return nil, err
+		}
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, host := range splitServices(mg.Services) {
+		name := ResourceName(group.ID, host)
+		hostRoute := copyRoute(route, host)
+		objects = append(objects, newObject(KindVirtualService, namespace, name, map[string]interface{}{
+			"hosts": []interface{}{host},
+			"http":  []interface{}{hostRoute, withDestination(map[string]interface{}{"name": defaultRoute}, host)},
+		}))
+		if len(trafficPolicy) > 0 {
+			objects = append(objects, newObject(KindDestinationRule, namespace, name, map[string]interface{}{
+				"host":          host,
+				"trafficPolicy": trafficPolicy,
+			}))
+		}
+		if limit != nil {
+			objects = append(objects, newObject(KindEnvoyFilter, namespace, name, toEnvoyFilterSpec(host, limit)))
+		}
+	}
+	return objects, nil
+}
+
+// CheckSpec returns an error when the policy spec can not be translated to istio resources
+func CheckSpec(kind string, spec map[string]interface{}) error {
+	var err error
+	switch util.ToSnake(kind) {
+	case kindMatchGroup:
+		mg := &matchGroupSpec{}
+		if err = decodeSpec(spec, mg); err == nil {
+			_, err = toHTTPMatches(mg.Matches)
+		}
+	case kindRetry:
+		_, err = toRetries(spec)
+	case kindFaultInjection:
+		_, err = toFault(spec)
+	case kindLoadBalance:
+		_, err = toLoadBalancer(spec)
+	case kindCircuitBreaker:
+		_, err = toOutlierDetection(spec)
+	case kindRateLimiting:
+		_, err = toLocalRateLimit(spec)
+	}
+	return err
+}
+
+// ResourceName returns the istio resource name of the match group for the host
+func ResourceName(groupID, host string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(groupID+"-"+host), "-"), "-.")
+	if len(name) > 253 {
+		name = strings.Trim(name[:253], "-.")
+	}
+	return name
+}
+
+func newObject(kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func decodeSpec(spec map[string]interface{}, v interface{}) error {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func splitServices(services string) []string {
+	var hosts []string
+	for _, s := range strings.Split(services, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			hosts = append(hosts, s)
+		}
+	}
+	return hosts
+}
+
+func toHTTPRoute(name string, mg *matchGroupSpec, policies map[string]*gov.Policy) (map[string]interface{}, error) {
+	matches, err := toHTTPMatches(mg.Matches)
+	if err != nil {
+		return nil, err
+	}
+	route := map[string]interface{}{"name": name, "match": matches}
+	if p, ok := policies[kindRetry]; ok {
+		retries, err := toRetries(p.Spec)
+		if err != nil {
+			return nil, err
+		}
+		route["retries"] = retries
+	}
+	if p, ok := policies[kindFaultInjection]; ok {
+		fault, err := toFault(p.Spec)
+		if err != nil {
+			return nil, err
+		}
+		route["fault"] = fault
+	}
+	return route, nil
+}
+
+func copyRoute(route map[string]interface{}, host string) map[string]interface{} {
+	r := make(map[string]interface{}, len(route)+1)
+	for k, v := range route {
+		r[k] = v
+	}
+	return withDestination(r, host)
+}
+
+func withDestination(route map[string]interface{}, host string) map[string]interface{} {
+	route["route"] = []interface{}{
+		map[string]interface{}{"destination": map[string]interface{}{"host": host}},
+	}
+	return route
+}
+
+func toHTTPMatches(items []*matchItem) ([]interface{}, error) {
+	var matches []interface{}
+	for _, item := range items {
+		match := map[string]interface{}{}
+		if item.Name != "" {
+			match["name"] = item.Name
+		}
+		if len(item.APIPath) > 0 {
+			uri, err := toStringMatch(item.APIPath)
+			if err != nil {
+				return nil, fmt.Errorf("match [%s] apiPath: %w", item.Name, err)
+			}
+			match["uri"] = uri
+		}
+		if len(item.Headers) > 0 {
+			headers := make(map[string]interface{}, len(item.Headers))
+			for header, m := range item.Headers {
+				hm, err := toStringMatch(m)
+				if err != nil {
+					return nil, fmt.Errorf("match [%s] header [%s]: %w", item.Name, header, err)
+				}
+				headers[header] = hm
+			}
+			match["headers"] = headers
+		}
+		methods := item.Method
+		if len(methods) == 0 {
+			methods = item.Methods
+		}
+		if len(methods) == 0 {
+			matches = append(matches, match)
+			continue
+		}
+		// istio ORs the match requests, so one request per method
+		for _, method := range methods {
+			m := make(map[string]interface{}, len(match)+1)
+			for k, v := range match {
+				m[k] = v
+			}
+			m["method"] = map[string]interface{}{"exact": strings.ToUpper(method)}
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
+
+func toStringMatch(m map[string]string) (map[string]interface{}, error) {
+	if len(m) != 1 {
+		return nil, fmt.Errorf("exactly one operator is required, got %d", len(m))
+	}
+	for op, v := range m {
+		switch op {
+		case "exact", "prefix", "regex":
+			return map[string]interface{}{op: v}, nil
+		case "suffix":
+			return map[string]interface{}{"regex": ".*" + regexp.QuoteMeta(v)}, nil
+		case "contains":
+			return map[string]interface{}{"regex": ".*" + regexp.QuoteMeta(v) + ".*"}, nil
+		default:
+			return nil, fmt.Errorf("operator [%s] can not be translated to istio", op)
+		}
+	}
+	return nil, nil
+}
+
+func toRetries(spec map[string]interface{}) (map[string]interface{}, error) {
+	s := &retrySpec{}
+	if err := decodeSpec(spec, s); err != nil {
+		return nil, err
+	}
+	if s.MaxAttempts < 0 {
+		return nil, fmt.Errorf("invalid maxAttempts %d", s.MaxAttempts)
+	}
+	retryOn := defaultRetryOn
+	if len(s.RetryOnResponseStatus) > 0 {
+		retryOn = []string{"connect-failure", "refused-stream"}
+		for _, code := range s.RetryOnResponseStatus {
+			retryOn = append(retryOn, strconv.FormatInt(code, 10))
+		}
+	}
+	// maxAttempts of the sdk counts the first call, while istio attempts do not
+	attempts := s.MaxAttempts - 1
+	if attempts < 0 {
+		attempts = 0
+	}
+	return map[string]interface{}{
+		"attempts": attempts,
+		"retryOn":  strings.Join(retryOn, ","),
+	}, nil
+}
+
+func toFault(spec map[string]interface{}) (map[string]interface{}, error) {
+	s := &faultSpec{}
+	if err := decodeSpec(spec, s); err != nil {
+		return nil, err
+	}
+	if s.Percentage < 0 || s.Percentage > 100 {
+		return nil, fmt.Errorf("invalid percentage %v", s.Percentage)
+	}
+	percentage := map[string]interface{}{"value": s.Percentage}
+	switch strings.ToLower(s.Type) {
+	case "abort":
+		code := s.ErrorCode
+		if code == 0 {
+			code = defaultAbortStatus
+		}
+		return map[string]interface{}{
+			"abort": map[string]interface{}{"percentage": percentage, "httpStatus": code},
+		}, nil
+	case "", "delay":
+		delay, err := parseDuration(s.DelayTime, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid delayTime: %w", err)
+		}
+		return map[string]interface{}{
+			"delay": map[string]interface{}{"percentage": percentage, "fixedDelay": formatDuration(delay)},
+		}, nil
+	default:
+		return nil, fmt.Errorf("fault type [%s] can not be translated to istio", s.Type)
+	}
+}
+
+func toTrafficPolicy(policies map[string]*gov.Policy) (map[string]interface{}, error) {
+	trafficPolicy := map[string]interface{}{}
+	if p, ok := policies[kindLoadBalance]; ok {
+		lb, err := toLoadBalancer(p.Spec)
+		if err != nil {
+			return nil, err
+		}
+		trafficPolicy["loadBalancer"] = lb
+	}
+	if p, ok := policies[kindCircuitBreaker]; ok {
+		od, err := toOutlierDetection(p.Spec)
+		if err != nil {
+			return nil, err
+		}
+		trafficPolicy["outlierDetection"] = od
+	}
+	return trafficPolicy, nil
+}
+
+func toLoadBalancer(spec map[string]interface{}) (map[string]interface{}, error) {
+	s := &loadBalanceSpec{}
+	if err := decodeSpec(spec, s); err != nil {
+		return nil, err
+	}
+	simple, ok := loadBalancers[strings.ToLower(s.Rule)]
+	if !ok {
+		return nil, fmt.Errorf("loadbalance rule [%s] can not be translated to istio", s.Rule)
+	}
+	return map[string]interface{}{"simple": simple}, nil
+}
+
+// toOutlierDetection approximates the sdk circuit breaker, the minimum number of calls
+// becomes the consecutive errors ejecting a host, and the open state lasts the ejection time.
+func toOutlierDetection(spec map[string]interface{}) (map[string]interface{}, error) {
+	s := &circuitBreakerSpec{}
+	if err := decodeSpec(spec, s); err != nil {
+		return nil, err
+	}
+	if s.MinimumNumberOfCalls <= 0 {
+		return nil, fmt.Errorf("invalid minimumNumberOfCalls %d", s.MinimumNumberOfCalls)
+	}
+	interval, err := parseDuration(s.SlidingWindowSize, defaultDetectInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slidingWindowSize: %w", err)
+	}
+	ejection, err := parseDuration(s.WaitDurationInOpenState, defaultEjectionTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid waitDurationInOpenState: %w", err)
+	}
+	return map[string]interface{}{
+		"consecutive5xxErrors": s.MinimumNumberOfCalls,
+		"interval":             formatDuration(interval),
+		"baseEjectionTime":     formatDuration(ejection),
+		"maxEjectionPercent":   int64(100),
+	}, nil
+}
+
+func toLocalRateLimit(spec map[string]interface{}) (map[string]interface{}, error) {
+	s := &rateLimitingSpec{}
+	if err := decodeSpec(spec, s); err != nil {
+		return nil, err
+	}
+	if s.Rate < 1 {
+		return nil, fmt.Errorf("invalid rate %v", s.Rate)
+	}
+	period, err := parseDuration(s.LimitRefreshPeriod, defaultRefreshPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("invalid limitRefreshPeriod: %w", err)
+	}
+	tokens := int64(s.Rate)
+	maxTokens := tokens
+	if s.Burst > s.Rate {
+		maxTokens = int64(s.Burst)
+	}
+	percent := map[string]interface{}{
+		"default_value": map[string]interface{}{"numerator": int64(100), "denominator": "HUNDRED"},
+	}
+	return map[string]interface{}{
+		"stat_prefix": localRateLimitStatsName,
+		"token_bucket": map[string]interface{}{
+			"max_tokens":      maxTokens,
+			"tokens_per_fill": tokens,
+			"fill_interval":   formatDuration(period),
+		},
+		"filter_enabled":  withRuntimeKey(percent, "local_rate_limit_enabled"),
+		"filter_enforced": withRuntimeKey(percent, "local_rate_limit_enforced"),
+	}, nil
+}
+
+func withRuntimeKey(percent map[string]interface{}, key string) map[string]interface{} {
+	m := map[string]interface{}{"runtime_key": key}
+	for k, v := range percent {
+		m[k] = v
+	}
+	return m
+}
+
+// toEnvoyFilterSpec limits the inbound requests of the workloads labeled app=<service>,
+// envoy local rate limit works per workload, so the match of the group is not applied.
+func toEnvoyFilterSpec(host string, limit map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"workloadSelector": map[string]interface{}{
+			"labels": map[string]interface{}{"app": strings.SplitN(host, ".", 2)[0]},
+		},
+		"configPatches": []interface{}{
+			map[string]interface{}{
+				"applyTo": "HTTP_FILTER",
+				"match": map[string]interface{}{
+					"context": "SIDECAR_INBOUND",
+					"listener": map[string]interface{}{
+						"filterChain": map[string]interface{}{
+							"filter": map[string]interface{}{"name": httpConnManagerFilter},
+						},
+					},
+				},
+				"patch": map[string]interface{}{
+					"operation": "INSERT_BEFORE",
+					"value": map[string]interface{}{
+						"name": localRateLimitFilter,
+						"typed_config": map[string]interface{}{
+							"@type":    "type.googleapis.com/udpa.type.v1.TypedStruct",
+							"type_url": "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit",
+							"value":    limit,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// parseDuration accepts milliseconds in number or string, or a go duration string
+func parseDuration(v interface{}, def time.Duration) (time.Duration, error) {
+	switch t := v.(type) {
+	case nil:
+		return def, nil
+	case float64:
+		return time.Duration(t * float64(time.Millisecond)), nil
+	case string:
+		if t == "" {
+			return def, nil
+		}
+		if ms, err := strconv.ParseFloat(t, 64); err == nil {
+			return time.Duration(ms * float64(time.Millisecond)), nil
+		}
+		return time.ParseDuration(t)
+	default:
+		return 0, fmt.Errorf("unsupported duration %v", v)
+	}
+}
+
+// formatDuration formats d in the protobuf json style, which istio requires
+func formatDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
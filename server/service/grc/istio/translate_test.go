@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/server/service/grc/istio"
+)
+
+func newPolicy(name string, spec map[string]interface{}) *gov.Policy {
+	return &gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{ID: "f2a2b4c1", Name: name},
+		Spec:             spec,
+	}
+}
+
+func group() *gov.Policy {
+	return newPolicy("scene-a1", map[string]interface{}{
+		"alias":    "a1",
+		"services": "provider.default.svc.cluster.local, consumer",
+		"matches": []interface{}{
+			map[string]interface{}{
+				"name":    "m1",
+				"apiPath": map[string]interface{}{"suffix": "/v1.0"},
+				"headers": map[string]interface{}{"X-User": map[string]interface{}{"exact": "jack"}},
+				"method":  []interface{}{"GET", "post"},
+			},
+		},
+	})
+}
+
+func spec(t *testing.T, obj *unstructured.Unstructured, fields ...string) interface{} {
+	v, ok, err := unstructured.NestedFieldNoCopy(obj.Object, append([]string{"spec"}, fields...)...)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	return v
+}
+
+func TestTranslate(t *testing.T) {
+	t.Run("match group only, should generate virtual services", func(t *testing.T) {
+		objects, err := istio.Translate("default", group(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(objects))
+		vs := objects[0]
+		assert.Equal(t, istio.KindVirtualService, vs.GetKind())
+		assert.Equal(t, "default", vs.GetNamespace())
+		assert.Equal(t, "f2a2b4c1-provider.default.svc.cluster.local", vs.GetName())
+		assert.Equal(t, []interface{}{"provider.default.svc.cluster.local"}, spec(t, vs, "hosts"))
+
+		http := spec(t, vs, "http").([]interface{})
+		assert.Equal(t, 2, len(http))
+		route := http[0].(map[string]interface{})
+		assert.Equal(t, "scene-a1", route["name"])
+		matches := route["match"].([]interface{})
+		assert.Equal(t, 2, len(matches))
+		assert.Equal(t, map[string]interface{}{
+			"name":    "m1",
+			"uri":     map[string]interface{}{"regex": `.*/v1\.0`},
+			"headers": map[string]interface{}{"X-User": map[string]interface{}{"exact": "jack"}},
+			"method":  map[string]interface{}{"exact": "POST"},
+		}, matches[1])
+		assert.Equal(t, "default", http[1].(map[string]interface{})["name"])
+		assert.Equal(t, "consumer", objects[1].GetName()[len("f2a2b4c1-"):])
+	})
+
+	t.Run("with policies, should generate all resources", func(t *testing.T) {
+		g := group()
+		g.Spec["services"] = "provider"
+		objects, err := istio.Translate("default", g, map[string]*gov.Policy{
+			"retry":          newPolicy("scene-a1", map[string]interface{}{"maxAttempts": 3}),
+			"faultInjection": newPolicy("scene-a1", map[string]interface{}{"type": "abort", "percentage": 50}),
+			"loadbalance":    newPolicy("scene-a1", map[string]interface{}{"rule": "Random"}),
+			"circuitBreaker": newPolicy("scene-a1", map[string]interface{}{"minimumNumberOfCalls": 5, "waitDurationInOpenState": "1500"}),
+			"rateLimiting":   newPolicy("scene-a1", map[string]interface{}{"rate": 10, "limitRefreshPeriod": "2s"}),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(objects))
+
+		route := spec(t, objects[0], "http").([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"attempts": int64(2), "retryOn": "connect-failure,refused-stream,502,503"}, route["retries"])
+		assert.Equal(t, map[string]interface{}{
+			"abort": map[string]interface{}{"percentage": map[string]interface{}{"value": float64(50)}, "httpStatus": int64(500)},
+		}, route["fault"])
+
+		dr := objects[1]
+		assert.Equal(t, istio.KindDestinationRule, dr.GetKind())
+		assert.Equal(t, "provider", spec(t, dr, "host"))
+		assert.Equal(t, "RANDOM", spec(t, dr, "trafficPolicy", "loadBalancer", "simple"))
+		assert.Equal(t, int64(5), spec(t, dr, "trafficPolicy", "outlierDetection", "consecutive5xxErrors"))
+		assert.Equal(t, "1.5s", spec(t, dr, "trafficPolicy", "outlierDetection", "baseEjectionTime"))
+
+		ef := objects[2]
+		assert.Equal(t, istio.KindEnvoyFilter, ef.GetKind())
+		assert.Equal(t, "provider", spec(t, ef, "workloadSelector", "labels", "app"))
+		patch := spec(t, ef, "configPatches").([]interface{})[0].(map[string]interface{})
+		bucket, _, _ := unstructured.NestedMap(patch, "patch", "value", "typed_config", "value", "token_bucket")
+		assert.Equal(t, map[string]interface{}{"max_tokens": int64(10), "tokens_per_fill": int64(10), "fill_interval": "2s"}, bucket)
+		assert.NotPanics(t, func() { ef.DeepCopy() })
+	})
+
+	t.Run("no services, should generate nothing", func(t *testing.T) {
+		g := group()
+		delete(g.Spec, "services")
+		objects, err := istio.Translate("default", g, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, objects)
+	})
+}
+
+func TestCheckSpec(t *testing.T) {
+	assert.NoError(t, istio.CheckSpec("match-group", group().Spec))
+	assert.NoError(t, istio.CheckSpec("bulkhead", map[string]interface{}{"maxConcurrentCalls": 1}))
+	assert.Error(t, istio.CheckSpec("match-group", map[string]interface{}{
+		"matches": []interface{}{map[string]interface{}{"name": "m", "apiPath": map[string]interface{}{"compare": ">1"}}},
+	}))
+	assert.Error(t, istio.CheckSpec("loadbalance", map[string]interface{}{"rule": "unknown"}))
+	assert.Error(t, istio.CheckSpec("rate-limiting", map[string]interface{}{"rate": 0}))
+	assert.Error(t, istio.CheckSpec("fault-injection", map[string]interface{}{"type": "delay", "delayTime": "x"}))
+	assert.Error(t, istio.CheckSpec("circuit-breaker", map[string]interface{}{}))
+}
@@ -21,9 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"strings"
-	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/go-chassis/foundation/httpclient"
@@ -53,7 +51,7 @@ func (d *Distributor) Create(ctx context.Context, kind, project string, p *gov.P
 		}
 	}
 	if kind == grcsvc.KindMatchGroup {
-		setAliasIfEmpty(p.Spec, p.Name)
+		grcsvc.SetAliasIfEmpty(p.Spec, p.Name)
 	}
 	yamlByte, err := yaml.Marshal(p.Spec)
 	if err != nil {
@@ -77,7 +75,7 @@ func (d *Distributor) Create(ctx context.Context, kind, project string, p *gov.P
 
 func (d *Distributor) Update(ctx context.Context, kind, id, project string, p *gov.Policy) error {
 	if kind == grcsvc.KindMatchGroup {
-		setAliasIfEmpty(p.Spec, p.Name)
+		grcsvc.SetAliasIfEmpty(p.Spec, p.Name)
 	}
 	yamlByte, err := yaml.Marshal(p.Spec)
 	if err != nil {
@@ -188,17 +186,6 @@ func (d *Distributor) Display(ctx context.Context, project, app, env string) ([]
 	return b, nil
 }
 
-func setAliasIfEmpty(spec map[string]interface{}, name string) {
-	if spec["alias"] == nil {
-		spec["alias"] = name
-		return
-	}
-	alias := spec["alias"].(string)
-	if alias == "" {
-		spec["alias"] = name
-	}
-}
-
 func (d *Distributor) List(ctx context.Context, kind, project, app, env string) ([]byte, error) {
 	list, _, err := d.listDataByKind(ctx, kind, project, app, env)
 	if err != nil {
@@ -299,7 +286,7 @@ func (d *Distributor) generateID(ctx context.Context, project string, p *gov.Pol
 	var id string
 	for {
 		var repeat bool
-		id = getID()
+		id = grcsvc.NewGroupName()
 		govKey := toGovKeyPrefix(kind) + id
 		for _, datum := range list.Data {
 			if govKey == datum.Key {
@@ -315,17 +302,6 @@ func (d *Distributor) generateID(ctx context.Context, project string, p *gov.Pol
 	return nil
 }
 
-func getID() string {
-	str := "0123456789abcdefghijklmnopqrstuvwxyz"
-	b := []byte(str)
-	var result []byte
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	for i := 0; i < 4; i++ {
-		result = append(result, b[r.Intn(len(b))])
-	}
-	return grcsvc.GroupNamePrefix + string(result)
-}
-
 func (d *Distributor) transform(kv *kie.KVDoc, kind string) (*gov.Policy, error) {
 	goc := &gov.Policy{
 		GovernancePolicy: &gov.GovernancePolicy{
@@ -0,0 +1,42 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grc
+
+import (
+	"math/rand"
+	"time"
+)
+
+// NewGroupName returns a random match group name, like scene-a1b2
+func NewGroupName() string {
+	str := "0123456789abcdefghijklmnopqrstuvwxyz"
+	b := []byte(str)
+	var result []byte
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := 0; i < 4; i++ {
+		result = append(result, b[r.Intn(len(b))])
+	}
+	return GroupNamePrefix + string(result)
+}
+
+// SetAliasIfEmpty uses the match group name as the alias if it is not set
+func SetAliasIfEmpty(spec map[string]interface{}, name string) {
+	if alias, _ := spec["alias"].(string); alias == "" {
+		spec["alias"] = name
+	}
+}
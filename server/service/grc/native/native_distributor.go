@@ -0,0 +1,347 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/go-chassis/foundation/gopool"
+
+	govds "github.com/apache/servicecomb-service-center/datasource/gov"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	grcsvc "github.com/apache/servicecomb-service-center/server/service/grc"
+)
+
+// PollInterval is the interval of checking the revision changed by other service center instances,
+// the revision of a domain project is checked by one poller for all its watchers
+var PollInterval = 2 * time.Second
+
+// Distributor persists policies in the datasource of service center,
+// so governance works without an external config server.
+type Distributor struct {
+	name string
+
+	mux     sync.Mutex
+	changed chan struct{}
+	pollers map[string]*poller
+}
+
+// poller checks the revision of a domain project for all the watchers of it,
+// it stops when there is no watcher
+type poller struct {
+	watchers int
+	changed  chan struct{}
+}
+
+func (d *Distributor) Create(ctx context.Context, kind, project string, p *gov.Policy) ([]byte, error) {
+	ctx = grcsvc.WithProject(ctx, project)
+	kind = util.ToSnake(kind)
+	if kind == util.ToSnake(grcsvc.KindMatchGroup) {
+		if err := d.generateName(ctx, p); err != nil {
+			return nil, err
+		}
+		grcsvc.SetAliasIfEmpty(p.Spec, p.Name)
+	}
+	now := time.Now().Unix()
+	p.ID = util.GenerateUUID()
+	p.Kind = kind
+	p.CreatTime = now
+	p.UpdateTime = now
+	if p.Status == "" {
+		p.Status = grcsvc.StatusEnabled
+	}
+	if err := govds.Instance().CreatePolicy(ctx, p); err != nil {
+		log.Error("create policy failed", err)
+		return nil, err
+	}
+	d.notify()
+	return []byte(p.ID), nil
+}
+
+func (d *Distributor) Update(ctx context.Context, kind, id, project string, p *gov.Policy) error {
+	ctx = grcsvc.WithProject(ctx, project)
+	old, err := govds.Instance().GetPolicy(ctx, id)
+	if err != nil {
+		return err
+	}
+	if util.ToSnake(kind) == util.ToSnake(grcsvc.KindMatchGroup) {
+		grcsvc.SetAliasIfEmpty(p.Spec, old.Name)
+	}
+	old.Spec = p.Spec
+	if p.Status != "" {
+		old.Status = p.Status
+	}
+	old.UpdateTime = time.Now().Unix()
+	if err = govds.Instance().UpdatePolicy(ctx, old); err != nil {
+		log.Error("update policy failed", err)
+		return err
+	}
+	d.notify()
+	return nil
+}
+
+func (d *Distributor) Delete(ctx context.Context, kind, id, project string) error {
+	ctx = grcsvc.WithProject(ctx, project)
+	ids := []string{id}
+	if util.ToSnake(kind) == util.ToSnake(grcsvc.KindMatchGroup) {
+		// should remove all policies of this group
+		group, err := govds.Instance().GetPolicy(ctx, id)
+		if err == govds.ErrPolicyNotExists {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		policies, err := d.list(ctx, "", group.Selector[grcsvc.KeyApp], group.Selector[grcsvc.KeyEnvironment])
+		if err != nil {
+			return err
+		}
+		for _, p := range policies {
+			if p.Name == group.Name && p.ID != id {
+				ids = append(ids, p.ID)
+			}
+		}
+	}
+	if err := govds.Instance().DeletePolicies(ctx, ids...); err != nil {
+		log.Error("delete policies failed", err)
+		return err
+	}
+	d.notify()
+	return nil
+}
+
+func (d *Distributor) Display(ctx context.Context, project, app, env string) ([]byte, error) {
+	list, err := d.list(grcsvc.WithProject(ctx, project), "", app, env)
+	if err != nil {
+		return nil, err
+	}
+	b, _ := json.MarshalIndent(display(list), "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) List(ctx context.Context, kind, project, app, env string) ([]byte, error) {
+	r, err := d.list(grcsvc.WithProject(ctx, project), util.ToSnake(kind), app, env)
+	if err != nil {
+		return nil, err
+	}
+	b, _ := json.MarshalIndent(r, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) Get(ctx context.Context, _, id, project string) ([]byte, error) {
+	p, err := govds.Instance().GetPolicy(grcsvc.WithProject(ctx, project), id)
+	if err != nil {
+		return nil, err
+	}
+	b, _ := json.MarshalIndent(p, "", "  ")
+	return b, nil
+}
+
+// Watch returns the policies of app and env once the project revision changed,
+// changes of other apps also wake up the watchers.
+func (d *Distributor) Watch(ctx context.Context, project, app, env string, revision int64) (*gov.WatchResponse, error) {
+	ctx = grcsvc.WithProject(ctx, project)
+	domainProject := util.ParseDomainProject(ctx)
+	d.watch(ctx, domainProject)
+	defer d.unwatch(domainProject)
+	for {
+		changed, polled := d.changes(domainProject)
+		current, err := govds.Instance().GetRevision(ctx)
+		if err != nil && ctx.Err() != nil {
+			return nil, nil
+		}
+		if err != nil {
+			log.Error("get policy revision failed", err)
+			return nil, err
+		}
+		if current != revision {
+			policies, err := d.list(ctx, "", app, env)
+			if err != nil {
+				return nil, err
+			}
+			return &gov.WatchResponse{Revision: current, Policies: policies}, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-changed:
+		case <-polled:
+		}
+	}
+}
+
+// watch starts the poller of the domain project for the first watcher
+func (d *Distributor) watch(ctx context.Context, domainProject string) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	p, ok := d.pollers[domainProject]
+	if ok {
+		p.watchers++
+		return
+	}
+	p = &poller{watchers: 1, changed: make(chan struct{})}
+	d.pollers[domainProject] = p
+	pctx := util.SetDomainProject(context.Background(), util.ParseDomain(ctx), util.ParseProject(ctx))
+	interval := PollInterval
+	gopool.Go(func(_ context.Context) {
+		d.poll(pctx, domainProject, p, interval)
+	})
+}
+
+func (d *Distributor) unwatch(domainProject string) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if p, ok := d.pollers[domainProject]; ok {
+		p.watchers--
+	}
+}
+
+// poll wakes up the watchers of the domain project when the revision is changed by others
+func (d *Distributor) poll(ctx context.Context, domainProject string, p *poller, interval time.Duration) {
+	last, err := govds.Instance().GetRevision(ctx)
+	if err != nil {
+		log.Error("get policy revision failed", err)
+	}
+	for {
+		time.Sleep(interval)
+		d.mux.Lock()
+		if p.watchers <= 0 {
+			delete(d.pollers, domainProject)
+			d.mux.Unlock()
+			return
+		}
+		d.mux.Unlock()
+
+		current, err := govds.Instance().GetRevision(ctx)
+		if err != nil {
+			log.Error("get policy revision failed", err)
+			continue
+		}
+		if current == last {
+			continue
+		}
+		last = current
+		d.mux.Lock()
+		close(p.changed)
+		p.changed = make(chan struct{})
+		d.mux.Unlock()
+	}
+}
+
+func (d *Distributor) Type() string {
+	return grcsvc.ConfigDistributorNative
+}
+func (d *Distributor) Name() string {
+	return d.name
+}
+
+func (d *Distributor) notify() {
+	d.mux.Lock()
+	close(d.changed)
+	d.changed = make(chan struct{})
+	d.mux.Unlock()
+}
+
+// changes returns the channels closed by the changes of this node and the poller of the domain project
+func (d *Distributor) changes(domainProject string) (<-chan struct{}, <-chan struct{}) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	var polled chan struct{}
+	if p, ok := d.pollers[domainProject]; ok {
+		polled = p.changed
+	}
+	return d.changed, polled
+}
+
+func (d *Distributor) list(ctx context.Context, kind, app, env string) ([]*gov.Policy, error) {
+	policies, err := govds.Instance().ListPolicies(ctx, kind)
+	if err != nil {
+		log.Error("list policies failed", err)
+		return nil, err
+	}
+	r := make([]*gov.Policy, 0, len(policies))
+	for _, p := range policies {
+		if app != "" && p.Selector[grcsvc.KeyApp] != app {
+			continue
+		}
+		if env != grcsvc.EnvAll && p.Selector[grcsvc.KeyEnvironment] != env {
+			continue
+		}
+		r = append(r, p)
+	}
+	return r, nil
+}
+
+func (d *Distributor) generateName(ctx context.Context, p *gov.Policy) error {
+	if p.Name != "" {
+		return nil
+	}
+	list, err := d.list(ctx, util.ToSnake(grcsvc.KindMatchGroup), p.Selector[grcsvc.KeyApp], p.Selector[grcsvc.KeyEnvironment])
+	if err != nil {
+		return err
+	}
+	names := make(map[string]bool, len(list))
+	for _, item := range list {
+		names[item.Name] = true
+	}
+	for {
+		name := grcsvc.NewGroupName()
+		if !names[name] {
+			p.Name = name
+			return nil
+		}
+	}
+}
+
+func display(list []*gov.Policy) []*gov.DisplayData {
+	policyMap := make(map[string]*gov.Policy)
+	for _, p := range list {
+		policyMap[p.Name+p.Kind] = p
+	}
+	r := make([]*gov.DisplayData, 0)
+	for _, match := range list {
+		if match.Kind != util.ToSnake(grcsvc.KindMatchGroup) {
+			continue
+		}
+		var policies []*gov.Policy
+		for _, kind := range grcsvc.PolicyNames {
+			if policyMap[match.Name+kind] != nil {
+				policies = append(policies, policyMap[match.Name+kind])
+			}
+		}
+		r = append(r, &gov.DisplayData{
+			Policies:   policies,
+			MatchGroup: match,
+		})
+	}
+	return r
+}
+
+func newDistributor(opts config.DistributorOptions) (grcsvc.ConfigDistributor, error) {
+	return &Distributor{name: opts.Name, changed: make(chan struct{}), pollers: make(map[string]*poller)}, nil
+}
+
+func init() {
+	grcsvc.InstallDistributor(grcsvc.ConfigDistributorNative, newDistributor)
+}
@@ -0,0 +1,369 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	govds "github.com/apache/servicecomb-service-center/datasource/gov"
+	govdsmock "github.com/apache/servicecomb-service-center/datasource/gov/mock"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/server/config"
+	grcsvc "github.com/apache/servicecomb-service-center/server/service/grc"
+	"github.com/apache/servicecomb-service-center/server/service/grc/native"
+)
+
+func init() {
+	if err := govds.Init(govds.Options{Kind: govdsmock.Kind}); err != nil {
+		panic(err)
+	}
+	config.App.Gov = &config.Gov{DistMap: map[string]config.DistributorOptions{
+		grcsvc.ConfigDistributorNative: {Type: grcsvc.ConfigDistributorNative},
+	}}
+	if err := grcsvc.Init(); err != nil {
+		panic(err)
+	}
+	grcsvc.PolicyNames = []string{"retry", "bulkhead"}
+	grcsvc.RegisterPolicySchema("retry", &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"object"}}})
+	grcsvc.RegisterPolicySchema("bulkhead", &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"object"}}})
+	grcsvc.RegisterPolicySchema(grcsvc.KindMatchGroup, &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"object"}}})
+}
+
+func newPolicy(name string, spec map[string]interface{}) *gov.Policy {
+	return &gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{
+			Name:     name,
+			Selector: gov.Selector{grcsvc.KeyApp: "app", grcsvc.KeyEnvironment: "prod"},
+		},
+		Spec: spec,
+	}
+}
+
+func TestDistributor(t *testing.T) {
+	ctx := context.Background()
+	defer func(interval time.Duration) { native.PollInterval = interval }(native.PollInterval)
+	native.PollInterval = 50 * time.Millisecond
+
+	id, err := grcsvc.Create(ctx, grcsvc.KindMatchGroup, "default", newPolicy("", map[string]interface{}{}))
+	assert.NoError(t, err)
+	b, err := grcsvc.Get(ctx, grcsvc.KindMatchGroup, string(id), "default")
+	assert.NoError(t, err)
+	group := &gov.Policy{}
+	assert.NoError(t, json.Unmarshal(b, group))
+	assert.Contains(t, group.Name, grcsvc.GroupNamePrefix)
+	assert.Equal(t, group.Name, group.Spec["alias"])
+	assert.Equal(t, grcsvc.StatusEnabled, group.Status)
+
+	retryID, err := grcsvc.Create(ctx, "retry", "default", newPolicy(group.Name, map[string]interface{}{"maxAttempts": 3}))
+	assert.NoError(t, err)
+
+	t.Run("display, should group the policies", func(t *testing.T) {
+		b, err := grcsvc.Display(ctx, "default", "app", grcsvc.EnvAll)
+		assert.NoError(t, err)
+		var display []*gov.DisplayData
+		assert.NoError(t, json.Unmarshal(b, &display))
+		assert.Equal(t, 1, len(display))
+		assert.Equal(t, 1, len(display[0].Policies))
+
+		b, err = grcsvc.List(ctx, "retry", "default", "app", "test")
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", string(b))
+	})
+
+	t.Run("watch the latest revision, should wait for the change", func(t *testing.T) {
+		resp, err := grcsvc.Watch(ctx, "default", "app", "prod", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(resp.Policies))
+
+		timeout, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		none, err := grcsvc.Watch(timeout, "default", "app", "prod", resp.Revision)
+		assert.NoError(t, err)
+		assert.Nil(t, none)
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_ = grcsvc.Update(ctx, "retry", string(retryID), "default", newPolicy("", map[string]interface{}{"maxAttempts": 5}))
+		}()
+		wait, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		changed, err := grcsvc.Watch(wait, "default", "app", "prod", resp.Revision)
+		assert.NoError(t, err)
+		assert.NotNil(t, changed)
+		assert.True(t, changed.Revision > resp.Revision)
+	})
+
+	t.Run("watch the change of others, should be woken up by the poller", func(t *testing.T) {
+		resp, err := grcsvc.Watch(ctx, "default", "app", "prod", 0)
+		assert.NoError(t, err)
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			// changed by another service center, this node is not notified
+			p, _ := govds.Instance().GetPolicy(grcsvc.WithProject(ctx, "default"), string(retryID))
+			_ = govds.Instance().UpdatePolicy(grcsvc.WithProject(ctx, "default"), p)
+		}()
+		wait, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		changed, err := grcsvc.Watch(wait, "default", "app", "prod", resp.Revision)
+		assert.NoError(t, err)
+		assert.NotNil(t, changed)
+	})
+
+	t.Run("delete the group, should remove its policies", func(t *testing.T) {
+		err := grcsvc.Delete(ctx, grcsvc.KindMatchGroup, string(id), "default")
+		assert.NoError(t, err)
+		_, err = grcsvc.Get(ctx, "retry", string(retryID), "default")
+		assert.Equal(t, govds.ErrPolicyNotExists, err)
+
+		list, err := grcsvc.ListHistory(ctx, "default", string(retryID))
+		assert.NoError(t, err)
+		last := list[len(list)-1]
+		assert.Equal(t, gov.ActionDelete, last.Action)
+		assert.Equal(t, float64(5), last.Policy.Spec["maxAttempts"])
+	})
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+
+	id, err := grcsvc.Create(ctx, "retry", "default", newPolicy("history", map[string]interface{}{"maxAttempts": 3}))
+	assert.NoError(t, err)
+	err = grcsvc.Update(ctx, "retry", string(id), "default", newPolicy("history", map[string]interface{}{"maxAttempts": 5}))
+	assert.NoError(t, err)
+
+	t.Run("list history, should return every version", func(t *testing.T) {
+		list, err := grcsvc.ListHistory(ctx, "default", string(id))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(list))
+		assert.Equal(t, gov.ActionCreate, list[0].Action)
+		assert.Equal(t, gov.ActionUpdate, list[1].Action)
+		assert.Equal(t, int64(2), list[1].Version)
+	})
+
+	t.Run("compare two versions, should return the changed spec", func(t *testing.T) {
+		changes, err := grcsvc.CompareHistory(ctx, "default", string(id), 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(changes))
+		assert.Equal(t, "spec.maxAttempts", changes[0].Path)
+	})
+
+	t.Run("rollback to the first version, should restore the spec", func(t *testing.T) {
+		err := grcsvc.Rollback(ctx, "retry", string(id), "default", 1)
+		assert.NoError(t, err)
+		b, err := grcsvc.Get(ctx, "retry", string(id), "default")
+		assert.NoError(t, err)
+		p := &gov.Policy{}
+		assert.NoError(t, json.Unmarshal(b, p))
+		assert.Equal(t, float64(3), p.Spec["maxAttempts"])
+
+		h, err := grcsvc.GetHistory(ctx, "default", string(id), 3)
+		assert.NoError(t, err)
+		assert.Equal(t, gov.ActionRollback, h.Action)
+		assert.Equal(t, int64(1), h.RollbackTo)
+	})
+
+	t.Run("rollback to the deletion, should failed", func(t *testing.T) {
+		err := grcsvc.Delete(ctx, "retry", string(id), "default")
+		assert.NoError(t, err)
+		err = grcsvc.Rollback(ctx, "retry", string(id), "default", 4)
+		assert.Error(t, err)
+		_, err = grcsvc.GetHistory(ctx, "default", string(id), 5)
+		assert.Equal(t, govds.ErrHistoryNotExists, err)
+	})
+}
+
+func TestSimulate(t *testing.T) {
+	ctx := context.Background()
+
+	newGroup := func(name string, matches ...map[string]interface{}) *gov.Policy {
+		items := make([]interface{}, 0, len(matches))
+		for _, m := range matches {
+			items = append(items, m)
+		}
+		return newPolicy(name, map[string]interface{}{"alias": name, "matches": items})
+	}
+	var ids [][]byte
+	for _, p := range []struct {
+		kind   string
+		policy *gov.Policy
+	}{
+		{grcsvc.KindMatchGroup, newGroup("simulate-prefix", map[string]interface{}{
+			"name": "orders", "apiPath": map[string]interface{}{"prefix": "/orders"}})},
+		{grcsvc.KindMatchGroup, newGroup("simulate-exact", map[string]interface{}{
+			"name": "create", "apiPath": map[string]interface{}{"exact": "/orders/create"}, "method": []interface{}{"POST"}})},
+		{"retry", newPolicy("simulate-prefix", map[string]interface{}{"maxAttempts": 3})},
+		{"retry", newPolicy("simulate-exact", map[string]interface{}{"maxAttempts": 1})},
+	} {
+		id, err := grcsvc.Create(ctx, p.kind, "default", p.policy)
+		assert.NoError(t, err)
+		ids = append(ids, id)
+	}
+	defer func() {
+		for i := range ids[:2] {
+			_ = grcsvc.Delete(ctx, grcsvc.KindMatchGroup, string(ids[i]), "default")
+		}
+	}()
+
+	t.Run("simulate a request matched by two groups, should prefer the exact path", func(t *testing.T) {
+		resp, err := grcsvc.Simulate(ctx, "default", "app", "prod", &gov.SimulateRequest{
+			Path: "/orders/create", Method: "post",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(resp.MatchGroups))
+		assert.Equal(t, "simulate-exact", resp.MatchGroups[0].MatchGroup.Name)
+		assert.Equal(t, "create", resp.MatchGroups[0].Match)
+		assert.Equal(t, "simulate-prefix", resp.MatchGroups[1].MatchGroup.Name)
+		assert.Equal(t, 1, len(resp.Effective))
+		assert.Equal(t, "simulate-exact", resp.Effective[0].Name)
+	})
+
+	t.Run("simulate a request not matched, should return nothing", func(t *testing.T) {
+		resp, err := grcsvc.Simulate(ctx, "default", "app", "prod", &gov.SimulateRequest{Path: "/users"})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(resp.MatchGroups))
+		assert.Equal(t, 0, len(resp.Effective))
+	})
+
+	t.Run("simulate with a draft group, should use the draft", func(t *testing.T) {
+		draft := newGroup("simulate-exact", map[string]interface{}{
+			"name": "header", "headers": map[string]interface{}{"X-User": map[string]interface{}{"exact": "jack"}}})
+		draft.Kind = grcsvc.KindMatchGroup
+		resp, err := grcsvc.Simulate(ctx, "default", "app", "prod", &gov.SimulateRequest{
+			Path: "/orders/create", Method: "POST", Headers: map[string]string{"x-user": "jack"},
+			Drafts: []*gov.Policy{draft},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(resp.MatchGroups))
+		assert.Equal(t, "simulate-prefix", resp.MatchGroups[0].MatchGroup.Name)
+		assert.Equal(t, "header", resp.MatchGroups[1].Match)
+
+		draft.Spec["matches"] = []interface{}{map[string]interface{}{
+			"name": "invalid", "apiPath": map[string]interface{}{"regex": "("}}}
+		_, err = grcsvc.Simulate(ctx, "default", "app", "prod", &gov.SimulateRequest{
+			Path: "/orders", Drafts: []*gov.Policy{draft},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestTemplate(t *testing.T) {
+	ctx := context.Background()
+
+	tpl, err := grcsvc.CreateTemplate(ctx, "default", &gov.Template{
+		Name: "standard",
+		MatchGroup: map[string]interface{}{"matches": []interface{}{
+			map[string]interface{}{"name": "all", "apiPath": map[string]interface{}{"prefix": "/"}}}},
+		Policies: map[string]map[string]interface{}{
+			"retry":    {"maxAttempts": 3},
+			"bulkhead": {"maxConcurrentCalls": 10},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), tpl.Revision)
+
+	var instances []*gov.TemplateInstance
+	defer func() {
+		for _, instance := range instances {
+			_ = grcsvc.Delete(ctx, grcsvc.KindMatchGroup, instance.Policies[grcsvc.KindMatchGroup], "default")
+		}
+	}()
+	t.Run("apply to two apps, should create the policies of each app", func(t *testing.T) {
+		result, err := grcsvc.ApplyTemplate(ctx, "default", tpl.ID, []*gov.TemplateTarget{
+			{App: "tpl-a", Environment: "prod", Services: "orders"},
+			{App: "tpl-b", Environment: "prod"},
+			{Environment: "prod"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(result.Instances))
+		assert.Equal(t, 1, len(result.Failures))
+		instances = result.Instances
+
+		b, err := grcsvc.Display(ctx, "default", "tpl-a", "prod")
+		assert.NoError(t, err)
+		var display []*gov.DisplayData
+		assert.NoError(t, json.Unmarshal(b, &display))
+		assert.Equal(t, 1, len(display))
+		assert.Equal(t, instances[0].GroupName, display[0].MatchGroup.Name)
+		assert.Equal(t, "orders", display[0].MatchGroup.Spec["services"])
+		assert.Equal(t, 2, len(display[0].Policies))
+	})
+
+	t.Run("update template, should diff the instances without changing them", func(t *testing.T) {
+		tpl.Policies = map[string]map[string]interface{}{"retry": {"maxAttempts": 5}}
+		updated, err := grcsvc.UpdateTemplate(ctx, "default", tpl.ID, tpl)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Revision)
+		// the template read before the update is stale
+		stale := *updated
+		stale.Revision = 3
+		err = govds.Instance().UpdateTemplate(grcsvc.WithProject(ctx, "default"), &stale, 1)
+		assert.Equal(t, govds.ErrTemplateConflict, err)
+
+		diffs, err := grcsvc.DiffTemplate(ctx, "default", tpl.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(diffs))
+		var paths []string
+		for _, c := range diffs[0].Changes {
+			paths = append(paths, c.Path)
+		}
+		assert.Contains(t, paths, "bulkhead.spec.maxConcurrentCalls")
+		assert.Contains(t, paths, "retry.spec.maxAttempts")
+
+		b, err := grcsvc.Get(ctx, "retry", instances[0].Policies["retry"], "default")
+		assert.NoError(t, err)
+		p := &gov.Policy{}
+		assert.NoError(t, json.Unmarshal(b, p))
+		assert.Equal(t, float64(3), p.Spec["maxAttempts"])
+	})
+
+	t.Run("propagate one instance, should update only it", func(t *testing.T) {
+		result, err := grcsvc.PropagateTemplate(ctx, "default", tpl.ID, []string{instances[0].ID, "not-exist"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(result.Instances))
+		assert.Equal(t, 1, len(result.Failures))
+		assert.Equal(t, int64(2), result.Instances[0].Revision)
+
+		b, err := grcsvc.Get(ctx, "retry", instances[0].Policies["retry"], "default")
+		assert.NoError(t, err)
+		p := &gov.Policy{}
+		assert.NoError(t, json.Unmarshal(b, p))
+		assert.Equal(t, float64(5), p.Spec["maxAttempts"])
+		_, ok := result.Instances[0].Policies["bulkhead"]
+		assert.False(t, ok)
+
+		diffs, err := grcsvc.DiffTemplate(ctx, "default", tpl.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(diffs))
+		assert.Equal(t, instances[1].ID, diffs[0].Instance.ID)
+	})
+
+	t.Run("delete template, should keep the policies", func(t *testing.T) {
+		err := grcsvc.DeleteTemplate(ctx, "default", tpl.ID)
+		assert.NoError(t, err)
+		_, err = grcsvc.GetTemplate(ctx, "default", tpl.ID)
+		assert.Equal(t, govds.ErrTemplateNotExists, err)
+		_, err = grcsvc.Get(ctx, "retry", instances[0].Policies["retry"], "default")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,329 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-chassis/cari/discovery"
+
+	model "github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+// operator rank of apiPath, the more exact operator takes precedence
+var operatorRanks = map[string]int{
+	"exact":    4,
+	"prefix":   3,
+	"suffix":   2,
+	"contains": 2,
+	"regex":    1,
+}
+
+type matchGroupSpec struct {
+	Services string       `json:"services,omitempty"`
+	Matches  []*matchItem `json:"matches,omitempty"`
+}
+
+type matchItem struct {
+	Name        string                       `json:"name,omitempty"`
+	ServiceName string                       `json:"serviceName,omitempty"`
+	APIPath     map[string]string            `json:"apiPath,omitempty"`
+	Headers     map[string]map[string]string `json:"headers,omitempty"`
+	Method      []string                     `json:"method,omitempty"`
+}
+
+// specificity is compared field by field, the greater one takes precedence
+type specificity struct {
+	pathRank int
+	pathLen  int
+	headers  int
+	method   int
+}
+
+func (s specificity) greater(o specificity) bool {
+	if s.pathRank != o.pathRank {
+		return s.pathRank > o.pathRank
+	}
+	if s.pathLen != o.pathLen {
+		return s.pathLen > o.pathLen
+	}
+	if s.headers != o.headers {
+		return s.headers > o.headers
+	}
+	return s.method > o.method
+}
+
+type candidate struct {
+	group    *model.Policy
+	policies map[string]*model.Policy
+	match    string
+	spec     specificity
+}
+
+// Simulate returns the match groups of app and env selecting the sample request and
+// the effective policies, nothing is changed in distributors.
+// The matched groups are ordered by the specificity of their matched items:
+// exact apiPath first, then prefix (the longer first), suffix or contains, regex and no apiPath,
+// then the more headers, then the item with method. Groups with the same specificity are ordered by name.
+func Simulate(ctx context.Context, project, app, env string, req *model.SimulateRequest) (*model.SimulateResponse, error) {
+	b, err := Display(ctx, project, app, env)
+	if err != nil {
+		return nil, err
+	}
+	var published []*model.DisplayData
+	if len(b) > 0 {
+		if err = json.Unmarshal(b, &published); err != nil {
+			return nil, err
+		}
+	}
+	candidates, err := mergeDrafts(published, req.Drafts)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*candidate
+	for _, c := range candidates {
+		if c.group.Status != "" && c.group.Status != StatusEnabled {
+			continue
+		}
+		ok, err := c.matches(req)
+		if err != nil {
+			return nil, discovery.NewError(discovery.ErrInvalidParams, fmt.Sprintf("match group [%s]: %s", c.group.Name, err))
+		}
+		if ok {
+			matched = append(matched, c)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].spec != matched[j].spec {
+			return matched[i].spec.greater(matched[j].spec)
+		}
+		return matched[i].group.Name < matched[j].group.Name
+	})
+
+	resp := &model.SimulateResponse{MatchGroups: []*model.MatchedGroup{}, Effective: []*model.Policy{}}
+	effective := make(map[string]bool)
+	for i, c := range matched {
+		group := &model.MatchedGroup{Order: i + 1, Match: c.match, MatchGroup: c.group}
+		for _, kind := range PolicyNames {
+			p, ok := c.policies[kind]
+			if !ok || (p.Status != "" && p.Status != StatusEnabled) {
+				continue
+			}
+			group.Policies = append(group.Policies, p)
+			if !effective[kind] {
+				effective[kind] = true
+				resp.Effective = append(resp.Effective, p)
+			}
+		}
+		resp.MatchGroups = append(resp.MatchGroups, group)
+	}
+	return resp, nil
+}
+
+// mergeDrafts replaces the published groups and policies by the drafts with the same kind and name
+func mergeDrafts(published []*model.DisplayData, drafts []*model.Policy) ([]*candidate, error) {
+	groups := make(map[string]*candidate, len(published))
+	var names []string
+	add := func(group *model.Policy) *candidate {
+		c, ok := groups[group.Name]
+		if !ok {
+			c = &candidate{policies: make(map[string]*model.Policy)}
+			groups[group.Name] = c
+			names = append(names, group.Name)
+		}
+		c.group = group
+		return c
+	}
+	for _, data := range published {
+		if data.MatchGroup == nil || data.MatchGroup.GovernancePolicy == nil {
+			continue
+		}
+		c := add(data.MatchGroup)
+		for _, p := range data.Policies {
+			if p != nil {
+				c.policies[p.Kind] = p
+			}
+		}
+	}
+
+	var policies []*model.Policy
+	for _, draft := range drafts {
+		if draft == nil || draft.GovernancePolicy == nil || draft.Name == "" {
+			return nil, discovery.NewError(discovery.ErrInvalidParams, "draft name is required")
+		}
+		if err := ValidatePolicySpec(draft.Kind, draft.Spec); err != nil {
+			return nil, discovery.NewError(discovery.ErrInvalidParams, err.Error())
+		}
+		d := *draft
+		d.Kind = util.ToSnake(draft.Kind)
+		if d.Kind == util.ToSnake(KindMatchGroup) {
+			add(&d)
+			continue
+		}
+		policies = append(policies, &d)
+	}
+	for _, p := range policies {
+		if c, ok := groups[p.Name]; ok {
+			c.policies[p.Kind] = p
+		}
+	}
+
+	candidates := make([]*candidate, 0, len(names))
+	for _, name := range names {
+		candidates = append(candidates, groups[name])
+	}
+	return candidates, nil
+}
+
+// matches checks whether any item of the group selects the request,
+// and keeps the most specific matched item
+func (c *candidate) matches(req *model.SimulateRequest) (bool, error) {
+	spec := &matchGroupSpec{}
+	if err := decode(c.group.Spec, spec); err != nil {
+		return false, err
+	}
+	if !containsService(spec.Services, req.Service) {
+		return false, nil
+	}
+	var found bool
+	for _, item := range spec.Matches {
+		s, ok, err := item.matches(req)
+		if err != nil {
+			return false, fmt.Errorf("match [%s]: %w", item.Name, err)
+		}
+		if ok && (!found || s.greater(c.spec)) {
+			found = true
+			c.match = item.Name
+			c.spec = s
+		}
+	}
+	return found, nil
+}
+
+func (item *matchItem) matches(req *model.SimulateRequest) (specificity, bool, error) {
+	var s specificity
+	if item.ServiceName != "" && !containsService(item.ServiceName, req.Service) {
+		return s, false, nil
+	}
+	if len(item.APIPath) > 0 {
+		ok, err := matchString(item.APIPath, req.Path)
+		if err != nil || !ok {
+			return s, false, err
+		}
+		for op, v := range item.APIPath {
+			s.pathRank = operatorRanks[op]
+			s.pathLen = len(v)
+		}
+	}
+	for name, m := range item.Headers {
+		value, ok := header(req.Headers, name)
+		if !ok {
+			return s, false, nil
+		}
+		matched, err := matchString(m, value)
+		if err != nil || !matched {
+			return s, false, err
+		}
+		s.headers++
+	}
+	if len(item.Method) > 0 {
+		method := req.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		var ok bool
+		for _, m := range item.Method {
+			ok = ok || strings.EqualFold(m, method)
+		}
+		if !ok {
+			return s, false, nil
+		}
+		s.method = 1
+	}
+	return s, true, nil
+}
+
+// matchString checks value by the only operator in m
+func matchString(m map[string]string, value string) (bool, error) {
+	if len(m) != 1 {
+		return false, fmt.Errorf("exactly one operator is required, got %d", len(m))
+	}
+	for op, v := range m {
+		switch op {
+		case "exact":
+			return value == v, nil
+		case "prefix":
+			return strings.HasPrefix(value, v), nil
+		case "suffix":
+			return strings.HasSuffix(value, v), nil
+		case "contains":
+			return strings.Contains(value, v), nil
+		case "regex":
+			r, err := regexp.Compile(v)
+			if err != nil {
+				return false, err
+			}
+			return r.MatchString(value), nil
+		default:
+			return false, fmt.Errorf("unknown operator [%s]", op)
+		}
+	}
+	return false, nil
+}
+
+// header gets the header value, the name is case-insensitive
+func header(headers map[string]string, name string) (string, bool) {
+	if v, ok := headers[name]; ok {
+		return v, true
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// containsService checks whether service is in the comma separated services, empty services contains any service
+func containsService(services, service string) bool {
+	if strings.TrimSpace(services) == "" {
+		return true
+	}
+	for _, s := range strings.Split(services, ",") {
+		if strings.TrimSpace(s) == service {
+			return true
+		}
+	}
+	return false
+}
+
+func decode(spec map[string]interface{}, v interface{}) error {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
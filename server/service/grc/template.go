@@ -0,0 +1,412 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-chassis/cari/discovery"
+
+	govds "github.com/apache/servicecomb-service-center/datasource/gov"
+	model "github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+var ErrTemplateNotSupported = discovery.NewError(discovery.ErrInvalidParams, "policy template is not supported")
+
+// CreateTemplate saves the template as revision 1
+func CreateTemplate(ctx context.Context, project string, t *model.Template) (*model.Template, error) {
+	if govds.Instance() == nil {
+		return nil, ErrTemplateNotSupported
+	}
+	if err := validateTemplate(t); err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	t.ID = util.GenerateUUID()
+	t.Revision = 1
+	t.CreateTime = now
+	t.UpdateTime = now
+	if err := govds.Instance().PutTemplate(WithProject(ctx, project), t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// UpdateTemplate saves the template as the next revision,
+// the instances are not changed until they are propagated, see PropagateTemplate.
+// It fails if the template is updated by others after it is read.
+func UpdateTemplate(ctx context.Context, project, id string, t *model.Template) (*model.Template, error) {
+	old, err := GetTemplate(ctx, project, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateTemplate(t); err != nil {
+		return nil, err
+	}
+	t.ID = id
+	t.Revision = old.Revision
+	if len(model.Diff(templatePolicy(old.MatchGroup), templatePolicy(t.MatchGroup))) > 0 ||
+		len(diffPolicies(old.Policies, t.Policies)) > 0 {
+		t.Revision++
+	}
+	t.CreateTime = old.CreateTime
+	t.UpdateTime = time.Now().Unix()
+	if err = govds.Instance().UpdateTemplate(WithProject(ctx, project), t, old.Revision); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func GetTemplate(ctx context.Context, project, id string) (*model.Template, error) {
+	if govds.Instance() == nil {
+		return nil, ErrTemplateNotSupported
+	}
+	return govds.Instance().GetTemplate(WithProject(ctx, project), id)
+}
+
+func ListTemplates(ctx context.Context, project string) ([]*model.Template, error) {
+	if govds.Instance() == nil {
+		return nil, ErrTemplateNotSupported
+	}
+	return govds.Instance().ListTemplates(WithProject(ctx, project))
+}
+
+// DeleteTemplate deletes the template, the policies created from it are kept
+func DeleteTemplate(ctx context.Context, project, id string) error {
+	if govds.Instance() == nil {
+		return ErrTemplateNotSupported
+	}
+	return govds.Instance().DeleteTemplate(WithProject(ctx, project), id)
+}
+
+func ListTemplateInstances(ctx context.Context, project, id string) ([]*model.TemplateInstance, error) {
+	if _, err := GetTemplate(ctx, project, id); err != nil {
+		return nil, err
+	}
+	return govds.Instance().ListTemplateInstances(WithProject(ctx, project), id)
+}
+
+// ApplyTemplate creates a match group and its policies from the template for each target by
+// the config distributor. A target failed does not stop the others, and its created policies are removed.
+func ApplyTemplate(ctx context.Context, project, id string, targets []*model.TemplateTarget) (*model.TemplateResult, error) {
+	t, err := GetTemplate(ctx, project, id)
+	if err != nil {
+		return nil, err
+	}
+	result := &model.TemplateResult{Instances: []*model.TemplateInstance{}}
+	for _, target := range targets {
+		instance, err := instantiate(ctx, project, t, target)
+		if err != nil {
+			log.Error(fmt.Sprintf("apply template [%s] to app [%s] failed", t.Name, target.App), err)
+			result.Failures = append(result.Failures, &model.TemplateFailure{Target: target, Message: err.Error()})
+			continue
+		}
+		result.Instances = append(result.Instances, instance)
+	}
+	return result, nil
+}
+
+// DiffTemplate returns the changes to the instances not in the latest template revision,
+// the changes are between the policies in distributor and the template
+func DiffTemplate(ctx context.Context, project, id string) ([]*model.TemplateDiff, error) {
+	t, err := GetTemplate(ctx, project, id)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := govds.Instance().ListTemplateInstances(WithProject(ctx, project), id)
+	if err != nil {
+		return nil, err
+	}
+	diffs := make([]*model.TemplateDiff, 0)
+	for _, instance := range instances {
+		if instance.Revision >= t.Revision {
+			continue
+		}
+		current := make(map[string]map[string]interface{}, len(instance.Policies))
+		for kind, policyID := range instance.Policies {
+			p, err := getPolicy(ctx, kind, policyID, project)
+			if err != nil {
+				log.Error(fmt.Sprintf("get policy [%s] of template instance [%s] failed", policyID, instance.ID), err)
+				continue
+			}
+			current[kind] = p.Spec
+		}
+		diffs = append(diffs, &model.TemplateDiff{
+			Instance: instance,
+			Changes:  diffPolicies(current, render(t, instance.Target, instance.GroupName)),
+		})
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Instance.ID < diffs[j].Instance.ID
+	})
+	return diffs, nil
+}
+
+// PropagateTemplate updates the instances to the latest template revision by the config distributor,
+// the policies added to the template are created and the policies removed from the template are deleted
+func PropagateTemplate(ctx context.Context, project, id string, instanceIDs []string) (*model.TemplateResult, error) {
+	t, err := GetTemplate(ctx, project, id)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := govds.Instance().ListTemplateInstances(WithProject(ctx, project), id)
+	if err != nil {
+		return nil, err
+	}
+	instanceMap := make(map[string]*model.TemplateInstance, len(instances))
+	for _, instance := range instances {
+		instanceMap[instance.ID] = instance
+	}
+	result := &model.TemplateResult{Instances: []*model.TemplateInstance{}}
+	for _, instanceID := range instanceIDs {
+		instance, ok := instanceMap[instanceID]
+		if !ok {
+			result.Failures = append(result.Failures, &model.TemplateFailure{Instance: instanceID,
+				Message: "template instance not exist"})
+			continue
+		}
+		if err := propagate(ctx, project, t, instance); err != nil {
+			log.Error(fmt.Sprintf("propagate template [%s] to instance [%s] failed", t.Name, instanceID), err)
+			result.Failures = append(result.Failures, &model.TemplateFailure{Instance: instanceID, Message: err.Error()})
+			continue
+		}
+		result.Instances = append(result.Instances, instance)
+	}
+	return result, nil
+}
+
+func instantiate(ctx context.Context, project string, t *model.Template, target *model.TemplateTarget) (*model.TemplateInstance, error) {
+	if target == nil || target.App == "" {
+		return nil, fmt.Errorf("app of target is required")
+	}
+	specs := render(t, target, "")
+	instance := &model.TemplateInstance{
+		ID:         util.GenerateUUID(),
+		TemplateID: t.ID,
+		Revision:   t.Revision,
+		Target:     target,
+		Policies:   make(map[string]string, len(specs)),
+	}
+	groupID, err := Create(ctx, KindMatchGroup, project, newTemplatePolicy("", target, specs[KindMatchGroup]))
+	if err != nil {
+		return nil, err
+	}
+	instance.Policies[KindMatchGroup] = string(groupID)
+	group, err := getPolicy(ctx, KindMatchGroup, string(groupID), project)
+	if err == nil {
+		instance.GroupName = group.Name
+		for _, kind := range sortedKinds(specs) {
+			if kind == KindMatchGroup {
+				continue
+			}
+			var policyID []byte
+			policyID, err = Create(ctx, kind, project, newTemplatePolicy(group.Name, target, specs[kind]))
+			if err != nil {
+				break
+			}
+			instance.Policies[kind] = string(policyID)
+		}
+	}
+	if err == nil {
+		err = govds.Instance().PutTemplateInstance(WithProject(ctx, project), instance)
+	}
+	if err != nil {
+		removeInstance(ctx, project, instance)
+		return nil, err
+	}
+	return instance, nil
+}
+
+func propagate(ctx context.Context, project string, t *model.Template, instance *model.TemplateInstance) error {
+	specs := render(t, instance.Target, instance.GroupName)
+	for _, kind := range sortedKinds(specs) {
+		p := newTemplatePolicy(instance.GroupName, instance.Target, specs[kind])
+		if policyID, ok := instance.Policies[kind]; ok {
+			if err := Update(ctx, kind, policyID, project, p); err != nil {
+				return err
+			}
+			continue
+		}
+		policyID, err := Create(ctx, kind, project, p)
+		if err != nil {
+			return err
+		}
+		// save the created policy at once, so it is still managed by the instance if the next fails
+		instance.Policies[kind] = string(policyID)
+		if err = govds.Instance().PutTemplateInstance(WithProject(ctx, project), instance); err != nil {
+			return err
+		}
+	}
+	for kind, policyID := range instance.Policies {
+		if _, ok := specs[kind]; ok {
+			continue
+		}
+		if err := Delete(ctx, kind, policyID, project); err != nil {
+			return err
+		}
+		delete(instance.Policies, kind)
+		if err := govds.Instance().PutTemplateInstance(WithProject(ctx, project), instance); err != nil {
+			return err
+		}
+	}
+	instance.Revision = t.Revision
+	return govds.Instance().PutTemplateInstance(WithProject(ctx, project), instance)
+}
+
+// removeInstance deletes the created policies of the instance, the match group is the last
+func removeInstance(ctx context.Context, project string, instance *model.TemplateInstance) {
+	for kind, policyID := range instance.Policies {
+		if kind == KindMatchGroup {
+			continue
+		}
+		if err := Delete(ctx, kind, policyID, project); err != nil {
+			log.Error(fmt.Sprintf("remove policy [%s] of template instance failed", policyID), err)
+		}
+	}
+	if groupID, ok := instance.Policies[KindMatchGroup]; ok {
+		if err := Delete(ctx, KindMatchGroup, groupID, project); err != nil {
+			log.Error(fmt.Sprintf("remove match group [%s] of template instance failed", groupID), err)
+		}
+	}
+}
+
+// render returns the specs of the template for the target keyed by kind, including the match group
+func render(t *model.Template, target *model.TemplateTarget, groupName string) map[string]map[string]interface{} {
+	specs := make(map[string]map[string]interface{}, len(t.Policies)+1)
+	group := copySpec(t.MatchGroup)
+	if target != nil && target.Services != "" {
+		group["services"] = target.Services
+	}
+	if groupName != "" {
+		SetAliasIfEmpty(group, groupName)
+	}
+	specs[KindMatchGroup] = group
+	for kind, spec := range t.Policies {
+		specs[kind] = copySpec(spec)
+	}
+	return specs
+}
+
+func newTemplatePolicy(name string, target *model.TemplateTarget, spec map[string]interface{}) *model.Policy {
+	selector := model.Selector{KeyApp: target.App}
+	if target.Environment != "" {
+		selector[KeyEnvironment] = target.Environment
+	}
+	return &model.Policy{
+		GovernancePolicy: &model.GovernancePolicy{Name: name, Selector: selector},
+		Spec:             spec,
+	}
+}
+
+func validateTemplate(t *model.Template) error {
+	if t.Name == "" {
+		return discovery.NewError(discovery.ErrInvalidParams, "template name is required")
+	}
+	if len(t.Policies) == 0 {
+		return discovery.NewError(discovery.ErrInvalidParams, "template policies are required")
+	}
+	group := copySpec(t.MatchGroup)
+	SetAliasIfEmpty(group, t.Name)
+	if err := ValidatePolicySpec(KindMatchGroup, group); err != nil {
+		return discovery.NewError(discovery.ErrInvalidParams, err.Error())
+	}
+	for kind, spec := range t.Policies {
+		if kind == KindMatchGroup {
+			return discovery.NewError(discovery.ErrInvalidParams, "match group can not be a template policy")
+		}
+		if err := ValidatePolicySpec(kind, spec); err != nil {
+			return discovery.NewError(discovery.ErrInvalidParams, err.Error())
+		}
+	}
+	return nil
+}
+
+// diffPolicies returns the changes of the specs keyed by kind, the change path is prefixed by the kind
+func diffPolicies(old, new map[string]map[string]interface{}) []*model.Change {
+	kinds := make(map[string]map[string]interface{}, len(old)+len(new))
+	for kind, spec := range old {
+		kinds[kind] = spec
+	}
+	for kind, spec := range new {
+		kinds[kind] = spec
+	}
+	var changes []*model.Change
+	for _, kind := range sortedKinds(kinds) {
+		oldSpec, ok := old[kind]
+		var o *model.Policy
+		if ok {
+			o = templatePolicy(oldSpec)
+		}
+		newSpec, ok := new[kind]
+		var n *model.Policy
+		if ok {
+			n = templatePolicy(newSpec)
+		}
+		for _, c := range model.Diff(o, n) {
+			c.Path = kind + "." + c.Path
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+func templatePolicy(spec map[string]interface{}) *model.Policy {
+	return &model.Policy{GovernancePolicy: &model.GovernancePolicy{}, Spec: spec}
+}
+
+func getPolicy(ctx context.Context, kind, id, project string) (*model.Policy, error) {
+	b, err := Get(ctx, kind, id, project)
+	if err != nil {
+		return nil, err
+	}
+	p := &model.Policy{}
+	if err = json.Unmarshal(b, p); err != nil {
+		return nil, err
+	}
+	if p.GovernancePolicy == nil {
+		return nil, fmt.Errorf("policy [%s] not found", id)
+	}
+	return p, nil
+}
+
+// copySpec deep copies the spec by json, so the template is never changed by the distributors
+func copySpec(spec map[string]interface{}) map[string]interface{} {
+	r := make(map[string]interface{})
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return r
+	}
+	_ = json.Unmarshal(b, &r)
+	return r
+}
+
+// sortedKinds returns the kinds in order, so the policies are created in the same order
+func sortedKinds(specs map[string]map[string]interface{}) []string {
+	kinds := make([]string, 0, len(specs))
+	for kind := range specs {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
@@ -0,0 +1,445 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/dlock"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/foundation/gopool"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	probeds "github.com/apache/servicecomb-service-center/datasource/probe"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+const (
+	StatusUnknown   = "unknown"
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+
+	lockKey      = "instance-probe-job"
+	tickInterval = time.Second
+)
+
+// Result is the probe result of an instance, it is persisted to be queried in any service center
+type Result = probeds.Result
+
+// Actions changes the instance by the probe results
+type Actions interface {
+	Heartbeat(ctx context.Context, t *Target) error
+	PutStatus(ctx context.Context, t *Target, status string) error
+	Unregister(ctx context.Context, t *Target) error
+}
+
+// Options is the probe manager options
+type Options struct {
+	Workers        int
+	Timeout        time.Duration
+	ResyncInterval time.Duration
+	// UnregisterAfter unregisters the instance if it keeps failing for the time, 0 to never unregister
+	UnregisterAfter time.Duration
+}
+
+type entry struct {
+	target  *Target
+	result  *Result
+	next    time.Time
+	running bool
+	// saved is the time the result persisted
+	saved time.Time
+}
+
+// Manager probes the targets in a worker pool, only the service center holding
+// the lock probes, and persists the results when they change, or at least once
+// in the resync interval to refresh the last probe time
+type Manager struct {
+	opts    Options
+	actions Actions
+	probe   func(ctx context.Context, t *Target, timeout time.Duration) error
+	listFn  func(ctx context.Context) ([]*Target, error)
+	daoFn   func() probeds.DAO
+
+	lock    sync.RWMutex
+	entries map[string]*entry
+	jobs    chan *entry
+}
+
+var defaultManager = NewManager(Options{}, nil)
+
+// Init replaces the default manager, it should be called before Run
+func Init(opts Options, actions Actions) *Manager {
+	defaultManager = NewManager(opts, actions)
+	return defaultManager
+}
+
+// GetManager returns the default manager
+func GetManager() *Manager {
+	return defaultManager
+}
+
+func NewManager(opts Options, actions Actions) *Manager {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	return &Manager{
+		opts:    opts,
+		actions: actions,
+		probe:   Probe,
+		listFn:  ListTargets,
+		daoFn:   probeds.Instance,
+		entries: make(map[string]*entry),
+		jobs:    make(chan *entry, opts.Workers),
+	}
+}
+
+// Run starts the workers and the scheduler
+func (m *Manager) Run() {
+	for i := 0; i < m.opts.Workers; i++ {
+		gopool.Go(m.work)
+	}
+	gopool.Go(m.schedule)
+}
+
+func (m *Manager) schedule(ctx context.Context) {
+	m.resync(ctx)
+	resync := time.NewTicker(m.opts.ResyncInterval)
+	defer resync.Stop()
+	tick := time.NewTicker(tickInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-resync.C:
+			m.resync(ctx)
+		case now := <-tick.C:
+			m.dispatch(now)
+		}
+	}
+}
+
+func (m *Manager) resync(ctx context.Context) {
+	if !m.holdLock() {
+		m.Sync(nil)
+		return
+	}
+	targets, err := m.listFn(ctx)
+	if err != nil {
+		log.Error("list probe targets failed", err)
+		return
+	}
+	results := m.listResults(ctx)
+	m.sync(targets, results)
+	m.prune(ctx, results)
+}
+
+// listResults returns the persisted results by the target keys, nil if failed
+func (m *Manager) listResults(ctx context.Context) map[string]*Result {
+	dao := m.daoFn()
+	if dao == nil {
+		return nil
+	}
+	results, err := dao.ListAllResults(ctx)
+	if err != nil {
+		log.Error("list probe results failed", err)
+		return nil
+	}
+	persisted := make(map[string]*Result, len(results))
+	for _, r := range results {
+		persisted[resultTarget(r).Key()] = r
+	}
+	return persisted
+}
+
+func resultTarget(r *Result) *Target {
+	return &Target{Domain: r.Domain, Project: r.Project, ServiceID: r.ServiceID, InstanceID: r.InstanceID}
+}
+
+// prune removes the persisted results of the instances which are not probed any more
+func (m *Manager) prune(ctx context.Context, persisted map[string]*Result) {
+	for key, r := range persisted {
+		m.lock.RLock()
+		_, ok := m.entries[key]
+		m.lock.RUnlock()
+		if !ok {
+			m.deleteResult(ctx, resultTarget(r))
+		}
+	}
+}
+
+func (m *Manager) saveResult(ctx context.Context, t *Target, r *Result) {
+	dao := m.daoFn()
+	if dao == nil {
+		return
+	}
+	if err := dao.PutResult(targetContext(ctx, t), r); err != nil {
+		log.Error(fmt.Sprintf("save instance[%s] probe result failed", t.Key()), err)
+	}
+}
+
+func (m *Manager) deleteResult(ctx context.Context, t *Target) {
+	dao := m.daoFn()
+	if dao == nil {
+		return
+	}
+	if err := dao.DeleteResult(targetContext(ctx, t), t.ServiceID, t.InstanceID); err != nil {
+		log.Error(fmt.Sprintf("delete instance[%s] probe result failed", t.Key()), err)
+	}
+}
+
+func targetContext(ctx context.Context, t *Target) context.Context {
+	return util.SetDomainProject(util.CloneContext(ctx), t.Domain, t.Project)
+}
+
+func (m *Manager) holdLock() bool {
+	ttl := int64(2 * m.opts.ResyncInterval / time.Second)
+	if dlock.IsHoldLock(lockKey) {
+		if err := dlock.Renew(lockKey); err == nil {
+			return true
+		}
+	}
+	return dlock.TryLock(lockKey, ttl) == nil
+}
+
+// Sync replaces the targets, the results of the existing ones are kept
+func (m *Manager) Sync(targets []*Target) {
+	m.sync(targets, nil)
+}
+
+// sync replaces the targets, the new ones start from the persisted results if any, so that
+// the instances marked DOWN by the previous lock holder can be marked UP again once recovered
+func (m *Manager) sync(targets []*Target, persisted map[string]*Result) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	entries := make(map[string]*entry, len(targets))
+	for _, t := range targets {
+		key := t.Key()
+		e, ok := m.entries[key]
+		if !ok {
+			r := &Result{Domain: t.Domain, Project: t.Project, ServiceID: t.ServiceID,
+				InstanceID: t.InstanceID, Status: StatusUnknown}
+			if p, ok := persisted[key]; ok {
+				c := *p
+				r = &c
+			}
+			e = &entry{result: r}
+		}
+		e.target = t
+		e.result.URL = t.URL
+		entries[key] = e
+	}
+	m.entries = entries
+}
+
+func (m *Manager) dispatch(now time.Time) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, e := range m.entries {
+		if e.running || now.Before(e.next) {
+			continue
+		}
+		select {
+		case m.jobs <- e:
+			e.running = true
+		default:
+			// all workers are busy, dispatch in next tick
+			return
+		}
+	}
+}
+
+func (m *Manager) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-m.jobs:
+			m.lock.RLock()
+			t := e.target
+			m.lock.RUnlock()
+			err := m.probe(ctx, t, m.opts.Timeout)
+			m.handle(ctx, e, err, time.Now())
+		}
+	}
+}
+
+// handle changes the instance by the probe result, it heartbeats to keep the instance alive
+// until the consecutive failures reached the times, then marks it DOWN and stops heartbeating,
+// so that it is evicted by the lease TTL unless its client heartbeats. It marks the instance
+// UP again once recovered, and unregisters it if failing too long
+func (m *Manager) handle(ctx context.Context, e *entry, probeErr error, now time.Time) {
+	m.lock.Lock()
+	t, r := e.target, e.result
+	last := *r
+	r.LastProbeTime = now.Unix()
+	e.next = now.Add(t.Interval)
+	e.running = false
+	var status string
+	unregister := false
+	if probeErr == nil {
+		r.Status, r.Failures, r.FailingSince, r.LastError = StatusHealthy, 0, 0, ""
+		if r.MarkedDown {
+			status, r.MarkedDown = pb.MSI_UP, false
+		}
+	} else {
+		r.Failures++
+		r.LastError = probeErr.Error()
+		if r.FailingSince == 0 {
+			r.FailingSince = now.Unix()
+		}
+		if r.Failures >= t.Times {
+			r.Status = StatusUnhealthy
+			if !r.MarkedDown && t.Status != pb.MSI_DOWN {
+				status, r.MarkedDown = pb.MSI_DOWN, true
+			}
+		}
+		unregister = m.opts.UnregisterAfter > 0 && r.Status == StatusUnhealthy &&
+			now.Sub(time.Unix(r.FailingSince, 0)) >= m.opts.UnregisterAfter
+	}
+	if len(status) > 0 {
+		t.Status = status
+	}
+	healthStatus := r.Status
+	var save *Result
+	if r.Status != last.Status || r.Failures != last.Failures || r.MarkedDown != last.MarkedDown ||
+		r.URL != last.URL || now.Sub(e.saved) >= m.opts.ResyncInterval {
+		e.saved = now
+		c := *r
+		save = &c
+	}
+	m.lock.Unlock()
+
+	if unregister {
+		log.Warn(fmt.Sprintf("instance[%s] keeps failing the probe %s, unregister it", t.Key(), t.URL))
+		if err := m.actions.Unregister(ctx, t); err != nil {
+			log.Error(fmt.Sprintf("unregister instance[%s] failed", t.Key()), err)
+		}
+		m.remove(ctx, t)
+		return
+	}
+	if save != nil {
+		m.saveResult(ctx, t, save)
+	}
+	if len(status) > 0 {
+		log.Info(fmt.Sprintf("instance[%s] probe %s is %s, set status %s", t.Key(), t.URL, healthStatus, status))
+		if err := m.actions.PutStatus(ctx, t, status); err != nil {
+			log.Error(fmt.Sprintf("update instance[%s] status failed", t.Key()), err)
+		}
+	}
+	if healthStatus == StatusUnhealthy {
+		return
+	}
+	if err := m.actions.Heartbeat(ctx, t); err != nil {
+		log.Error(fmt.Sprintf("heartbeat instance[%s] failed", t.Key()), err)
+		if isNotExist(err) {
+			m.remove(ctx, t)
+		}
+	}
+}
+
+func isNotExist(err error) bool {
+	e, ok := err.(*errsvc.Error)
+	return ok && e.Code == pb.ErrInstanceNotExists
+}
+
+func (m *Manager) remove(ctx context.Context, t *Target) {
+	m.lock.Lock()
+	delete(m.entries, t.Key())
+	m.lock.Unlock()
+	m.deleteResult(ctx, t)
+}
+
+// Get returns the probe result of the instance
+func (m *Manager) Get(domain, project, serviceID, instanceID string) (*Result, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	e, ok := m.entries[(&Target{Domain: domain, Project: project, ServiceID: serviceID, InstanceID: instanceID}).Key()]
+	if !ok {
+		return nil, false
+	}
+	r := *e.result
+	return &r, true
+}
+
+// List returns the probe results of the service instances
+func (m *Manager) List(domain, project, serviceID string) []*Result {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	results := make([]*Result, 0)
+	for _, e := range m.entries {
+		t := e.target
+		if t.Domain != domain || t.Project != project || t.ServiceID != serviceID {
+			continue
+		}
+		r := *e.result
+		results = append(results, &r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].InstanceID < results[j].InstanceID
+	})
+	return results
+}
+
+// GetResult returns the persisted probe result of the instance in the domain project of ctx
+func GetResult(ctx context.Context, serviceID, instanceID string) (*Result, error) {
+	return probeds.Instance().GetResult(ctx, serviceID, instanceID)
+}
+
+// ListResults returns the persisted probe results of the service instances in the domain project of ctx
+func ListResults(ctx context.Context, serviceID string) ([]*Result, error) {
+	results, err := probeds.Instance().ListResults(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].InstanceID < results[j].InstanceID
+	})
+	return results, nil
+}
+
+// ListTargets lists the instances declared probes in all domains and projects
+func ListTargets(ctx context.Context) ([]*Target, error) {
+	list, err := datasource.GetMetadataManager().ListAllServiceInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var targets []*Target
+	for _, si := range list {
+		for _, instance := range si.Instances {
+			t, err := NewTarget(si.Service, instance)
+			if err != nil {
+				log.Warn(fmt.Sprintf("instance[%s/%s/%s] probe is invalid: %s", si.Domain, si.Project, instance.InstanceId, err))
+				continue
+			}
+			if t == nil {
+				continue
+			}
+			t.Domain, t.Project = si.Domain, si.Project
+			targets = append(targets, t)
+		}
+	}
+	return targets, nil
+}
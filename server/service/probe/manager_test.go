@@ -0,0 +1,225 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/stretchr/testify/assert"
+
+	probeds "github.com/apache/servicecomb-service-center/datasource/probe"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+type fakeActions struct {
+	heartbeats   int
+	status       []string
+	unregistered bool
+	heartbeatErr error
+}
+
+func (a *fakeActions) Heartbeat(_ context.Context, _ *Target) error {
+	a.heartbeats++
+	return a.heartbeatErr
+}
+
+func (a *fakeActions) PutStatus(_ context.Context, _ *Target, status string) error {
+	a.status = append(a.status, status)
+	return nil
+}
+
+func (a *fakeActions) Unregister(_ context.Context, _ *Target) error {
+	a.unregistered = true
+	return nil
+}
+
+type fakeDAO struct {
+	results map[string]*Result
+}
+
+func (d *fakeDAO) PutResult(ctx context.Context, r *Result) error {
+	c := *r
+	c.Domain, c.Project = util.ParseDomain(ctx), util.ParseProject(ctx)
+	d.results[c.Domain+"/"+c.Project+"/"+c.ServiceID+"/"+c.InstanceID] = &c
+	return nil
+}
+
+func (d *fakeDAO) GetResult(_ context.Context, _, _ string) (*Result, error) {
+	return nil, probeds.ErrResultNotExists
+}
+
+func (d *fakeDAO) ListResults(_ context.Context, _ string) ([]*Result, error) {
+	return nil, nil
+}
+
+func (d *fakeDAO) ListAllResults(_ context.Context) ([]*Result, error) {
+	list := make([]*Result, 0, len(d.results))
+	for _, r := range d.results {
+		list = append(list, r)
+	}
+	return list, nil
+}
+
+func (d *fakeDAO) DeleteResult(ctx context.Context, serviceID, instanceID string) error {
+	delete(d.results, util.ParseDomain(ctx)+"/"+util.ParseProject(ctx)+"/"+serviceID+"/"+instanceID)
+	return nil
+}
+
+func newTestManager(opts Options) (*Manager, *fakeActions, *entry) {
+	actions := &fakeActions{}
+	m := NewManager(opts, actions)
+	m.Sync([]*Target{{Domain: "d", Project: "p", ServiceID: "s", InstanceID: "i",
+		Status: pb.MSI_UP, URL: "tcp://:80", Interval: time.Second, Times: 2}})
+	return m, actions, m.entries["d/p/s/i"]
+}
+
+func TestManager_Handle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	errProbe := errors.New("refused")
+
+	t.Run("failures reached times should mark DOWN, recovery should mark UP", func(t *testing.T) {
+		m, actions, e := newTestManager(Options{})
+
+		m.handle(ctx, e, errProbe, now)
+		r, ok := m.Get("d", "p", "s", "i")
+		assert.True(t, ok)
+		assert.Equal(t, StatusUnknown, r.Status)
+		assert.Equal(t, 1, r.Failures)
+		assert.Empty(t, actions.status)
+
+		m.handle(ctx, e, errProbe, now)
+		m.handle(ctx, e, errProbe, now)
+		r, _ = m.Get("d", "p", "s", "i")
+		assert.Equal(t, StatusUnhealthy, r.Status)
+		assert.True(t, r.MarkedDown)
+		assert.Equal(t, "refused", r.LastError)
+		assert.Equal(t, []string{pb.MSI_DOWN}, actions.status)
+
+		m.handle(ctx, e, nil, now)
+		r, _ = m.Get("d", "p", "s", "i")
+		assert.Equal(t, StatusHealthy, r.Status)
+		assert.Equal(t, 0, r.Failures)
+		assert.False(t, r.MarkedDown)
+		assert.Equal(t, []string{pb.MSI_DOWN, pb.MSI_UP}, actions.status)
+		// no heartbeat when unhealthy, so that the instance can be evicted by the lease TTL
+		assert.Equal(t, 2, actions.heartbeats)
+	})
+
+	t.Run("sync should keep the results", func(t *testing.T) {
+		m, _, e := newTestManager(Options{})
+		m.handle(ctx, e, errProbe, now)
+		m.Sync([]*Target{e.target, {Domain: "d", Project: "p", ServiceID: "s", InstanceID: "i2"}})
+		r, _ := m.Get("d", "p", "s", "i")
+		assert.Equal(t, 1, r.Failures)
+		assert.Equal(t, 2, len(m.List("d", "p", "s")))
+
+		m.Sync(nil)
+		_, ok := m.Get("d", "p", "s", "i")
+		assert.False(t, ok)
+	})
+
+	t.Run("failing too long should unregister", func(t *testing.T) {
+		m, actions, e := newTestManager(Options{UnregisterAfter: time.Minute})
+		m.handle(ctx, e, errProbe, now)
+		m.handle(ctx, e, errProbe, now)
+		assert.False(t, actions.unregistered)
+		m.handle(ctx, e, errProbe, now.Add(time.Minute))
+		assert.True(t, actions.unregistered)
+		_, ok := m.Get("d", "p", "s", "i")
+		assert.False(t, ok)
+	})
+
+	t.Run("instance not exist should remove the target", func(t *testing.T) {
+		m, actions, e := newTestManager(Options{})
+		actions.heartbeatErr = pb.NewError(pb.ErrInstanceNotExists, "not exist")
+		m.handle(ctx, e, nil, now)
+		_, ok := m.Get("d", "p", "s", "i")
+		assert.False(t, ok)
+
+		actions.heartbeatErr = &errsvc.Error{Code: pb.ErrInternal}
+		m.Sync([]*Target{e.target})
+		m.handle(ctx, e, nil, now)
+		_, ok = m.Get("d", "p", "s", "i")
+		assert.True(t, ok)
+	})
+}
+
+func TestManager_Persist(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	dao := &fakeDAO{results: make(map[string]*Result)}
+	m, actions, e := newTestManager(Options{ResyncInterval: time.Minute})
+	m.daoFn = func() probeds.DAO { return dao }
+
+	t.Run("changed result should be saved", func(t *testing.T) {
+		m.handle(ctx, e, nil, now)
+		r, ok := dao.results["d/p/s/i"]
+		if assert.True(t, ok) {
+			assert.Equal(t, StatusHealthy, r.Status)
+			assert.Equal(t, now.Unix(), r.LastProbeTime)
+		}
+
+		m.handle(ctx, e, nil, now.Add(time.Second))
+		assert.Equal(t, now.Unix(), dao.results["d/p/s/i"].LastProbeTime)
+
+		m.handle(ctx, e, errors.New("refused"), now.Add(2*time.Second))
+		assert.Equal(t, 1, dao.results["d/p/s/i"].Failures)
+
+		m.handle(ctx, e, nil, now.Add(time.Minute))
+		assert.Equal(t, 0, dao.results["d/p/s/i"].Failures)
+	})
+
+	t.Run("not probed any more should be pruned", func(t *testing.T) {
+		dao.results["d/p/s/gone"] = &Result{Domain: "d", Project: "p", ServiceID: "s", InstanceID: "gone"}
+		m.prune(ctx, m.listResults(ctx))
+		_, ok := dao.results["d/p/s/gone"]
+		assert.False(t, ok)
+		_, ok = dao.results["d/p/s/i"]
+		assert.True(t, ok)
+	})
+
+	t.Run("new target should start from the persisted result", func(t *testing.T) {
+		dao.results["d/p/s/down"] = &Result{Domain: "d", Project: "p", ServiceID: "s", InstanceID: "down",
+			Status: StatusUnhealthy, Failures: 3, MarkedDown: true}
+		down := &Target{Domain: "d", Project: "p", ServiceID: "s", InstanceID: "down",
+			Status: pb.MSI_DOWN, URL: "tcp://:80", Interval: time.Second, Times: 2}
+		m.sync([]*Target{e.target, down}, m.listResults(ctx))
+		r, ok := m.Get("d", "p", "s", "down")
+		if assert.True(t, ok) {
+			assert.True(t, r.MarkedDown)
+			assert.Equal(t, 3, r.Failures)
+		}
+
+		m.handle(ctx, m.entries[down.Key()], nil, now.Add(time.Minute))
+		assert.Equal(t, []string{pb.MSI_UP}, actions.status)
+		m.Sync([]*Target{e.target})
+		m.prune(ctx, m.listResults(ctx))
+	})
+
+	t.Run("instance not exist should delete the result", func(t *testing.T) {
+		actions.heartbeatErr = pb.NewError(pb.ErrInstanceNotExists, "not exist")
+		m.handle(ctx, e, nil, now.Add(2*time.Minute))
+		assert.Empty(t, dao.results)
+	})
+}
@@ -0,0 +1,246 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package probe checks the health of the instances which can not send heartbeats,
+// the service center probes the endpoints actively and keeps the instances alive
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	SchemeHTTP  = "http"
+	SchemeHTTPS = "https"
+	SchemeTCP   = "tcp"
+	SchemeGRPC  = "grpc"
+
+	// PropertyHealthProbe is the service property declaring the probe of all its instances,
+	// the value is the same as the url of the instance pull mode health check, e.g.
+	// http://:8080/health, tcp://:8080 or grpc://:9090/service.name, the host and port
+	// can be omitted and the ones of the first instance endpoint are used, and the host
+	// must be one of the instance endpoints
+	PropertyHealthProbe = "healthProbe"
+
+	defaultInterval = 30 * time.Second
+	defaultTimes    = 3
+)
+
+var (
+	ErrUnsupportedScheme = errors.New("unsupported probe scheme")
+	ErrNoAddress         = errors.New("can not resolve probe address")
+	ErrHostNotAllowed    = errors.New("probe host is not an endpoint host of the instance")
+)
+
+// Target is the endpoint to probe of an instance
+type Target struct {
+	Domain     string
+	Project    string
+	ServiceID  string
+	InstanceID string
+	// Status is the instance status when the target resolved
+	Status string
+
+	// URL is the declared probe
+	URL     string
+	Scheme  string
+	Address string
+	// Path is the http path, or the service name of grpc health check
+	Path string
+
+	Interval time.Duration
+	// Times is the consecutive failures to mark the instance DOWN
+	Times int
+}
+
+// Key returns the unique key of the target
+func (t *Target) Key() string {
+	return strings.Join([]string{t.Domain, t.Project, t.ServiceID, t.InstanceID}, "/")
+}
+
+// NewTarget resolves the probe target of the instance, it returns nil if no probe declared.
+// The instance pull mode health check url takes precedence over the service property
+func NewTarget(service *pb.MicroService, instance *pb.MicroServiceInstance) (*Target, error) {
+	hc := instance.HealthCheck
+	spec := ""
+	if hc != nil && hc.Mode == pb.CHECK_BY_PLATFORM && len(hc.Url) > 0 {
+		spec = hc.Url
+	} else if service != nil {
+		spec = service.Properties[PropertyHealthProbe]
+	}
+	if len(spec) == 0 {
+		return nil, nil
+	}
+
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	scheme := strings.ToLower(u.Scheme)
+	switch scheme {
+	case SchemeHTTP, SchemeHTTPS, SchemeTCP, SchemeGRPC:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedScheme, u.Scheme)
+	}
+
+	host, port := u.Hostname(), u.Port()
+	if len(port) == 0 && hc != nil && hc.Port > 0 {
+		port = strconv.Itoa(int(hc.Port))
+	}
+	if len(host) > 0 && !isEndpointHost(instance.Endpoints, host) {
+		// the service center must not be used to access the other hosts
+		return nil, fmt.Errorf("%w: %s", ErrHostNotAllowed, host)
+	}
+	if len(host) == 0 || len(port) == 0 {
+		epHost, epPort := endpointHostPort(instance.Endpoints)
+		if len(host) == 0 {
+			host = epHost
+		}
+		if len(port) == 0 {
+			port = epPort
+		}
+	}
+	if len(host) == 0 || len(port) == 0 {
+		return nil, ErrNoAddress
+	}
+
+	t := &Target{
+		ServiceID:  instance.ServiceId,
+		InstanceID: instance.InstanceId,
+		Status:     instance.Status,
+		URL:        spec,
+		Scheme:     scheme,
+		Address:    net.JoinHostPort(host, port),
+		Path:       u.RequestURI(),
+		Interval:   defaultInterval,
+		Times:      defaultTimes,
+	}
+	if scheme == SchemeGRPC {
+		t.Path = strings.TrimPrefix(u.Path, "/")
+	}
+	if hc != nil && hc.Interval > 0 {
+		t.Interval = time.Duration(hc.Interval) * time.Second
+	}
+	if hc != nil && hc.Times > 0 {
+		t.Times = int(hc.Times)
+	}
+	return t, nil
+}
+
+// endpointHostPort returns the host and port of the first endpoint, e.g. rest://127.0.0.1:8080?sslEnabled=false
+func endpointHostPort(endpoints []string) (string, string) {
+	for _, ep := range endpoints {
+		u, err := url.Parse(ep)
+		if err != nil || len(u.Host) == 0 {
+			continue
+		}
+		return u.Hostname(), u.Port()
+	}
+	return "", ""
+}
+
+func isEndpointHost(endpoints []string, host string) bool {
+	for _, ep := range endpoints {
+		u, err := url.Parse(ep)
+		if err == nil && strings.EqualFold(u.Hostname(), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Probe checks the target once, returns nil if healthy
+func Probe(ctx context.Context, t *Target, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	switch t.Scheme {
+	case SchemeHTTP, SchemeHTTPS:
+		return probeHTTP(ctx, t)
+	case SchemeTCP:
+		return probeTCP(ctx, t)
+	case SchemeGRPC:
+		return probeGRPC(ctx, t)
+	default:
+		return ErrUnsupportedScheme
+	}
+}
+
+// the https probe verifies the server certificates by the system roots, and the redirects
+// are not followed, as the location may be a host other than the instance endpoints
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		DisableKeepAlives: true,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func probeHTTP(ctx context.Context, t *Target) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.Scheme+"://"+t.Address+t.Path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unhealthy http status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func probeTCP(ctx context.Context, t *Target) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", t.Address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func probeGRPC(ctx context.Context, t *Target) error {
+	conn, err := grpc.DialContext(ctx, t.Address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: t.Path})
+	if err != nil {
+		return err
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("unhealthy grpc status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/apache/servicecomb-service-center/server/service/probe"
+)
+
+func TestNewTarget(t *testing.T) {
+	instance := &pb.MicroServiceInstance{
+		ServiceId:  "s1",
+		InstanceId: "i1",
+		Endpoints:  []string{"rest://127.0.0.1:8080?sslEnabled=false"},
+	}
+
+	t.Run("no probe declared should return nil", func(t *testing.T) {
+		target, err := probe.NewTarget(&pb.MicroService{}, instance)
+		assert.NoError(t, err)
+		assert.Nil(t, target)
+	})
+
+	t.Run("service property without address should use the endpoint", func(t *testing.T) {
+		service := &pb.MicroService{Properties: map[string]string{probe.PropertyHealthProbe: "http:///health?full=1"}}
+		target, err := probe.NewTarget(service, instance)
+		assert.NoError(t, err)
+		assert.Equal(t, probe.SchemeHTTP, target.Scheme)
+		assert.Equal(t, "127.0.0.1:8080", target.Address)
+		assert.Equal(t, "/health?full=1", target.Path)
+		assert.Equal(t, 30*time.Second, target.Interval)
+		assert.Equal(t, 3, target.Times)
+	})
+
+	t.Run("instance health check should take precedence", func(t *testing.T) {
+		service := &pb.MicroService{Properties: map[string]string{probe.PropertyHealthProbe: "tcp://:9000"}}
+		i := *instance
+		i.Endpoints = append(i.Endpoints, "grpc://10.0.0.1:9000")
+		i.HealthCheck = &pb.HealthCheck{Mode: pb.CHECK_BY_PLATFORM, Url: "grpc://10.0.0.1/svc.Name", Port: 9090, Interval: 5, Times: 1}
+		target, err := probe.NewTarget(service, &i)
+		assert.NoError(t, err)
+		assert.Equal(t, probe.SchemeGRPC, target.Scheme)
+		assert.Equal(t, "10.0.0.1:9090", target.Address)
+		assert.Equal(t, "svc.Name", target.Path)
+		assert.Equal(t, 5*time.Second, target.Interval)
+		assert.Equal(t, 1, target.Times)
+	})
+
+	t.Run("host not of the endpoints should return error", func(t *testing.T) {
+		service := &pb.MicroService{Properties: map[string]string{probe.PropertyHealthProbe: "http://169.254.169.254/latest"}}
+		_, err := probe.NewTarget(service, instance)
+		assert.ErrorIs(t, err, probe.ErrHostNotAllowed)
+	})
+
+	t.Run("unsupported scheme should return error", func(t *testing.T) {
+		service := &pb.MicroService{Properties: map[string]string{probe.PropertyHealthProbe: "udp://:53"}}
+		_, err := probe.NewTarget(service, instance)
+		assert.ErrorIs(t, err, probe.ErrUnsupportedScheme)
+	})
+
+	t.Run("no endpoint should return error", func(t *testing.T) {
+		service := &pb.MicroService{Properties: map[string]string{probe.PropertyHealthProbe: "tcp://"}}
+		_, err := probe.NewTarget(service, &pb.MicroServiceInstance{})
+		assert.ErrorIs(t, err, probe.ErrNoAddress)
+	})
+}
+
+func TestProbe(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("http", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/health" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+		address := server.Listener.Addr().String()
+
+		err := probe.Probe(ctx, &probe.Target{Scheme: probe.SchemeHTTP, Address: address, Path: "/health"}, time.Second)
+		assert.NoError(t, err)
+		err = probe.Probe(ctx, &probe.Target{Scheme: probe.SchemeHTTP, Address: address, Path: "/"}, time.Second)
+		assert.Error(t, err)
+	})
+
+	t.Run("http redirect should not be followed", func(t *testing.T) {
+		redirected := false
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			redirected = true
+		}))
+		defer other.Close()
+		server := httptest.NewServer(http.RedirectHandler(other.URL+"/health", http.StatusFound))
+		defer server.Close()
+
+		err := probe.Probe(ctx, &probe.Target{Scheme: probe.SchemeHTTP, Address: server.Listener.Addr().String(), Path: "/health"}, time.Second)
+		assert.NoError(t, err)
+		assert.False(t, redirected)
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		address := l.Addr().String()
+
+		err = probe.Probe(ctx, &probe.Target{Scheme: probe.SchemeTCP, Address: address}, time.Second)
+		assert.NoError(t, err)
+		l.Close()
+		err = probe.Probe(ctx, &probe.Target{Scheme: probe.SchemeTCP, Address: address}, time.Second)
+		assert.Error(t, err)
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		server := grpc.NewServer()
+		hs := health.NewServer()
+		hs.SetServingStatus("svc", healthpb.HealthCheckResponse_NOT_SERVING)
+		healthpb.RegisterHealthServer(server, hs)
+		go server.Serve(l)
+		defer server.Stop()
+		address := l.Addr().String()
+
+		err = probe.Probe(ctx, &probe.Target{Scheme: probe.SchemeGRPC, Address: address}, time.Second)
+		assert.NoError(t, err)
+		err = probe.Probe(ctx, &probe.Target{Scheme: probe.SchemeGRPC, Address: address, Path: "svc"}, time.Second)
+		assert.Error(t, err)
+	})
+}
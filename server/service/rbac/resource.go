@@ -51,13 +51,15 @@ var (
 
 	APIOps = "/v4/:project/admin"
 
-	APIGov = "/v1/:project/gov/"
+	APIGov         = "/v1/:project/gov/"
+	APIGovTemplate = "/v1/:project/gov-templates"
 
 	APILegacyGov = "/v4/:project/govern"
 
 	APIServiceInfo       = "/v4/:project/registry/microservices/:serviceId"
 	APIServicesList      = "/v4/:project/registry/microservices"
 	APIServiceProperties = "/v4/:project/registry/microservices/:serviceId/properties"
+	APIServiceCanary     = "/v4/:project/registry/microservices/:serviceId/canary"
 	APIServiceExistence  = "/v4/:project/registry/existence"
 
 	APIProConDependency = "/v4/:project/registry/microservices/:providerId/consumers"
@@ -66,6 +68,7 @@ var (
 	APIHeartbeats          = "/v4/:project/registry/heartbeats"
 	APIInstanceWatcher     = "/v4/:project/registry/microservices/:serviceId/watcher"
 	APIInstanceListWatcher = "/v4/:project/registry/microservices/:serviceId/listwatcher"
+	APIInstanceProbes      = "/v4/:project/registry/microservices/:serviceId/probes"
 
 	APIServiceTag    = "/v4/:project/registry/microservices/:serviceId/tags"
 	APIServiceTagKey = "/v4/:project/registry/microservices/:serviceId/tags/:key"
@@ -84,6 +87,7 @@ func InitResourceMap() {
 	rbac.PartialMapResource(APIAccountList, ResourceAccount)
 	rbac.PartialMapResource(APIRoleList, ResourceRole)
 	rbac.PartialMapResource(APIGov, ResourceGovern)
+	rbac.PartialMapResource(APIGovTemplate, ResourceGovern)
 	rbac.PartialMapResource(APIServiceSchema, ResourceSchema)
 	rbac.PartialMapResource(APIOps, ResourceOps)
 	rbac.PartialMapResource("instances", ResourceService)
@@ -93,12 +97,14 @@ func InitResourceMap() {
 	rbac.MapResource(APIServiceInfo, ResourceService)
 	rbac.MapResource(APIServicesList, ResourceService)
 	rbac.MapResource(APIServiceProperties, ResourceService)
+	rbac.MapResource(APIServiceCanary, ResourceService)
 	rbac.MapResource(APIServiceExistence, ResourceService)
 	rbac.MapResource(APIProConDependency, ResourceService)
 	rbac.MapResource(APIConProDependency, ResourceService)
 	rbac.MapResource(APIHeartbeats, ResourceService)
 	rbac.MapResource(APIInstanceWatcher, ResourceService)
 	rbac.MapResource(APIInstanceListWatcher, ResourceService)
+	rbac.MapResource(APIInstanceProbes, ResourceService)
 	rbac.MapResource(APIServiceRuleList, ResourceService)
 	rbac.MapResource(APIServiceRule, ResourceService)
 	rbac.MapResource(APIServiceTag, ResourceService)
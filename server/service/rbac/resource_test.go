@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac_test
+
+import (
+	"testing"
+
+	"github.com/go-chassis/cari/rbac"
+	"github.com/go-chassis/go-archaius"
+	"github.com/stretchr/testify/assert"
+
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestMustAuth(t *testing.T) {
+	defer archaius.Set("rbac.scope", "*")
+	err := archaius.Set("rbac.scope", rbacsvc.ResourceGovern)
+	assert.NoError(t, err)
+	rbacsvc.InitResourceMap()
+
+	t.Run("gov template api, should be governance resource and must auth", func(t *testing.T) {
+		for _, api := range []string{
+			"/v1/:project/gov-templates",
+			"/v1/:project/gov-templates/:id",
+			"/v1/:project/gov-templates/:id/propagate",
+		} {
+			assert.Equal(t, rbacsvc.ResourceGovern, rbac.GetResource(api))
+			assert.True(t, rbacsvc.MustAuth(api))
+		}
+	})
+	t.Run("api out of scope, should not auth", func(t *testing.T) {
+		assert.False(t, rbacsvc.MustAuth(rbacsvc.APIServiceInfo))
+	})
+}
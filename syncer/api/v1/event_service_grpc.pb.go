@@ -25,6 +25,9 @@ const _ = grpc.SupportPackageIsVersion7
 type EventServiceClient interface {
 	Sync(ctx context.Context, in *EventList, opts ...grpc.CallOption) (*Results, error)
 	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthReply, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (EventService_WatchClient, error)
+	Digest(ctx context.Context, in *DigestRequest, opts ...grpc.CallOption) (*DigestReply, error)
+	SyncChunks(ctx context.Context, opts ...grpc.CallOption) (EventService_SyncChunksClient, error)
 }
 
 type eventServiceClient struct {
@@ -53,12 +56,90 @@ func (c *eventServiceClient) Health(ctx context.Context, in *HealthRequest, opts
 	return out, nil
 }
 
+func (c *eventServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (EventService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EventService_ServiceDesc.Streams[0], "/api.sync.v1.EventService/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EventService_WatchClient interface {
+	Recv() (*EventList, error)
+	grpc.ClientStream
+}
+
+type eventServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventServiceWatchClient) Recv() (*EventList, error) {
+	m := new(EventList)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *eventServiceClient) Digest(ctx context.Context, in *DigestRequest, opts ...grpc.CallOption) (*DigestReply, error) {
+	out := new(DigestReply)
+	err := c.cc.Invoke(ctx, "/api.sync.v1.EventService/Digest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) SyncChunks(ctx context.Context, opts ...grpc.CallOption) (EventService_SyncChunksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EventService_ServiceDesc.Streams[1], "/api.sync.v1.EventService/SyncChunks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventServiceSyncChunksClient{stream}
+	return x, nil
+}
+
+type EventService_SyncChunksClient interface {
+	Send(*EventChunk) error
+	CloseAndRecv() (*Results, error)
+	grpc.ClientStream
+}
+
+type eventServiceSyncChunksClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventServiceSyncChunksClient) Send(m *EventChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *eventServiceSyncChunksClient) CloseAndRecv() (*Results, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Results)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // EventServiceServer is the server API for EventService service.
 // All implementations must embed UnimplementedEventServiceServer
 // for forward compatibility
 type EventServiceServer interface {
 	Sync(context.Context, *EventList) (*Results, error)
 	Health(context.Context, *HealthRequest) (*HealthReply, error)
+	Watch(*WatchRequest, EventService_WatchServer) error
+	Digest(context.Context, *DigestRequest) (*DigestReply, error)
+	SyncChunks(EventService_SyncChunksServer) error
 	mustEmbedUnimplementedEventServiceServer()
 }
 
@@ -72,6 +153,15 @@ func (UnimplementedEventServiceServer) Sync(context.Context, *EventList) (*Resul
 func (UnimplementedEventServiceServer) Health(context.Context, *HealthRequest) (*HealthReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
 }
+func (UnimplementedEventServiceServer) Watch(*WatchRequest, EventService_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedEventServiceServer) Digest(context.Context, *DigestRequest) (*DigestReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Digest not implemented")
+}
+func (UnimplementedEventServiceServer) SyncChunks(EventService_SyncChunksServer) error {
+	return status.Errorf(codes.Unimplemented, "method SyncChunks not implemented")
+}
 func (UnimplementedEventServiceServer) mustEmbedUnimplementedEventServiceServer() {}
 
 // UnsafeEventServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -121,6 +211,71 @@ func _EventService_Health_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EventService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventServiceServer).Watch(m, &eventServiceWatchServer{stream})
+}
+
+type EventService_WatchServer interface {
+	Send(*EventList) error
+	grpc.ServerStream
+}
+
+type eventServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventServiceWatchServer) Send(m *EventList) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EventService_Digest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DigestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).Digest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.sync.v1.EventService/Digest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).Digest(ctx, req.(*DigestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_SyncChunks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EventServiceServer).SyncChunks(&eventServiceSyncChunksServer{stream})
+}
+
+type EventService_SyncChunksServer interface {
+	SendAndClose(*Results) error
+	Recv() (*EventChunk, error)
+	grpc.ServerStream
+}
+
+type eventServiceSyncChunksServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventServiceSyncChunksServer) SendAndClose(m *Results) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *eventServiceSyncChunksServer) Recv() (*EventChunk, error) {
+	m := new(EventChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // EventService_ServiceDesc is the grpc.ServiceDesc for EventService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -136,7 +291,22 @@ var EventService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Health",
 			Handler:    _EventService_Health_Handler,
 		},
+		{
+			MethodName: "Digest",
+			Handler:    _EventService_Digest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _EventService_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SyncChunks",
+			Handler:       _EventService_SyncChunks_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "event_service.proto",
 }
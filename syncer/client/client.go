@@ -22,6 +22,8 @@ import (
 
 	grpc "github.com/apache/servicecomb-service-center/pkg/rpc"
 	serverconfig "github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/syncer/config"
+	"github.com/apache/servicecomb-service-center/syncer/mtls"
 )
 
 var (
@@ -42,3 +44,16 @@ func RPClientConfig() *grpc.TLSConfig {
 	once.Do(initConfig)
 	return cfg
 }
+
+// PeerTLSConfig returns the mutual TLS config to dial the peer if the sync tls enabled,
+// otherwise returns the rpc client config
+func PeerTLSConfig(p *config.Peer) (*grpc.TLSConfig, error) {
+	if !config.GetConfig().Sync.IsTLSEnabled() {
+		return RPClientConfig(), nil
+	}
+	cfg, err := mtls.ClientConfig(config.GetConfig().Sync, p)
+	if err != nil {
+		return nil, err
+	}
+	return &grpc.TLSConfig{Config: cfg}, nil
+}
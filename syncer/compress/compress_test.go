@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compress_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/encoding"
+
+	"github.com/apache/servicecomb-service-center/syncer/compress"
+)
+
+func TestNegotiate(t *testing.T) {
+	assert.Equal(t, compress.Zstd, compress.Negotiate([]string{compress.Zstd, compress.Gzip}, compress.Supported()))
+	assert.Equal(t, compress.Gzip, compress.Negotiate([]string{compress.Gzip, compress.Zstd}, compress.Supported()))
+	assert.Equal(t, compress.Gzip, compress.Negotiate([]string{"snappy", compress.Gzip}, []string{"snappy", compress.Gzip}))
+	assert.Equal(t, "", compress.Negotiate([]string{compress.Zstd}, nil), "old peer supports nothing")
+	assert.Equal(t, "", compress.Negotiate(nil, compress.Supported()))
+}
+
+func TestZstd(t *testing.T) {
+	c := encoding.GetCompressor(compress.Zstd)
+	if !assert.NotNil(t, c) {
+		return
+	}
+	content := []byte(strings.Repeat("servicecomb syncer event ", 10000))
+
+	for i := 0; i < 3; i++ {
+		buf := &bytes.Buffer{}
+		w, err := c.Compress(buf)
+		assert.NoError(t, err)
+		_, err = w.Write(content)
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+		assert.Less(t, buf.Len(), len(content))
+
+		r, err := c.Decompress(buf)
+		assert.NoError(t, err)
+		out, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, content, out)
+	}
+}
@@ -20,6 +20,7 @@ package config
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/go-chassis/go-archaius"
 
@@ -34,21 +35,185 @@ type Config struct {
 }
 
 type Sync struct {
-	EnableOnStart bool `yaml:"enableOnStart"`
+	// Name is the name of local syncer known by the peers, it is required when
+	// local subscribes events from the peer in watch mode
+	Name          string `yaml:"name"`
+	EnableOnStart bool   `yaml:"enableOnStart"`
 	// When RbacEnabled is true, syncer's API requires the rbac token,
 	// and service-center also provides the rbac token to communicate with peer.
 	// At the same time, service-center rbac must be enabled.
 	RbacEnabled bool    `yaml:"rbacEnabled"`
 	Peers       []*Peer `yaml:"peers"`
+	// Conflict is the policies to resolve the conflicts of the replicated resources
+	Conflict *Conflict `yaml:"conflict"`
+	// LagThreshold is the max age of the oldest task pending to replicate to a peer,
+	// an alarm is raised if exceeded, default is 10m, and 0 disables the alarm
+	LagThreshold string `yaml:"lagThreshold"`
+	// TLS enables the mutual TLS between syncers, it is the default of the peers
+	TLS *TLS `yaml:"tls"`
+	// Relay enables to replicate the changes applied from a peer to the other peers, so that
+	// a hub can relay changes between spokes, it requires Name and the peers named by their Name
+	Relay bool `yaml:"relay"`
 }
 
+const defaultLagThreshold = 10 * time.Minute
+
+// GetLagThreshold returns the lag threshold, 0 means the lag alarm is disabled
+func (s *Sync) GetLagThreshold() time.Duration {
+	if s == nil || len(s.LagThreshold) == 0 {
+		return defaultLagThreshold
+	}
+	d, err := time.ParseDuration(s.LagThreshold)
+	if err != nil {
+		log.Error(fmt.Sprintf("invalid lag threshold %s, use default %s", s.LagThreshold, defaultLagThreshold), err)
+		return defaultLagThreshold
+	}
+	return d
+}
+
+const defaultReloadInterval = time.Minute
+
+// TLS is the certificates to communicate with the peers, the files are reloaded
+// once changed, so that the certificates can be rotated without restarting
+type TLS struct {
+	Enabled  bool   `yaml:"enabled"`
+	CAFile   string `yaml:"caFile"`
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+	// ReloadInterval is the interval to check whether the files changed, default is 1m
+	ReloadInterval string `yaml:"reloadInterval"`
+}
+
+// IsTLSEnabled returns true if the mutual TLS is enabled
+func (s *Sync) IsTLSEnabled() bool {
+	return s != nil && s.TLS != nil && s.TLS.Enabled
+}
+
+// GetReloadInterval returns the interval to check whether the certificate files changed
+func (t *TLS) GetReloadInterval() time.Duration {
+	if t == nil || len(t.ReloadInterval) == 0 {
+		return defaultReloadInterval
+	}
+	d, err := time.ParseDuration(t.ReloadInterval)
+	if err != nil {
+		log.Error(fmt.Sprintf("invalid reload interval %s, use default %s", t.ReloadInterval, defaultReloadInterval), err)
+		return defaultReloadInterval
+	}
+	return d
+}
+
+const (
+	// PolicyLastWriterWins keeps the resource which updated last by timestamp
+	PolicyLastWriterWins = "last-writer-wins"
+	// PolicyOriginWins applies the change from the peer even if the local resource is newer,
+	// it is used on the sites which replicate resources from a single writable origin
+	PolicyOriginWins = "origin-wins"
+	// PolicyRejectAndAlarm rejects the change from the peer and keeps the local resource,
+	// the peer is answered with a conflict result
+	PolicyRejectAndAlarm = "reject-and-alarm"
+)
+
+type Conflict struct {
+	// Policy is the default policy, last-writer-wins if it is empty
+	Policy string `yaml:"policy"`
+	// Resources are the policies of the resource types, such as service, role
+	Resources map[string]string `yaml:"resources"`
+}
+
+// ConflictPolicy returns the conflict policy of the resource type
+func (s *Sync) ConflictPolicy(resourceType string) string {
+	if s == nil || s.Conflict == nil {
+		return PolicyLastWriterWins
+	}
+	if policy, ok := s.Conflict.Resources[resourceType]; ok && len(policy) > 0 {
+		return policy
+	}
+	if len(s.Conflict.Policy) > 0 {
+		return s.Conflict.Policy
+	}
+	return PolicyLastWriterWins
+}
+
+const (
+	// ModePush means the local events are pushed to the peer
+	ModePush = "push"
+	// ModeWatch means the events are delivered by watching, if the peer has endpoints,
+	// local subscribes the events of the peer, otherwise the peer subscribes the local events
+	ModeWatch = "watch"
+)
+
 type Peer struct {
 	Name      string   `yaml:"name"`
 	Kind      string   `yaml:"kind"`
 	Endpoints []string `yaml:"endpoints"`
 	Mode      []string `yaml:"mode"`
-	// The token to communicate with peer, this takes effect only when RbacEnabled is true
+	// The token to communicate with peer, this takes effect only when RbacEnabled is true.
+	// For a watch mode peer without endpoints, it is the token the peer presents to watch,
+	// which identifies the peer if the sync TLS is disabled
 	Token string `yaml:"token"`
+	// Include and Exclude filter the resources replicated to the peer, a resource is replicated
+	// if it matches any of the include rules or there is no include rule, and it matches none
+	// of the exclude rules
+	Include []*Rule `yaml:"include"`
+	Exclude []*Rule `yaml:"exclude"`
+	// Compression is the compressors in the order of preference, such as zstd and gzip, the one
+	// supported by the peer is negotiated to send events, they are sent without compression if none
+	Compression []string `yaml:"compression"`
+	// TLS overrides the certificates of the sync TLS for the peer, it takes effect only when
+	// the sync TLS is enabled
+	TLS *PeerTLS `yaml:"tls"`
+}
+
+// PeerTLS is the certificates and identity of the peer
+type PeerTLS struct {
+	// CAFile verifies the certificate of the peer, the sync CA file is used if empty
+	CAFile string `yaml:"caFile"`
+	// CertFile and KeyFile are the client certificate presented to the peer,
+	// the sync certificate is used if empty
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+	// ServerName verifies the hostname of the peer certificate if not empty
+	ServerName string `yaml:"serverName"`
+	// SANs are the identities of the peer, the certificate of the peer must contain one of them
+	// as DNS name, IP address, URI or email address, both when local dials the peer and the peer
+	// dials local. It is required if the sync TLS is enabled, the syncer server fails to start otherwise
+	SANs []string `yaml:"sans"`
+}
+
+// Rule matches the resources, the conditions are ANDed and the values of a condition are ORed,
+// an empty condition matches all the resources
+type Rule struct {
+	Domains       []string `yaml:"domains"`
+	Projects      []string `yaml:"projects"`
+	AppIDs        []string `yaml:"appIds"`
+	Environments  []string `yaml:"environments"`
+	ResourceTypes []string `yaml:"resourceTypes"`
+}
+
+// GetPeer returns the configuration of the peer
+func (s *Sync) GetPeer(name string) (*Peer, bool) {
+	if s == nil {
+		return nil, false
+	}
+	for _, p := range s.Peers {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// HasMode returns true if the peer enables the mode, push is the default mode
+func (p *Peer) HasMode(mode string) bool {
+	if len(p.Mode) == 0 {
+		return mode == ModePush
+	}
+	for _, m := range p.Mode {
+		if m == mode {
+			return true
+		}
+	}
+	return false
 }
 
 func Init() error {
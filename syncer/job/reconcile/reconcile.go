@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/syncer/service/reconcile"
+	"github.com/apache/servicecomb-service-center/syncer/service/replicator"
+	"github.com/go-chassis/cari/dlock"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	defaultReconcileCron = "*/10 * * * *" // once every 10 minutes
+	reconcileLockTTL     = 600
+	reconcileLockKey     = "reconcile-peers-job"
+)
+
+func init() {
+	cronStr := config.GetString("sync.reconcile.cron", "")
+	if len(cronStr) <= 0 {
+		cronStr = defaultReconcileCron
+	}
+
+	log.Info(fmt.Sprintf("start syncer reconcile job, plan is %v", cronStr))
+	c := cron.New()
+	_, err := c.AddFunc(cronStr, func() {
+		reconcilePeers()
+	})
+	if err != nil {
+		log.Error("cron add func failed", err)
+		return
+	}
+	c.Start()
+}
+
+func reconcilePeers() {
+	peers := replicator.DialedPeerNames()
+	if len(peers) == 0 {
+		return
+	}
+
+	err := dlock.TryLock(reconcileLockKey, reconcileLockTTL)
+	if err != nil {
+		log.Error(fmt.Sprintf("try lock %s failed", reconcileLockKey), err)
+		return
+	}
+	defer func() {
+		if err := dlock.Unlock(reconcileLockKey); err != nil {
+			log.Error("unlock failed", err)
+		}
+	}()
+
+	for _, peer := range peers {
+		log.Info(fmt.Sprintf("start reconcile with peer %s", peer))
+		err = reconcile.Reconcile(context.Background(), peer)
+		if err != nil {
+			log.Error(fmt.Sprintf("reconcile with peer %s failed", peer), err)
+		}
+	}
+}
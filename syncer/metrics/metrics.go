@@ -19,6 +19,7 @@ package metrics
 
 import (
 	"net"
+	"sync"
 	"time"
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
@@ -35,9 +36,38 @@ const (
 	KeyConnectedPeers = FamilyName + "_connected_peers"
 	KeyPeersTotal     = FamilyName + "_peers_total"
 	KeyPeersClockDiff = FamilyName + "_peers_clock_diff"
+
+	KeyPeerPendingEvent   = FamilyName + "_peer_pending_event"
+	KeyPeerAbandonEvent   = FamilyName + "_peer_abandon_event"
+	KeyPeerPendingTask    = FamilyName + "_peer_pending_task"
+	KeyOldestPendingTask  = FamilyName + "_oldest_pending_task_seconds"
+	KeySentEvent          = FamilyName + "_sent_event"
+	KeyFailedEvent        = FamilyName + "_failed_event"
+	KeyReplicationLatency = FamilyName + "_replication_latency_seconds"
+)
+
+var (
+	Instance string
+
+	// enabled is false if metrics are disabled, the metrics reported
+	// per event are skipped then, rather than logging errors
+	enabled bool
+
+	pendingLock sync.Mutex
+	// pendingKeys are the label sets of the peer pending tasks reported last time,
+	// they are reset to 0 if no task pending anymore
+	pendingKeys = make(map[PendingKey]struct{})
+	oldestPeers = make(map[string]struct{})
+	// pendingEvents are the numbers of the events pending to send to each peer
+	pendingEvents = make(map[string]int64)
 )
 
-var Instance string
+// PendingKey is the labels of the peer pending tasks
+type PendingKey struct {
+	Peer   string
+	Type   string
+	Action string
+}
 
 func Init() error {
 	if !config.GetBool("metrics.enable", false) {
@@ -66,6 +96,22 @@ func Init() error {
 		return err
 	}
 
+	if err := metrics.CreateGauge(metrics.GaugeOpts{
+		Key:    KeyPeerPendingEvent,
+		Help:   "The number of events pending to send to the peer",
+		Labels: []string{"instance", "peer"},
+	}); err != nil {
+		return err
+	}
+
+	if err := metrics.CreateCounter(metrics.CounterOpts{
+		Key:    KeyPeerAbandonEvent,
+		Help:   "The number of events abandoned to send to the peer",
+		Labels: []string{"instance", "peer", "type", "action"},
+	}); err != nil {
+		return err
+	}
+
 	if err := metrics.CreateGauge(metrics.GaugeOpts{
 		Key:    KeyPendingTask,
 		Help:   "The number of tasks pending to dispatch",
@@ -97,10 +143,56 @@ func Init() error {
 	}); err != nil {
 		return err
 	}
+
+	if err := metrics.CreateGauge(metrics.GaugeOpts{
+		Key:    KeyPeerPendingTask,
+		Help:   "The number of tasks pending to replicate to the peer",
+		Labels: []string{"instance", "peer", "type", "action"},
+	}); err != nil {
+		return err
+	}
+
+	if err := metrics.CreateGauge(metrics.GaugeOpts{
+		Key:    KeyOldestPendingTask,
+		Help:   "The age seconds of the oldest task pending to replicate to the peer",
+		Labels: []string{"instance", "peer"},
+	}); err != nil {
+		return err
+	}
+
+	if err := metrics.CreateCounter(metrics.CounterOpts{
+		Key:    KeySentEvent,
+		Help:   "The number of events replicated to the peer",
+		Labels: []string{"instance", "peer", "type", "action"},
+	}); err != nil {
+		return err
+	}
+
+	if err := metrics.CreateCounter(metrics.CounterOpts{
+		Key:    KeyFailedEvent,
+		Help:   "The number of events failed to replicate to the peer",
+		Labels: []string{"instance", "peer", "type", "action"},
+	}); err != nil {
+		return err
+	}
+
+	if err := metrics.CreateHistogram(metrics.HistogramOpts{
+		Key:     KeyReplicationLatency,
+		Help:    "The seconds from the event happened to it replicated to the peer",
+		Labels:  []string{"instance", "peer", "type"},
+		Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+	}); err != nil {
+		return err
+	}
+
+	enabled = true
 	return nil
 }
 
 func PendingEventSet(n int64) {
+	if !enabled {
+		return
+	}
 	labels := map[string]string{"instance": Instance}
 	if err := metrics.GaugeSet(KeyPendingEvent, float64(n), labels); err != nil {
 		log.Error("gauge set failed", err)
@@ -108,12 +200,48 @@ func PendingEventSet(n int64) {
 }
 
 func AbandonEventAdd() {
+	if !enabled {
+		return
+	}
 	labels := map[string]string{"instance": Instance}
 	if err := metrics.CounterAdd(KeyAbandonEvent, 1, labels); err != nil {
 		log.Error("counter add failed", err)
 	}
 }
 
+// PeerPendingEventSet sets the number of the events pending to send to the peer,
+// and the total of all the peers as the pending events
+func PeerPendingEventSet(peer string, n int64) {
+	if !enabled {
+		return
+	}
+	pendingLock.Lock()
+	pendingEvents[peer] = n
+	var total int64
+	for _, c := range pendingEvents {
+		total += c
+	}
+	pendingLock.Unlock()
+
+	labels := map[string]string{"instance": Instance, "peer": peer}
+	if err := metrics.GaugeSet(KeyPeerPendingEvent, float64(n), labels); err != nil {
+		log.Error("gauge set failed", err)
+	}
+	PendingEventSet(total)
+}
+
+// PeerAbandonEventAdd counts the event abandoned to send to the peer, also as an abandon event
+func PeerAbandonEventAdd(peer, resourceType, action string) {
+	if !enabled {
+		return
+	}
+	labels := map[string]string{"instance": Instance, "peer": peer, "type": resourceType, "action": action}
+	if err := metrics.CounterAdd(KeyPeerAbandonEvent, 1, labels); err != nil {
+		log.Error("counter add failed", err)
+	}
+	AbandonEventAdd()
+}
+
 func PendingTaskSet(n int64) {
 	labels := map[string]string{"instance": Instance}
 	if err := metrics.GaugeSet(KeyPendingTask, float64(n), labels); err != nil {
@@ -141,3 +269,90 @@ func PeersClockDiffSet(peerName string, n int64) {
 		log.Error("gauge set failed", err)
 	}
 }
+
+// PeerPendingTaskSet sets the number of the pending tasks of each peer, resource type and action
+func PeerPendingTaskSet(pending map[PendingKey]int64) {
+	if !enabled {
+		return
+	}
+	pendingLock.Lock()
+	defer pendingLock.Unlock()
+	values := make(map[PendingKey]int64, len(pending)+len(pendingKeys))
+	for k := range pendingKeys {
+		values[k] = 0
+	}
+	for k, n := range pending {
+		values[k] = n
+	}
+	pendingKeys = make(map[PendingKey]struct{}, len(pending))
+	for k, n := range values {
+		labels := map[string]string{"instance": Instance, "peer": k.Peer, "type": k.Type, "action": k.Action}
+		if err := metrics.GaugeSet(KeyPeerPendingTask, float64(n), labels); err != nil {
+			log.Error("gauge set failed", err)
+		}
+		if n > 0 {
+			pendingKeys[k] = struct{}{}
+		}
+	}
+}
+
+// OldestPendingTaskSet sets the age of the oldest pending task of each peer
+func OldestPendingTaskSet(ages map[string]time.Duration) {
+	if !enabled {
+		return
+	}
+	pendingLock.Lock()
+	defer pendingLock.Unlock()
+	values := make(map[string]time.Duration, len(ages)+len(oldestPeers))
+	for peer := range oldestPeers {
+		values[peer] = 0
+	}
+	for peer, age := range ages {
+		values[peer] = age
+	}
+	oldestPeers = make(map[string]struct{}, len(ages))
+	for peer, age := range values {
+		labels := map[string]string{"instance": Instance, "peer": peer}
+		if err := metrics.GaugeSet(KeyOldestPendingTask, age.Seconds(), labels); err != nil {
+			log.Error("gauge set failed", err)
+		}
+		if age > 0 {
+			oldestPeers[peer] = struct{}{}
+		}
+	}
+}
+
+func SentEventAdd(peer, resourceType, action string) {
+	if !enabled {
+		return
+	}
+	labels := map[string]string{"instance": Instance, "peer": peer, "type": resourceType, "action": action}
+	if err := metrics.CounterAdd(KeySentEvent, 1, labels); err != nil {
+		log.Error("counter add failed", err)
+	}
+}
+
+func FailedEventAdd(peer, resourceType, action string) {
+	if !enabled {
+		return
+	}
+	labels := map[string]string{"instance": Instance, "peer": peer, "type": resourceType, "action": action}
+	if err := metrics.CounterAdd(KeyFailedEvent, 1, labels); err != nil {
+		log.Error("counter add failed", err)
+	}
+}
+
+// ReplicationLatencyObserve observes the latency from the event timestamp(nanoseconds) to now
+func ReplicationLatencyObserve(peer, resourceType string, timestamp int64) {
+	if !enabled || timestamp <= 0 {
+		return
+	}
+	latency := time.Since(time.Unix(0, timestamp)).Seconds()
+	if latency < 0 {
+		latency = 0
+	}
+	labels := map[string]string{"instance": Instance, "peer": peer, "type": resourceType}
+	if err := metrics.HistogramObserve(KeyReplicationLatency, latency, labels); err != nil {
+		log.Error("histogram observe failed", err)
+	}
+}
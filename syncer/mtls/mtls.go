@@ -0,0 +1,221 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package mtls builds the TLS configurations to authenticate the syncer peers
+// by certificates, the certificates are reloaded once the files changed
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/apache/servicecomb-service-center/syncer/config"
+)
+
+var (
+	ErrTLSDisabled     = errors.New("sync tls is disabled")
+	ErrNoPeerCert      = errors.New("peer presents no certificate")
+	ErrSANNotMatched   = errors.New("peer certificate matches none of the sans")
+	ErrSANMissing      = errors.New("peer declares no sans")
+	ErrCertFileMissing = errors.New("cert file and key file are required")
+	ErrCAFileMissing   = errors.New("ca file is required")
+)
+
+// ServerConfig returns the configuration of the syncer server, it requires the peers to present
+// certificates issued by the CAs of the sync and the peers, and the certificate must match the
+// SANs of one of the peers, so every peer must declare the SANs
+func ServerConfig(s *config.Sync) (*tls.Config, error) {
+	if !s.IsTLSEnabled() {
+		return nil, ErrTLSDisabled
+	}
+	if len(s.TLS.CertFile) == 0 || len(s.TLS.KeyFile) == 0 {
+		return nil, ErrCertFileMissing
+	}
+
+	caFiles := make([]string, 0, len(s.Peers)+1)
+	caFiles = appendFile(caFiles, s.TLS.CAFile)
+	peerSANs := make([][]string, 0, len(s.Peers))
+	for _, p := range s.Peers {
+		if p.TLS == nil || len(p.TLS.SANs) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrSANMissing, p.Name)
+		}
+		caFiles = appendFile(caFiles, p.TLS.CAFile)
+		peerSANs = append(peerSANs, p.TLS.SANs)
+	}
+
+	if len(caFiles) == 0 {
+		return nil, ErrCAFileMissing
+	}
+
+	st, err := newStore(caFiles, s.TLS.CertFile, s.TLS.KeyFile, s.TLS.GetReloadInterval())
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			pool, cert := st.get()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientCAs:    pool,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				NextProtos:   []string{"h2"},
+				VerifyConnection: func(cs tls.ConnectionState) error {
+					if len(cs.PeerCertificates) == 0 {
+						return ErrNoPeerCert
+					}
+					return matchPeer(cs.PeerCertificates[0], peerSANs)
+				},
+			}, nil
+		},
+	}, nil
+}
+
+// VerifyPeer checks the certificate the caller presented in the handshake against the SANs
+// of the peer it claims to be, the server must serve with the transport credentials of ServerConfig
+func VerifyPeer(ctx context.Context, p *config.Peer) error {
+	pr, ok := peer.FromContext(ctx)
+	if !ok {
+		return ErrNoPeerCert
+	}
+	info, ok := pr.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.PeerCertificates) == 0 {
+		return ErrNoPeerCert
+	}
+	if p.TLS == nil || len(p.TLS.SANs) == 0 {
+		return fmt.Errorf("%w: %s", ErrSANMissing, p.Name)
+	}
+	return matchSAN(info.State.PeerCertificates[0], p.TLS.SANs)
+}
+
+// ClientConfig returns the configuration to dial the peer, the certificate of the peer is
+// verified by the current CA pool in every handshake, so that the CA can be rotated
+func ClientConfig(s *config.Sync, p *config.Peer) (*tls.Config, error) {
+	if !s.IsTLSEnabled() {
+		return nil, ErrTLSDisabled
+	}
+
+	peerTLS := p.TLS
+	if peerTLS == nil {
+		peerTLS = &config.PeerTLS{}
+	}
+	caFile, certFile, keyFile := s.TLS.CAFile, s.TLS.CertFile, s.TLS.KeyFile
+	if len(peerTLS.CAFile) > 0 {
+		caFile = peerTLS.CAFile
+	}
+	if len(peerTLS.CertFile) > 0 {
+		certFile, keyFile = peerTLS.CertFile, peerTLS.KeyFile
+	}
+	if len(certFile) == 0 || len(keyFile) == 0 {
+		return nil, ErrCertFileMissing
+	}
+	if len(caFile) == 0 {
+		return nil, ErrCAFileMissing
+	}
+
+	st, err := newStore(appendFile(nil, caFile), certFile, keyFile, s.TLS.GetReloadInterval())
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// the chain is verified in VerifyConnection by the current CA pool
+		InsecureSkipVerify: true, //nolint:gosec
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			_, cert := st.get()
+			return cert, nil
+		},
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			pool, _ := st.get()
+			if err := verify(cs, pool, peerTLS.ServerName); err != nil {
+				return err
+			}
+			return matchSAN(cs.PeerCertificates[0], peerTLS.SANs)
+		},
+	}, nil
+}
+
+func appendFile(files []string, file string) []string {
+	if len(file) == 0 {
+		return files
+	}
+	for _, f := range files {
+		if f == file {
+			return files
+		}
+	}
+	return append(files, file)
+}
+
+func verify(cs tls.ConnectionState, pool *x509.CertPool, serverName string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return ErrNoPeerCert
+	}
+	opts := x509.VerifyOptions{
+		Roots:         pool,
+		DNSName:       serverName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// matchPeer returns nil if the certificate matches the SANs of any peer
+func matchPeer(cert *x509.Certificate, peerSANs [][]string) error {
+	for _, sans := range peerSANs {
+		if matchSAN(cert, sans) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w, subject: %s", ErrSANNotMatched, cert.Subject)
+}
+
+// matchSAN returns nil if the certificate contains any of the sans, or the sans is empty
+func matchSAN(cert *x509.Certificate, sans []string) error {
+	if len(sans) == 0 {
+		return nil
+	}
+	names := make(map[string]struct{})
+	for _, name := range cert.DNSNames {
+		names[name] = struct{}{}
+	}
+	for _, ip := range cert.IPAddresses {
+		names[ip.String()] = struct{}{}
+	}
+	for _, uri := range cert.URIs {
+		names[uri.String()] = struct{}{}
+	}
+	for _, email := range cert.EmailAddresses {
+		names[email] = struct{}{}
+	}
+	for _, san := range sans {
+		if _, ok := names[san]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w, subject: %s", ErrSANNotMatched, cert.Subject)
+}
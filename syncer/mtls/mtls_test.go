@@ -0,0 +1,269 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/apache/servicecomb-service-center/syncer/config"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) writeCA(t *testing.T, file string) {
+	writePEM(t, file, "CERTIFICATE", ca.cert.Raw)
+}
+
+// issue writes the certificate with the dns name and the key to files
+func (ca *testCA) issue(t *testing.T, dir, name string, serial int64) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDer)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, file, typ string, der []byte) {
+	assert.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600))
+}
+
+func handshake(serverConfig, clientConfig *tls.Config) (*tls.ConnectionState, error) {
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer lis.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer conn.Close()
+		errs <- conn.(*tls.Conn).Handshake()
+	}()
+
+	client, err := tls.Dial("tcp", lis.Addr().String(), clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	state := client.ConnectionState()
+	return &state, nil
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caFile := filepath.Join(dir, "ca.crt")
+	ca.writeCA(t, caFile)
+	serverCert, serverKey := ca.issue(t, dir, "syncer-a", 2)
+	clientCert, clientKey := ca.issue(t, dir, "syncer-b", 3)
+	otherCert, otherKey := ca.issue(t, dir, "syncer-c", 4)
+
+	serverSync := &config.Sync{
+		TLS: &config.TLS{Enabled: true, CAFile: caFile, CertFile: serverCert, KeyFile: serverKey},
+		Peers: []*config.Peer{
+			{Name: "b", TLS: &config.PeerTLS{SANs: []string{"syncer-b"}}},
+		},
+	}
+	serverConfig, err := ServerConfig(serverSync)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	clientSync := &config.Sync{
+		TLS: &config.TLS{Enabled: true, CAFile: caFile, CertFile: clientCert, KeyFile: clientKey},
+	}
+
+	t.Run("peer identity matched, should pass", func(t *testing.T) {
+		clientConfig, err := ClientConfig(clientSync, &config.Peer{
+			Name: "a", TLS: &config.PeerTLS{ServerName: "syncer-a", SANs: []string{"syncer-a"}},
+		})
+		assert.NoError(t, err)
+		state, err := handshake(serverConfig, clientConfig)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "syncer-a", state.PeerCertificates[0].Subject.CommonName)
+		}
+	})
+
+	t.Run("server sans not matched, should fail", func(t *testing.T) {
+		clientConfig, err := ClientConfig(clientSync, &config.Peer{
+			Name: "a", TLS: &config.PeerTLS{SANs: []string{"syncer-x"}},
+		})
+		assert.NoError(t, err)
+		_, err = handshake(serverConfig, clientConfig)
+		assert.Error(t, err)
+	})
+
+	t.Run("client sans not matched, should fail", func(t *testing.T) {
+		clientConfig, err := ClientConfig(clientSync, &config.Peer{
+			Name: "a", TLS: &config.PeerTLS{CertFile: otherCert, KeyFile: otherKey},
+		})
+		assert.NoError(t, err)
+		_, err = handshake(serverConfig, clientConfig)
+		assert.Error(t, err)
+	})
+
+	t.Run("untrusted ca, should fail", func(t *testing.T) {
+		untrusted := newTestCA(t)
+		untrustedFile := filepath.Join(dir, "untrusted.crt")
+		untrusted.writeCA(t, untrustedFile)
+		clientConfig, err := ClientConfig(clientSync, &config.Peer{
+			Name: "a", TLS: &config.PeerTLS{CAFile: untrustedFile},
+		})
+		assert.NoError(t, err)
+		_, err = handshake(serverConfig, clientConfig)
+		assert.Error(t, err)
+	})
+}
+
+func TestConfigError(t *testing.T) {
+	_, err := ServerConfig(&config.Sync{})
+	assert.ErrorIs(t, err, ErrTLSDisabled)
+	_, err = ServerConfig(&config.Sync{TLS: &config.TLS{Enabled: true}})
+	assert.ErrorIs(t, err, ErrCertFileMissing)
+	_, err = ClientConfig(&config.Sync{TLS: &config.TLS{Enabled: true, CertFile: "a", KeyFile: "b"}}, &config.Peer{})
+	assert.ErrorIs(t, err, ErrCAFileMissing)
+	_, err = ServerConfig(&config.Sync{
+		TLS: &config.TLS{Enabled: true, CertFile: "a", KeyFile: "b"},
+		Peers: []*config.Peer{
+			{Name: "b", TLS: &config.PeerTLS{SANs: []string{"syncer-b"}}},
+			{Name: "c"},
+		},
+	})
+	assert.ErrorIs(t, err, ErrSANMissing)
+}
+
+func TestVerifyPeer(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{DNSNames: []string{"syncer-b"}}},
+		}},
+	})
+
+	t.Run("the claimed peer matched, should pass", func(t *testing.T) {
+		assert.NoError(t, VerifyPeer(ctx, &config.Peer{Name: "b", TLS: &config.PeerTLS{SANs: []string{"syncer-b"}}}))
+	})
+	t.Run("claim to be another peer, should fail", func(t *testing.T) {
+		err := VerifyPeer(ctx, &config.Peer{Name: "c", TLS: &config.PeerTLS{SANs: []string{"syncer-c"}}})
+		assert.ErrorIs(t, err, ErrSANNotMatched)
+	})
+	t.Run("the claimed peer declares no sans, should fail", func(t *testing.T) {
+		assert.ErrorIs(t, VerifyPeer(ctx, &config.Peer{Name: "c"}), ErrSANMissing)
+	})
+	t.Run("no certificate, should fail", func(t *testing.T) {
+		err := VerifyPeer(context.Background(), &config.Peer{Name: "b", TLS: &config.PeerTLS{SANs: []string{"syncer-b"}}})
+		assert.ErrorIs(t, err, ErrNoPeerCert)
+	})
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caFile := filepath.Join(dir, "ca.crt")
+	ca.writeCA(t, caFile)
+	certFile, keyFile := ca.issue(t, dir, "syncer-a", 2)
+
+	st, err := newStore([]string{caFile}, certFile, keyFile, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, cert := st.get()
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), leaf.SerialNumber.Int64())
+
+	// rotate the certificate
+	ca.issue(t, dir, "syncer-a", 5)
+	future := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(certFile, future, future))
+	assert.NoError(t, os.Chtimes(keyFile, future, future))
+	_, cert = st.get()
+	leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), leaf.SerialNumber.Int64())
+
+	// broken files, keep the previous certificate
+	assert.NoError(t, os.WriteFile(keyFile, []byte("broken"), 0600))
+	future = future.Add(time.Minute)
+	assert.NoError(t, os.Chtimes(keyFile, future, future))
+	_, cert = st.get()
+	assert.NotNil(t, cert)
+	leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), leaf.SerialNumber.Int64())
+}
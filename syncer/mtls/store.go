@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+var ErrNoCertificate = errors.New("no certificate found")
+
+// store holds the CA pool and the certificate loaded from files, it reloads
+// them if the files changed since the last check
+type store struct {
+	caFiles  []string
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	lock      sync.Mutex
+	checkTime time.Time
+	modTimes  map[string]time.Time
+	pool      *x509.CertPool
+	cert      *tls.Certificate
+}
+
+func newStore(caFiles []string, certFile, keyFile string, interval time.Duration) (*store, error) {
+	s := &store{
+		caFiles:  caFiles,
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: interval,
+	}
+	modTimes, err := s.statFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.load(modTimes); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *store) files() []string {
+	files := append([]string{}, s.caFiles...)
+	if len(s.certFile) > 0 {
+		files = append(files, s.certFile, s.keyFile)
+	}
+	return files
+}
+
+func (s *store) statFiles() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	for _, file := range s.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[file] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func (s *store) load(modTimes map[string]time.Time) error {
+	pool := x509.NewCertPool()
+	for _, file := range s.caFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if !pool.AppendCertsFromPEM(content) {
+			return fmt.Errorf("%w in ca file %s", ErrNoCertificate, file)
+		}
+	}
+
+	var cert *tls.Certificate
+	if len(s.certFile) > 0 {
+		c, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+		if err != nil {
+			return err
+		}
+		cert = &c
+	}
+
+	s.pool, s.cert, s.modTimes = pool, cert, modTimes
+	s.checkTime = time.Now()
+	return nil
+}
+
+func (s *store) changed(modTimes map[string]time.Time) bool {
+	for file, t := range modTimes {
+		if !t.Equal(s.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// get returns the current CA pool and certificate, the files are checked at most
+// once in the interval, the previous ones are kept if reloading failed
+func (s *store) get() (*x509.CertPool, *tls.Certificate) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if time.Since(s.checkTime) < s.interval {
+		return s.pool, s.cert
+	}
+	s.checkTime = time.Now()
+
+	modTimes, err := s.statFiles()
+	if err != nil {
+		log.Error("check certificate files failed", err)
+		return s.pool, s.cert
+	}
+	if !s.changed(modTimes) {
+		return s.pool, s.cert
+	}
+	if err := s.load(modTimes); err != nil {
+		log.Error("reload certificates failed, keep the previous ones", err)
+		return s.pool, s.cert
+	}
+	log.Info(fmt.Sprintf("certificates reloaded, files: %v", s.files()))
+	return s.pool, s.cert
+}
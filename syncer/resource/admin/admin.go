@@ -18,17 +18,37 @@
 package admin
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/apache/servicecomb-service-center/eventbase/model"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/syncer/service/admin"
+	"github.com/apache/servicecomb-service-center/syncer/service/task"
 	"github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/rbac"
 )
 
 const (
-	APIHealth = "/v1/syncer/health"
+	APIHealth    = "/v1/syncer/health"
+	APIConflicts = "/v1/syncer/conflicts"
+	APITasks     = "/v1/syncer/tasks"
+	APITask      = "/v1/syncer/tasks/:id"
+	APITaskRetry = "/v1/syncer/tasks/:id/retry"
+	APITombstone = "/v1/syncer/tombstones"
+)
+
+var (
+	// ErrTaskNotExist is the code of the task not found
+	ErrTaskNotExist int32 = 404001
+	// ErrTaskStateConflict is the code of the task can not be retried in its state, e.g. not handled yet
+	ErrTaskStateConflict int32 = 409001
+	// ErrTaskManagerUnavailable is the code of the task manager not running on this node,
+	// it runs only on the node holding the distributed lock
+	ErrTaskManagerUnavailable int32 = 503001
 )
 
 func init() {
@@ -42,6 +62,13 @@ type Resource struct {
 func (res *Resource) URLPatterns() []rest.Route {
 	return []rest.Route{
 		{Method: http.MethodGet, Path: APIHealth, Func: res.HealthCheck},
+		{Method: http.MethodGet, Path: APIConflicts, Func: res.ListConflicts},
+		{Method: http.MethodDelete, Path: APIConflicts, Func: res.ClearConflicts},
+		{Method: http.MethodGet, Path: APITasks, Func: res.ListTasks},
+		{Method: http.MethodPost, Path: APITaskRetry, Func: res.RetryTask},
+		{Method: http.MethodDelete, Path: APITask, Func: res.DiscardTask},
+		{Method: http.MethodGet, Path: APITombstone, Func: res.ListTombstones},
+		{Method: http.MethodDelete, Path: APITombstone, Func: res.PurgeTombstones},
 	}
 }
 
@@ -54,3 +81,121 @@ func (res *Resource) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	}
 	rest.WriteResponse(w, r, nil, healthResp)
 }
+
+func (res *Resource) ListConflicts(w http.ResponseWriter, r *http.Request) {
+	rest.WriteResponse(w, r, nil, admin.Conflicts())
+}
+
+func (res *Resource) ClearConflicts(w http.ResponseWriter, r *http.Request) {
+	err := admin.ClearConflicts()
+	if err != nil {
+		log.Error("clear conflicts failed", err)
+		rest.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	rest.WriteResponse(w, r, nil, nil)
+}
+
+func (res *Resource) ListTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	resp, err := admin.ListTasks(r.Context(), &model.ListTaskRequest{
+		Domain:       query.Get("domain"),
+		Project:      query.Get("project"),
+		Action:       query.Get("action"),
+		Status:       query.Get("status"),
+		ResourceType: query.Get("type"),
+	})
+	if err != nil {
+		log.Error("list tasks failed", err)
+		rest.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	rest.WriteResponse(w, r, nil, resp)
+}
+
+func (res *Resource) RetryTask(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get(":id")
+	err := admin.RetryTask(id)
+	if err != nil {
+		log.Error(fmt.Sprintf("retry task %s failed", id), err)
+		writeTaskError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, nil)
+}
+
+func (res *Resource) DiscardTask(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get(":id")
+	err := admin.DiscardTask(r.Context(), id)
+	if err != nil {
+		log.Error(fmt.Sprintf("discard task %s failed", id), err)
+		writeTaskError(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, nil)
+}
+
+func writeTaskError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, task.ErrTaskNotFound):
+		rest.WriteError(w, ErrTaskNotExist, err.Error())
+	case errors.Is(err, task.ErrManagerNotRunning):
+		rest.WriteError(w, ErrTaskManagerUnavailable,
+			"this node does not hold the lock of the task manager, retry on the node holding it")
+	case errors.Is(err, task.ErrTaskNotHandled):
+		rest.WriteError(w, ErrTaskStateConflict, err.Error())
+	default:
+		rest.WriteError(w, discovery.ErrInternal, err.Error())
+	}
+}
+
+func (res *Resource) ListTombstones(w http.ResponseWriter, r *http.Request) {
+	req, err := toListTombstoneRequest(r)
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, err := admin.ListTombstones(r.Context(), req)
+	if err != nil {
+		log.Error("list tombstones failed", err)
+		rest.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	rest.WriteResponse(w, r, nil, resp)
+}
+
+func (res *Resource) PurgeTombstones(w http.ResponseWriter, r *http.Request) {
+	req, err := toListTombstoneRequest(r)
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	count, err := admin.PurgeTombstones(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, admin.ErrPurgeConditionEmpty) {
+			rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		log.Error("purge tombstones failed", err)
+		rest.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	rest.WriteResponse(w, r, nil, map[string]int{"total": count})
+}
+
+func toListTombstoneRequest(r *http.Request) (*model.ListTombstoneRequest, error) {
+	query := r.URL.Query()
+	req := &model.ListTombstoneRequest{
+		Domain:       query.Get("domain"),
+		Project:      query.Get("project"),
+		ResourceType: query.Get("type"),
+	}
+	if before := query.Get("before"); len(before) > 0 {
+		timestamp, err := strconv.ParseInt(before, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid before timestamp %s", before)
+		}
+		req.BeforeTimestamp = timestamp
+	}
+	return req, nil
+}
@@ -2,6 +2,8 @@ package rpc
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -11,10 +13,15 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/plugin/security/cipher"
 	"github.com/apache/servicecomb-service-center/syncer/config"
+	"github.com/apache/servicecomb-service-center/syncer/mtls"
 )
 
-var errWrongAccountNorRole = fmt.Errorf("account should be %s, and roles should contain %s", RbacAllowedAccountName, RbacAllowedRoleName)
+var (
+	errWrongAccountNorRole = fmt.Errorf("account should be %s, and roles should contain %s", RbacAllowedAccountName, RbacAllowedRoleName)
+	errPeerNotAllowed      = errors.New("peer not allowed")
+)
 
 func auth(ctx context.Context) error {
 	if !config.GetConfig().Sync.RbacEnabled {
@@ -25,16 +32,10 @@ func auth(ctx context.Context) error {
 		return rbac.NewError(rbac.ErrNoAuthHeader, "")
 	}
 
-	authHeader := md.Get(restful.HeaderAuth)
-	if len(authHeader) == 0 {
-		return rbac.NewError(rbac.ErrNoAuthHeader, fmt.Sprintf("header %s not found nor content empty", restful.HeaderAuth))
-	}
-
-	s := strings.Split(authHeader[0], " ")
-	if len(s) != 2 {
-		return rbac.ErrInvalidHeader
+	to, err := bearerToken(md)
+	if err != nil {
+		return err
 	}
-	to := s[1]
 
 	claims, err := authr.Authenticate(ctx, to)
 	if err != nil {
@@ -61,3 +62,60 @@ func auth(ctx context.Context) error {
 	}
 	return errWrongAccountNorRole
 }
+
+func bearerToken(md metadata.MD) (string, error) {
+	authHeader := md.Get(restful.HeaderAuth)
+	if len(authHeader) == 0 {
+		return "", rbac.NewError(rbac.ErrNoAuthHeader, fmt.Sprintf("header %s not found nor content empty", restful.HeaderAuth))
+	}
+
+	s := strings.Split(authHeader[0], " ")
+	if len(s) != 2 {
+		return "", rbac.ErrInvalidHeader
+	}
+	return s[1], nil
+}
+
+// authPeer checks the caller is the peer it claims to be, by the certificate if the sync tls
+// is enabled, otherwise by the token configured for the peer if rbac enabled, so that a syncer
+// can not watch the events on behalf of another peer
+func authPeer(ctx context.Context, name string) error {
+	cfg := config.GetConfig().Sync
+	var peer *config.Peer
+	for _, p := range cfg.Peers {
+		if p.Name == name {
+			peer = p
+			break
+		}
+	}
+	if peer == nil {
+		return fmt.Errorf("%w: %s", errPeerNotAllowed, name)
+	}
+
+	if cfg.IsTLSEnabled() {
+		return mtls.VerifyPeer(ctx, peer)
+	}
+	if !cfg.RbacEnabled {
+		return nil
+	}
+	if len(peer.Token) == 0 {
+		return fmt.Errorf("%w: %s has neither certificate nor token to identify", errPeerNotAllowed, name)
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return rbac.NewError(rbac.ErrNoAuthHeader, "")
+	}
+	to, err := bearerToken(md)
+	if err != nil {
+		return err
+	}
+	token, err := cipher.Decrypt(peer.Token)
+	if err != nil {
+		log.Error("decrypt peer token failed, use original content", err)
+		token = peer.Token
+	}
+	if subtle.ConstantTimeCompare([]byte(to), []byte(token)) != 1 {
+		return fmt.Errorf("%w: the token is not the one of %s", errPeerNotAllowed, name)
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/metadata"
 
+	_ "github.com/apache/servicecomb-service-center/server/plugin/security/cipher/buildin"
 	"github.com/apache/servicecomb-service-center/syncer/config"
 )
 
@@ -128,3 +129,33 @@ func Test_auth(t *testing.T) {
 		})
 	}
 }
+
+func Test_authPeer(t *testing.T) {
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(),
+			metadata.New(map[string]string{restful.HeaderAuth: "Bearer " + token}))
+	}
+	peers := []*config.Peer{
+		{Name: "b", Mode: []string{config.ModeWatch}, Token: "token-b"},
+		{Name: "c", Mode: []string{config.ModeWatch}},
+	}
+
+	t.Run("rbac and tls disabled, should pass", func(t *testing.T) {
+		config.SetConfig(config.Config{Sync: &config.Sync{Peers: peers}})
+		assert.NoError(t, authPeer(context.Background(), "b"))
+	})
+
+	config.SetConfig(config.Config{Sync: &config.Sync{RbacEnabled: true, Peers: peers}})
+	t.Run("the token of the peer, should pass", func(t *testing.T) {
+		assert.NoError(t, authPeer(withToken("token-b"), "b"))
+	})
+	t.Run("claim to be another peer, should fail", func(t *testing.T) {
+		assert.ErrorIs(t, authPeer(withToken("token-c"), "b"), errPeerNotAllowed)
+	})
+	t.Run("the peer has no token, should fail", func(t *testing.T) {
+		assert.ErrorIs(t, authPeer(withToken("token-c"), "c"), errPeerNotAllowed)
+	})
+	t.Run("not a configured peer, should fail", func(t *testing.T) {
+		assert.ErrorIs(t, authPeer(withToken("token-b"), "d"), errPeerNotAllowed)
+	})
+}
@@ -24,7 +24,9 @@ import (
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/compress"
 	"github.com/apache/servicecomb-service-center/syncer/config"
+	"github.com/apache/servicecomb-service-center/syncer/service/reconcile"
 	"github.com/apache/servicecomb-service-center/syncer/service/replicator"
 	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
 )
@@ -65,6 +67,28 @@ func (s *Server) Sync(ctx context.Context, events *v1sync.EventList) (*v1sync.Re
 	return s.toResults(res), nil
 }
 
+// SyncChunks receives an event larger than the page limit in chunks
+func (s *Server) SyncChunks(stream v1sync.EventService_SyncChunksServer) error {
+	ctx := stream.Context()
+	err := auth(ctx)
+	if err != nil {
+		log.Error("auth failed", err)
+		return err
+	}
+
+	event, err := replicator.ReceiveChunks(stream.Recv, replicator.MaxChunkedSize)
+	if err != nil {
+		log.Error("receive event chunks failed", err)
+		return err
+	}
+
+	log.Info(fmt.Sprintf("start sync event in chunks: %s, size is %d", event.Flag(), len(event.Value)))
+
+	res := s.replicator.Persist(ctx, &v1sync.EventList{Events: []*v1sync.Event{event}})
+
+	return stream.SendAndClose(s.toResults(res))
+}
+
 func generateFailedResults(events *v1sync.EventList, err error) (*v1sync.Results, error) {
 	if events == nil || len(events.Events) == 0 {
 		return &v1sync.Results{Results: map[string]*v1sync.Result{}}, nil
@@ -92,10 +116,38 @@ func (s *Server) toResults(results []*resource.Result) *v1sync.Results {
 	}
 }
 
+// Watch streams the local events to the peer in watch mode, starting from the revision
+func (s *Server) Watch(in *v1sync.WatchRequest, stream v1sync.EventService_WatchServer) error {
+	err := auth(stream.Context())
+	if err != nil {
+		log.Error("auth failed", err)
+		return err
+	}
+	err = authPeer(stream.Context(), in.Peer)
+	if err != nil {
+		log.Error(fmt.Sprintf("auth peer %s failed", in.Peer), err)
+		return err
+	}
+
+	return replicator.ServeWatch(stream.Context(), in, stream.Send)
+}
+
+// Digest returns the local digests of the subject, the peer uses them to reconcile
+func (s *Server) Digest(ctx context.Context, in *v1sync.DigestRequest) (*v1sync.DigestReply, error) {
+	err := auth(ctx)
+	if err != nil {
+		log.Error("auth failed", err)
+		return nil, err
+	}
+
+	return reconcile.LocalDigest(ctx, in.Subject)
+}
+
 func (s *Server) Health(ctx context.Context, _ *v1sync.HealthRequest) (*v1sync.HealthReply, error) {
 	resp := &v1sync.HealthReply{
 		Status:         HealthStatusConnected,
 		LocalTimestamp: time.Now().UnixNano(),
+		Compressors:    compress.Supported(),
 	}
 	err := auth(ctx)
 	if err != nil {
@@ -25,11 +25,14 @@ import (
 	syncv1 "github.com/apache/servicecomb-service-center/syncer/api/v1"
 	"github.com/apache/servicecomb-service-center/syncer/config"
 	"github.com/apache/servicecomb-service-center/syncer/metrics"
+	"github.com/apache/servicecomb-service-center/syncer/mtls"
 	"github.com/apache/servicecomb-service-center/syncer/rpc"
 	"github.com/apache/servicecomb-service-center/syncer/service/admin"
 	"github.com/apache/servicecomb-service-center/syncer/service/sync"
 )
 
+const grpcProtocol = "grpc"
+
 // Run register chassis schema and run syncer services before chassis.Run()
 func Run() {
 	if err := config.Init(); err != nil {
@@ -50,7 +53,15 @@ func Run() {
 		log.Info("syncer rbac enabled")
 	}
 
-	chassis.RegisterSchema("grpc", rpc.NewServer(),
+	if config.GetConfig().Sync.IsTLSEnabled() {
+		if err := enableTLS(); err != nil {
+			log.Error("syncer tls init failed", err)
+			return
+		}
+		log.Info("syncer mutual tls enabled")
+	}
+
+	chassis.RegisterSchema(grpcProtocol, rpc.NewServer(),
 		chassisServer.WithRPCServiceDesc(&syncv1.EventService_ServiceDesc))
 
 	admin.Init()
@@ -61,3 +72,16 @@ func Run() {
 		log.Error("syncer metrics init failed", err)
 	}
 }
+
+// enableTLS makes the grpc server serve with the mutual tls config of syncer
+// instead of the chassis ssl config, so that the certificates can be reloaded
+func enableTLS() error {
+	tlsConfig, err := mtls.ServerConfig(config.GetConfig().Sync)
+	if err != nil {
+		return err
+	}
+	chassisServer.InstallPlugin(grpcProtocol, func(opts chassisServer.Options) chassisServer.ProtocolServer {
+		return newTLSServer(opts, tlsConfig)
+	})
+	return nil
+}
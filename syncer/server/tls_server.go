@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+
+	grpcServer "github.com/go-chassis/go-chassis-extension/protocol/grpc/server"
+	"github.com/go-chassis/go-chassis/v2/core/common"
+	"github.com/go-chassis/go-chassis/v2/core/handler"
+	"github.com/go-chassis/go-chassis/v2/core/invocation"
+	"github.com/go-chassis/go-chassis/v2/core/registry"
+	chassisServer "github.com/go-chassis/go-chassis/v2/core/server"
+	"github.com/go-chassis/go-chassis/v2/pkg/util/iputil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+var ErrRPCServiceDesc = errors.New("grpc service desc is required")
+
+// tlsServer is the chassis grpc server serving with the transport credentials instead of
+// a tls listener, so that the certificate of the peer can be read from the context of a call
+type tlsServer struct {
+	s    *grpc.Server
+	opts chassisServer.Options
+}
+
+func newTLSServer(opts chassisServer.Options, tlsConfig *tls.Config) chassisServer.ProtocolServer {
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handle grpc.UnaryHandler) (interface{}, error) {
+		c, err := handler.GetChain(common.Provider, opts.ChainName)
+		if err != nil {
+			log.Error("get handler chain failed", err)
+			return nil, err
+		}
+		var r *invocation.Response
+		c.Next(grpcServer.Request2Invocation(ctx, req, info), func(ir *invocation.Response) {
+			ir.Result, ir.Err = handle(ctx, req)
+			r = ir
+		})
+		return r.Result, r.Err
+	}
+
+	sops := []grpc.ServerOption{
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.UnaryInterceptor(interceptor),
+	}
+	if opts.BodyLimit != 0 {
+		sops = append(sops, grpc.MaxRecvMsgSize(int(opts.BodyLimit)))
+	}
+	return &tlsServer{
+		opts: opts,
+		s:    grpc.NewServer(sops...),
+	}
+}
+
+func (s *tlsServer) Register(schema interface{}, options ...chassisServer.RegisterOption) (string, error) {
+	opts := chassisServer.RegisterOptions{}
+	for _, o := range options {
+		o(&opts)
+	}
+	desc, ok := opts.RPCSvcDesc.(*grpc.ServiceDesc)
+	if !ok {
+		return "", ErrRPCServiceDesc
+	}
+	s.s.RegisterService(desc, schema)
+	return "", nil
+}
+
+func (s *tlsServer) Start() error {
+	listener, host, port, err := iputil.StartListener(s.opts.Address, nil)
+	if err != nil {
+		log.Error(fmt.Sprintf("listen %s failed", s.opts.Address), err)
+		return err
+	}
+	registry.InstanceEndpoints[grpcProtocol] = net.JoinHostPort(host, port)
+
+	go func() {
+		if err := s.s.Serve(listener); err != nil {
+			chassisServer.ErrRuntime <- err
+		}
+	}()
+	return nil
+}
+
+func (s *tlsServer) Stop() error {
+	s.s.GracefulStop()
+	return nil
+}
+
+func (s *tlsServer) String() string {
+	return grpcProtocol
+}
@@ -0,0 +1,41 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package admin
+
+import (
+	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
+)
+
+type ConflictResp struct {
+	Conflicts []*resource.ConflictRecord `json:"conflicts"`
+	Total     int                        `json:"total"`
+}
+
+// Conflicts returns the recent conflicts detected when persisting the events from peers
+func Conflicts() *ConflictResp {
+	conflicts := resource.ListConflicts()
+	return &ConflictResp{
+		Conflicts: conflicts,
+		Total:     len(conflicts),
+	}
+}
+
+// ClearConflicts removes the conflict records and clears the conflict alarm
+func ClearConflicts() error {
+	return resource.ClearConflicts()
+}
@@ -89,7 +89,7 @@ func Init() {
 			p.Token = plainToken
 		}
 
-		conn, err := newRPCConn(p.Endpoints)
+		conn, err := newRPCConn(c)
 		if err != nil {
 			log.Error(fmt.Sprintf("new client failed for peer: %s", c.Name), err)
 			continue
@@ -162,12 +162,16 @@ func reportMetrics(peers []*Peer) {
 	metrics.ConnectedPeersSet(connectPeersCount)
 }
 
-func newRPCConn(endpoints []string) (*grpc.ClientConn, error) {
+func newRPCConn(p *config.Peer) (*grpc.ClientConn, error) {
+	tlsConfig, err := syncerclient.PeerTLSConfig(p)
+	if err != nil {
+		return nil, err
+	}
 	return pkgrpc.GetRoundRobinLbConn(&pkgrpc.Config{
-		Addrs:       endpoints,
+		Addrs:       p.Endpoints,
 		Scheme:      scheme,
 		ServiceName: serviceName,
-		TLSConfig:   syncerclient.RPClientConfig(),
+		TLSConfig:   tlsConfig,
 	})
 }
 
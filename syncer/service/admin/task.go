@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package admin
+
+import (
+	"context"
+
+	carisync "github.com/go-chassis/cari/sync"
+
+	"github.com/apache/servicecomb-service-center/eventbase/model"
+	servicetask "github.com/apache/servicecomb-service-center/eventbase/service/task"
+	"github.com/apache/servicecomb-service-center/syncer/service/task"
+)
+
+// TaskStatusFailed filters the tasks which failed to replicate to any peer
+const TaskStatusFailed = "failed"
+
+type Task struct {
+	*carisync.Task
+	// Peers are the replication states of the task, it is empty if the
+	// task is not handled by the task manager of this node
+	Peers map[string]*task.PeerState `json:"peers,omitempty"`
+}
+
+type TaskResp struct {
+	Tasks []*Task `json:"tasks"`
+	Total int     `json:"total"`
+}
+
+// ListTasks lists the sync tasks, the status can be pending, done or failed
+func ListTasks(ctx context.Context, req *model.ListTaskRequest) (*TaskResp, error) {
+	failed := req.Status == TaskStatusFailed
+	if failed {
+		req.Status = ""
+	}
+	tasks, err := servicetask.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TaskResp{Tasks: make([]*Task, 0, len(tasks))}
+	for _, t := range tasks {
+		states, _ := task.PeerStates(t.ID)
+		if failed && !hasFailedPeer(states) {
+			continue
+		}
+		resp.Tasks = append(resp.Tasks, &Task{Task: t, Peers: states})
+	}
+	resp.Total = len(resp.Tasks)
+	return resp, nil
+}
+
+func hasFailedPeer(states map[string]*task.PeerState) bool {
+	for _, s := range states {
+		if s.Status == task.PeerStatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryTask resends the task to the peers which are not done
+func RetryTask(id string) error {
+	return task.Retry(id)
+}
+
+// DiscardTask deletes the task, it will not be replicated any more
+func DiscardTask(ctx context.Context, id string) error {
+	return task.Discard(ctx, id)
+}
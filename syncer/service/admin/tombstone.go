@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package admin
+
+import (
+	"context"
+	"errors"
+
+	carisync "github.com/go-chassis/cari/sync"
+
+	"github.com/apache/servicecomb-service-center/eventbase/model"
+	"github.com/apache/servicecomb-service-center/eventbase/service/tombstone"
+)
+
+var ErrPurgeConditionEmpty = errors.New("at least one condition is required to purge tombstones")
+
+type TombstoneResp struct {
+	Tombstones []*carisync.Tombstone `json:"tombstones"`
+	Total      int                   `json:"total"`
+}
+
+// ListTombstones lists the tombstones of the deleted resources
+func ListTombstones(ctx context.Context, req *model.ListTombstoneRequest) (*TombstoneResp, error) {
+	tombstones, err := tombstone.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &TombstoneResp{
+		Tombstones: tombstones,
+		Total:      len(tombstones),
+	}, nil
+}
+
+// PurgeTombstones deletes the tombstones matched the request, returns the deleted count
+func PurgeTombstones(ctx context.Context, req *model.ListTombstoneRequest) (int, error) {
+	if len(req.Domain) == 0 && len(req.Project) == 0 && len(req.ResourceType) == 0 && req.BeforeTimestamp == 0 {
+		return 0, ErrPurgeConditionEmpty
+	}
+	tombstones, err := tombstone.List(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	if len(tombstones) == 0 {
+		return 0, nil
+	}
+	err = tombstone.Delete(ctx, tombstones...)
+	if err != nil {
+		return 0, err
+	}
+	return len(tombstones), nil
+}
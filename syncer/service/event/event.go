@@ -24,6 +24,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/service/relay"
 )
 
 func Publish(ctx context.Context, action string, resourceType string, resource interface{}) {
@@ -38,12 +39,14 @@ func Publish(ctx context.Context, action string, resourceType string, resource i
 		return
 	}
 
+	opts := util.SyncRelayOpts(ctx, map[string]string{
+		string(util.CtxDomain):  util.ParseDomain(ctx),
+		string(util.CtxProject): util.ParseProject(ctx),
+	})
+	relay.Stamp(opts)
 	e := &v1sync.Event{
-		Id: eventID,
-		Opts: map[string]string{
-			string(util.CtxDomain):  util.ParseDomain(ctx),
-			string(util.CtxProject): util.ParseProject(ctx),
-		},
+		Id:        eventID,
+		Opts:      opts,
 		Subject:   resourceType,
 		Action:    action,
 		Value:     resourceValue,
@@ -27,6 +27,8 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
 	"github.com/apache/servicecomb-service-center/syncer/metrics"
+	"github.com/apache/servicecomb-service-center/syncer/service/filter"
+	"github.com/apache/servicecomb-service-center/syncer/service/relay"
 	"github.com/apache/servicecomb-service-center/syncer/service/replicator"
 	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
 
@@ -41,7 +43,10 @@ const (
 	eventSliceSize     = 100
 )
 
-var m Manager
+var (
+	lock     sync.RWMutex
+	managers = make(map[string]Manager)
+)
 
 type Event struct {
 	*v1sync.Event
@@ -52,24 +57,55 @@ type Event struct {
 
 type Result struct {
 	ID    string
+	Peer  string
 	Data  *v1sync.Result
 	Error error
 }
 
+// Work starts an event manager for each peer, so that each peer has its own queue
 func Work() {
-	m = NewManager()
-	m.HandleEvent()
-	m.HandleResult()
+	for _, peer := range replicator.PeerNames() {
+		r, ok := replicator.PeerManager(peer)
+		if !ok {
+			continue
+		}
+		em := NewManager(ManagerPeer(peer), Replicator(r))
+		em.HandleEvent()
+		em.HandleResult()
+		AddManager(peer, em)
+	}
 }
 
-func GetManager() Manager {
-	return m
+// AddManager registers the event manager of the peer
+func AddManager(peer string, em Manager) {
+	lock.Lock()
+	defer lock.Unlock()
+	managers[peer] = em
+}
+
+// GetManager returns the event manager of the peer, nil if not exist
+func GetManager(peer string) Manager {
+	lock.RLock()
+	defer lock.RUnlock()
+	return managers[peer]
+}
+
+// Managers returns all the event managers, key is the peer name
+func Managers() map[string]Manager {
+	lock.RLock()
+	defer lock.RUnlock()
+	ms := make(map[string]Manager, len(managers))
+	for peer, em := range managers {
+		ms[peer] = em
+	}
+	return ms
 }
 
 type ManagerOption func(*managerOptions)
 
 type managerOptions struct {
 	internal   time.Duration
+	peer       string
 	replicator replicator.Replicator
 }
 
@@ -79,6 +115,12 @@ func ManagerInternal(i time.Duration) ManagerOption {
 	}
 }
 
+func ManagerPeer(peer string) ManagerOption {
+	return func(options *managerOptions) {
+		options.peer = peer
+	}
+}
+
 func toManagerOptions(os ...ManagerOption) *managerOptions {
 	mo := new(managerOptions)
 	mo.internal = DefaultInternal
@@ -103,6 +145,7 @@ func NewManager(os ...ManagerOption) Manager {
 		batchEvents: make(chan []*Event, batchEventChanSize),
 		result:      make(chan *Result, resChanSize),
 		internal:    mo.internal,
+		peer:        mo.peer,
 		Replicator:  mo.replicator,
 	}
 	return em
@@ -127,6 +170,7 @@ type ManagerImpl struct {
 
 	internal time.Duration
 	ticker   *time.Ticker
+	peer     string
 
 	cache  sync.Map
 	result chan *Result
@@ -148,7 +192,7 @@ func (e *ManagerImpl) Send(et *Event) {
 }
 
 func (e *ManagerImpl) checkThreshold(et *Event) bool {
-	metrics.PendingEventSet(int64(len(e.events)))
+	metrics.PeerPendingEventSet(e.peer, int64(len(e.events)))
 	if len(e.events) < cap(e.events) {
 		return false
 	}
@@ -159,7 +203,7 @@ func (e *ManagerImpl) checkThreshold(et *Event) bool {
 	}
 
 	log.Warn(fmt.Sprintf("drop event %s", et.Flag()))
-	metrics.AbandonEventAdd()
+	metrics.PeerAbandonEventAdd(e.peer, et.Subject, et.Action)
 	return true
 }
 
@@ -192,7 +236,7 @@ func (e *ManagerImpl) resultHandle(ctx context.Context) {
 			}
 
 			if res.Error != nil {
-				log.Error(fmt.Sprintf("result is error %s", event.Flag()), res.Error)
+				log.Error(fmt.Sprintf("result of peer %s is error %s", e.peer, event.Flag()), res.Error)
 				if r.CanDrop() {
 					log.Warn(fmt.Sprintf("drop event %s", event.Flag()))
 					continue
@@ -205,6 +249,11 @@ func (e *ManagerImpl) resultHandle(ctx context.Context) {
 				continue
 			}
 
+			if res.Data.Code == resource.Conflict {
+				log.Warn(fmt.Sprintf("event %s is rejected by peer %s for conflict, %s",
+					event.Flag(), e.peer, res.Data.Message))
+			}
+
 			toSendEvent, err := r.FailHandle(ctx, res.Data.Code)
 			if err != nil {
 				log.Warn(fmt.Sprintf("event %s fail handle failed, %s", event.Flag(), err.Error()))
@@ -310,23 +359,50 @@ func (e *ManagerImpl) handle(ctx context.Context, es syncEvents) {
 	})
 
 	if err != nil {
-		log.Error("replicate failed", err)
+		log.Error(fmt.Sprintf("replicate to peer %s failed", e.peer), err)
 		result = &v1sync.Results{
 			Results: make(map[string]*v1sync.Result),
 		}
 	}
 
-	for _, e := range es {
-		e.Result <- &Result{
-			ID:    e.Id,
-			Data:  result.Results[e.Id],
+	for _, et := range es {
+		res := &Result{
+			ID:    et.Id,
+			Peer:  e.peer,
+			Data:  result.Results[et.Id],
 			Error: err,
 		}
+		e.reportMetrics(et, res)
+		et.Result <- res
+	}
+}
+
+func (e *ManagerImpl) reportMetrics(et *Event, res *Result) {
+	if res.Error != nil || res.Data == nil || res.Data.Code == resource.Fail {
+		metrics.FailedEventAdd(e.peer, et.Subject, et.Action)
+		return
 	}
+	metrics.SentEventAdd(e.peer, et.Subject, et.Action)
+	metrics.ReplicationLatencyObserve(e.peer, et.Subject, et.Timestamp)
 }
 
-// Send sends event to replicator
+// Send sends event to the replicator of every peer, except the peers visited
+// or the peers whose rules filter out the resource
 func Send(e *Event) {
 	log.Info(fmt.Sprintf("send event %s", e.Subject))
-	m.Send(e)
+	r := filter.NewEventResource(e.Event)
+	for peer, em := range Managers() {
+		if relay.Visited(e.Opts, peer) {
+			continue
+		}
+		if !filter.Allow(context.Background(), peer, r) {
+			log.Info(fmt.Sprintf("event %s is filtered out for peer %s", e.Id, peer))
+			continue
+		}
+		em.Send(&Event{
+			Event:         e.Event,
+			CanNotAbandon: e.CanNotAbandon,
+			Result:        e.Result,
+		})
+	}
 }
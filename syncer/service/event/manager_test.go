@@ -24,8 +24,13 @@ import (
 
 	_ "github.com/apache/servicecomb-service-center/test"
 
+	pb "github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/config"
 	"github.com/apache/servicecomb-service-center/syncer/service/event"
+	"github.com/apache/servicecomb-service-center/syncer/service/filter"
 	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
 	"github.com/stretchr/testify/assert"
 )
@@ -46,9 +51,11 @@ func TestWork(t *testing.T) {
 		},
 		err: nil,
 	}
-	event.Work()
-	em := event.GetManager().(*event.ManagerImpl)
-	em.Replicator = r
+	em := event.NewManager(event.ManagerPeer("dc"), event.Replicator(r))
+	em.HandleEvent()
+	em.HandleResult()
+	event.AddManager("dc", em)
+	assert.Equal(t, em, event.GetManager("dc"))
 
 	var f *forkResources
 	resource.RegisterResources("fork", func(event *v1sync.Event) resource.Resource {
@@ -142,3 +149,54 @@ func TestNewManager(t *testing.T) {
 	nm := event.NewManager(event.ManagerInternal(event.DefaultInternal), event.Replicator(new(mockReplicator)))
 	assert.NotNil(t, nm)
 }
+
+type recordManager struct {
+	events []*event.Event
+}
+
+func (r *recordManager) Send(e *event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recordManager) HandleEvent() {}
+
+func (r *recordManager) HandleResult() {}
+
+type mockServiceGetter struct {
+	services map[string]*pb.MicroService
+}
+
+func (m *mockServiceGetter) GetService(_ context.Context, in *pb.GetServiceRequest) (*pb.MicroService, error) {
+	s, ok := m.services[in.ServiceId]
+	if !ok {
+		return nil, pb.NewError(pb.ErrServiceNotExists, "service not exist")
+	}
+	return s, nil
+}
+
+func TestSendFiltered(t *testing.T) {
+	origin := config.GetConfig()
+	defer config.SetConfig(origin)
+	originGetter := filter.SetServiceGetter(&mockServiceGetter{services: map[string]*pb.MicroService{
+		"prod": {ServiceId: "prod", AppId: "app", Environment: "production"},
+		"dev":  {ServiceId: "dev", AppId: "app", Environment: "development"},
+	}})
+	defer filter.SetServiceGetter(originGetter)
+	config.SetConfig(config.Config{Sync: &config.Sync{Peers: []*config.Peer{
+		{Name: "filter-dr", Exclude: []*config.Rule{{Environments: []string{"development"}}}},
+	}}})
+
+	dr := new(recordManager)
+	event.AddManager("filter-dr", dr)
+	ctx := util.SetDomainProject(context.TODO(), "default", "default")
+
+	event.Publish(ctx, "create", resource.Instance, &pb.RegisterInstanceRequest{
+		Instance: &pb.MicroServiceInstance{ServiceId: "dev", InstanceId: "dev-1"},
+	})
+	assert.Equal(t, 0, len(dr.events), "the development instance should not be sent")
+
+	event.Publish(ctx, "create", resource.Instance, &pb.RegisterInstanceRequest{
+		Instance: &pb.MicroServiceInstance{ServiceId: "prod", InstanceId: "prod-1"},
+	})
+	assert.Equal(t, 1, len(dr.events))
+}
@@ -0,0 +1,222 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package filter decides whether the resources are replicated to the peers,
+// according to the include and exclude rules of the peers
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/sync"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/config"
+	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
+)
+
+const (
+	labelApp         = "app"
+	labelEnvironment = "environment"
+)
+
+// ServiceGetter gets the service which the resource belongs to
+type ServiceGetter interface {
+	GetService(ctx context.Context, in *pb.GetServiceRequest) (*pb.MicroService, error)
+}
+
+type metadataManage struct {
+}
+
+func (m *metadataManage) GetService(ctx context.Context, in *pb.GetServiceRequest) (*pb.MicroService, error) {
+	return datasource.GetMetadataManager().GetService(ctx, in)
+}
+
+var serviceGetter ServiceGetter = new(metadataManage)
+
+// SetServiceGetter replaces the service getter and returns the origin one
+func SetServiceGetter(g ServiceGetter) ServiceGetter {
+	origin := serviceGetter
+	serviceGetter = g
+	return origin
+}
+
+// Resource is the resource to be replicated, AppID and Environment are loaded
+// from the Value if the resource belongs to a service
+type Resource struct {
+	Domain      string
+	Project     string
+	Type        string
+	AppID       string
+	Environment string
+	Value       []byte
+
+	loaded bool
+	// known is true if the resource belongs to a service, or a config has the app or environment label
+	known bool
+}
+
+// NewResource returns the resource of the task
+func NewResource(task *sync.Task) *Resource {
+	return &Resource{
+		Domain:  task.Domain,
+		Project: task.Project,
+		Type:    task.ResourceType,
+		Value:   task.Resource,
+	}
+}
+
+// NewEventResource returns the resource of the event
+func NewEventResource(e *v1sync.Event) *Resource {
+	return &Resource{
+		Domain:  e.Opts[string(util.CtxDomain)],
+		Project: e.Opts[string(util.CtxProject)],
+		Type:    e.Subject,
+		Value:   e.Value,
+	}
+}
+
+// probe is the common fields of the replicated resources, used to find out
+// which service the resource belongs to
+type probe struct {
+	ServiceID string `json:"serviceId"`
+	Service   *struct {
+		AppID       string `json:"appId"`
+		Environment string `json:"environment"`
+	} `json:"service"`
+	Instance *struct {
+		ServiceID string `json:"serviceId"`
+	} `json:"instance"`
+	Labels map[string]string `json:"labels"`
+}
+
+// load parses the appId and environment of the resource, the service is got if the
+// resource only has the service id, the fields are left empty if the service not exist
+func (r *Resource) load(ctx context.Context) {
+	if r.loaded {
+		return
+	}
+	r.loaded = true
+
+	switch r.Type {
+	case resource.Microservice, resource.Instance, resource.Heartbeat, resource.Config:
+	default:
+		return
+	}
+
+	p := new(probe)
+	err := json.Unmarshal(r.Value, p)
+	if err != nil {
+		log.Warn(fmt.Sprintf("can not parse %s resource to filter, %s", r.Type, err))
+		return
+	}
+	if r.Type == resource.Config {
+		app, hasApp := p.Labels[labelApp]
+		env, hasEnv := p.Labels[labelEnvironment]
+		r.AppID, r.Environment, r.known = app, env, hasApp || hasEnv
+		return
+	}
+	if p.Service != nil {
+		r.AppID, r.Environment, r.known = p.Service.AppID, p.Service.Environment, true
+		return
+	}
+
+	serviceID := p.ServiceID
+	if p.Instance != nil {
+		serviceID = p.Instance.ServiceID
+	}
+	if len(serviceID) == 0 {
+		return
+	}
+	ctx = util.SetDomainProject(ctx, r.Domain, r.Project)
+	service, err := serviceGetter.GetService(ctx, &pb.GetServiceRequest{ServiceId: serviceID})
+	if err != nil {
+		log.Warn(fmt.Sprintf("can not get service %s to filter %s resource, %s", serviceID, r.Type, err))
+		return
+	}
+	r.AppID, r.Environment, r.known = service.AppId, service.Environment, true
+}
+
+// Allow returns true if the resource is allowed to be replicated to the peer, the resource
+// of unknown appId or environment, such as account or the deleted service, is only filtered
+// by the other conditions
+func Allow(ctx context.Context, peer string, r *Resource) bool {
+	p, ok := config.GetConfig().Sync.GetPeer(peer)
+	if !ok || (len(p.Include) == 0 && len(p.Exclude) == 0) {
+		return true
+	}
+	if needLoad(p.Include) || needLoad(p.Exclude) {
+		r.load(ctx)
+	}
+	if len(p.Include) > 0 && !matchAny(p.Include, r, true) {
+		return false
+	}
+	return !matchAny(p.Exclude, r, false)
+}
+
+func needLoad(rules []*config.Rule) bool {
+	for _, rule := range rules {
+		if len(rule.AppIDs) > 0 || len(rule.Environments) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny returns true if the resource matches any of the rules, the unknown
+// appId or environment is regarded as matched if the unknown is true
+func matchAny(rules []*config.Rule, r *Resource, unknown bool) bool {
+	for _, rule := range rules {
+		if match(rule, r, unknown) {
+			return true
+		}
+	}
+	return false
+}
+
+func match(rule *config.Rule, r *Resource, unknown bool) bool {
+	return contains(rule.Domains, r.Domain) &&
+		contains(rule.Projects, r.Project) &&
+		contains(rule.ResourceTypes, r.Type) &&
+		containsOrUnknown(rule.AppIDs, r, r.AppID, unknown) &&
+		containsOrUnknown(rule.Environments, r, r.Environment, unknown)
+}
+
+func contains(values []string, v string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsOrUnknown(values []string, r *Resource, v string, unknown bool) bool {
+	if len(values) > 0 && !r.known {
+		return unknown
+	}
+	return contains(values, v)
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/sync"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/syncer/config"
+	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
+)
+
+type mockServiceGetter struct {
+	services map[string]*pb.MicroService
+}
+
+func (m *mockServiceGetter) GetService(_ context.Context, in *pb.GetServiceRequest) (*pb.MicroService, error) {
+	s, ok := m.services[in.ServiceId]
+	if !ok {
+		return nil, pb.NewError(pb.ErrServiceNotExists, "service not exist")
+	}
+	return s, nil
+}
+
+func newTask(t *testing.T, resourceType string, v interface{}) *sync.Task {
+	value, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return &sync.Task{
+		Domain:       "default",
+		Project:      "default",
+		ResourceType: resourceType,
+		Resource:     value,
+	}
+}
+
+func TestAllow(t *testing.T) {
+	origin := config.GetConfig()
+	defer config.SetConfig(origin)
+	originGetter := serviceGetter
+	defer func() {
+		serviceGetter = originGetter
+	}()
+	serviceGetter = &mockServiceGetter{services: map[string]*pb.MicroService{
+		"prod": {ServiceId: "prod", AppId: "app", Environment: "production"},
+		"dev":  {ServiceId: "dev", AppId: "app", Environment: "development"},
+	}}
+
+	config.SetConfig(config.Config{Sync: &config.Sync{Peers: []*config.Peer{
+		{Name: "all"},
+		{
+			Name:    "dr",
+			Include: []*config.Rule{{Environments: []string{"production"}}},
+			Exclude: []*config.Rule{{ResourceTypes: []string{resource.Config}}},
+		},
+	}}})
+	ctx := context.TODO()
+
+	prodService := newTask(t, resource.Microservice, &pb.CreateServiceRequest{
+		Service: &pb.MicroService{ServiceId: "prod", Environment: "production"},
+	})
+	devService := newTask(t, resource.Microservice, &pb.CreateServiceRequest{
+		Service: &pb.MicroService{ServiceId: "dev", Environment: "development"},
+	})
+	prodInstance := newTask(t, resource.Instance, &pb.RegisterInstanceRequest{
+		Instance: &pb.MicroServiceInstance{ServiceId: "prod"},
+	})
+	devHeartbeat := newTask(t, resource.Heartbeat, &pb.HeartbeatRequest{ServiceId: "dev"})
+	deletedService := newTask(t, resource.Microservice, &pb.DeleteServiceRequest{ServiceId: "deleted"})
+	account := newTask(t, resource.Account, map[string]string{"name": "admin"})
+	prodConfig := newTask(t, resource.Config, map[string]interface{}{
+		"labels": map[string]string{"environment": "production"},
+	})
+
+	tests := []struct {
+		name  string
+		peer  string
+		task  *sync.Task
+		allow bool
+	}{
+		{"peer without rules", "all", devService, true},
+		{"peer not configured", "unknown", devService, true},
+		{"included service", "dr", prodService, true},
+		{"not included service", "dr", devService, false},
+		{"included instance", "dr", prodInstance, true},
+		{"not included heartbeat", "dr", devHeartbeat, false},
+		{"deleted service is unknown", "dr", deletedService, true},
+		{"account is unknown", "dr", account, true},
+		{"excluded resource type", "dr", prodConfig, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.allow, Allow(ctx, tt.peer, NewResource(tt.task)))
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	r := &Resource{Domain: "default", Project: "p1", Type: resource.Microservice,
+		AppID: "app", Environment: "", loaded: true, known: true}
+	assert.True(t, match(&config.Rule{}, r, false))
+	assert.True(t, match(&config.Rule{Projects: []string{"p1", "p2"}}, r, false))
+	assert.False(t, match(&config.Rule{Projects: []string{"p2"}}, r, false))
+	assert.True(t, match(&config.Rule{Environments: []string{""}}, r, false))
+	assert.False(t, match(&config.Rule{AppIDs: []string{"app"}, Domains: []string{"other"}}, r, true))
+}
@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	kiemodel "github.com/apache/servicecomb-kie/pkg/model"
+	kiedb "github.com/apache/servicecomb-kie/server/datasource"
+	carisync "github.com/go-chassis/cari/sync"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/eventbase/model"
+	"github.com/apache/servicecomb-service-center/eventbase/service/tombstone"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
+)
+
+// Subjects are the resource types to reconcile, in the order of dependency
+var Subjects = []string{
+	resource.Account,
+	resource.Role,
+	resource.Microservice,
+	resource.KV,
+	resource.Instance,
+	resource.Config,
+}
+
+var kieOnce sync.Once
+
+// ListDigest lists the local digests of the subject
+func ListDigest(ctx context.Context, subject string) ([]*datasource.Digest, error) {
+	if subject == resource.Config {
+		return listConfigDigests(ctx)
+	}
+	return datasource.GetSyncManager().ListDigest(ctx, subject)
+}
+
+// ListTombstones lists the local tombstones of the subject
+func ListTombstones(ctx context.Context, subject string) ([]*carisync.Tombstone, error) {
+	return tombstone.List(ctx, &model.ListTombstoneRequest{
+		ResourceType: subject,
+	})
+}
+
+// listConfigDigests lists the configs of the domains and projects which have services
+func listConfigDigests(ctx context.Context) ([]*datasource.Digest, error) {
+	kieOnce.Do(func() {
+		kind := config.GetString("registry.kind", "etcd", config.WithStandby("registry_plugin"))
+		if err := kiedb.Init(kind); err != nil {
+			log.Error(fmt.Sprintf("kie datasource[%s] init failed", kind), err)
+		}
+	})
+
+	services, err := datasource.GetSyncManager().ListDigest(ctx, resource.Microservice)
+	if err != nil {
+		return nil, err
+	}
+	domainProjects := map[string][2]string{
+		carisync.Default + "/" + carisync.Default: {carisync.Default, carisync.Default},
+	}
+	for _, s := range services {
+		domainProjects[s.Domain+"/"+s.Project] = [2]string{s.Domain, s.Project}
+	}
+
+	digests := make([]*datasource.Digest, 0)
+	for _, dp := range domainProjects {
+		domain, project := dp[0], dp[1]
+		resp, err := kiedb.GetBroker().GetKVDao().List(util.SetDomainProject(ctx, domain, project), project, domain)
+		if err != nil {
+			log.Error(fmt.Sprintf("list configs of %s/%s failed", domain, project), err)
+			return nil, err
+		}
+		for _, doc := range resp.Data {
+			d, err := configDigest(domain, project, doc)
+			if err != nil {
+				return nil, err
+			}
+			digests = append(digests, d)
+		}
+	}
+	return digests, nil
+}
+
+func configDigest(domain, project string, doc *kiemodel.KVDoc) (*datasource.Digest, error) {
+	c := *doc
+	c.CreateRevision, c.UpdateRevision = 0, 0
+	c.CreateTime, c.UpdateTime = 0, 0
+	content, err := json.Marshal(&c)
+	if err != nil {
+		return nil, err
+	}
+	d := datasource.NewDigest(resource.Config, domain, project, kiedb.TombstoneID(doc), content)
+	d.Timestamp = doc.UpdateTime * 1000 * 1000 * 1000
+	d.Resource = doc
+	return d, nil
+}
@@ -0,0 +1,217 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	carisync "github.com/go-chassis/cari/sync"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/service/event"
+	"github.com/apache/servicecomb-service-center/syncer/service/filter"
+	"github.com/apache/servicecomb-service-center/syncer/service/replicator"
+)
+
+var ErrEventManagerNotFound = errors.New("event manager of peer not found")
+
+// Repair is the action to repair the drift of a resource in peer
+type Repair struct {
+	Action string
+	Digest *datasource.Digest
+}
+
+// Reconcile compares the local digests with the peer's, and sends the
+// events to the peer to repair the resources that are missing or stale
+func Reconcile(ctx context.Context, peer string) error {
+	em := event.GetManager(peer)
+	if em == nil {
+		return ErrEventManagerNotFound
+	}
+
+	for _, subject := range Subjects {
+		local, err := ListDigest(ctx, subject)
+		if errors.Is(err, datasource.ErrNotSupportDigest) {
+			continue
+		}
+		if err != nil {
+			log.Error(fmt.Sprintf("list local digests of %s failed", subject), err)
+			return err
+		}
+
+		remote, err := replicator.Digest(ctx, peer, subject)
+		if err != nil {
+			log.Error(fmt.Sprintf("get digests of %s from peer %s failed", subject, peer), err)
+			return err
+		}
+
+		repairs := Diff(local, remote)
+		log.Info(fmt.Sprintf("reconcile %s with peer %s, local %d, remote %d, repair %d",
+			subject, peer, len(local), len(remote.Digests), len(repairs)))
+		for _, r := range repairs {
+			e, err := toEvent(subject, r)
+			if err != nil {
+				log.Error(fmt.Sprintf("convert repair of %s %s to event failed", subject, r.Digest.ResourceID), err)
+				continue
+			}
+			if !filter.Allow(ctx, peer, filter.NewEventResource(e)) {
+				continue
+			}
+			em.Send(&event.Event{
+				Event: e,
+			})
+		}
+	}
+	return nil
+}
+
+// Diff returns the repairs that peer needs, a missing resource will be created
+// unless the peer deleted it later, a different resource will be updated only
+// if the local one is newer, the peer will repair the opposite cases by itself
+func Diff(local []*datasource.Digest, remote *v1sync.DigestReply) []*Repair {
+	remoteDigests := make(map[string]*v1sync.Digest, len(remote.Digests))
+	for _, d := range remote.Digests {
+		remoteDigests[key(d.Domain, d.Project, d.ResourceId)] = d
+	}
+	tombstones := make(map[string]*v1sync.Digest, len(remote.Tombstones))
+	for _, t := range remote.Tombstones {
+		tombstones[key(t.Domain, t.Project, t.ResourceId)] = t
+	}
+
+	repairs := make([]*Repair, 0)
+	for _, d := range local {
+		k := key(d.Domain, d.Project, d.ResourceID)
+		r, ok := remoteDigests[k]
+		if !ok {
+			if t, deleted := tombstones[k]; deleted && t.Timestamp >= d.Timestamp {
+				continue
+			}
+			repairs = append(repairs, &Repair{Action: carisync.CreateAction, Digest: d})
+			continue
+		}
+		if r.Hash != d.Hash && d.Timestamp > r.Timestamp {
+			repairs = append(repairs, &Repair{Action: carisync.UpdateAction, Digest: d})
+		}
+	}
+	return repairs
+}
+
+func key(domain, project, resourceID string) string {
+	if len(domain) == 0 {
+		domain = carisync.Default
+	}
+	if len(project) == 0 {
+		project = carisync.Default
+	}
+	return domain + "/" + project + "/" + resourceID
+}
+
+func toEvent(subject string, r *Repair) (*v1sync.Event, error) {
+	d := r.Digest
+	resource := d.Resource
+	if r.Action == carisync.UpdateAction && d.Update != nil {
+		resource = d.Update
+	}
+
+	var value []byte
+	switch rv := resource.(type) {
+	case string:
+		value = []byte(rv)
+	case []byte:
+		value = rv
+	default:
+		var err error
+		value, err = json.Marshal(resource)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	id, err := v1sync.NewEventID()
+	if err != nil {
+		return nil, err
+	}
+
+	domain, project := d.Domain, d.Project
+	if len(domain) == 0 {
+		domain = carisync.Default
+	}
+	if len(project) == 0 {
+		project = carisync.Default
+	}
+	opts := make(map[string]string, len(d.Opts)+2)
+	for k, v := range d.Opts {
+		opts[k] = v
+	}
+	opts[string(util.CtxDomain)] = domain
+	opts[string(util.CtxProject)] = project
+
+	timestamp := d.Timestamp
+	if timestamp == 0 {
+		timestamp = v1sync.Timestamp()
+	}
+	return &v1sync.Event{
+		Id:        id,
+		Action:    r.Action,
+		Subject:   subject,
+		Opts:      opts,
+		Value:     value,
+		Timestamp: timestamp,
+	}, nil
+}
+
+// LocalDigest returns the local digests and tombstones of the subject for the peer
+func LocalDigest(ctx context.Context, subject string) (*v1sync.DigestReply, error) {
+	digests, err := ListDigest(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	tombstones, err := ListTombstones(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &v1sync.DigestReply{
+		Digests:    make([]*v1sync.Digest, 0, len(digests)),
+		Tombstones: make([]*v1sync.Digest, 0, len(tombstones)),
+	}
+	for _, d := range digests {
+		reply.Digests = append(reply.Digests, &v1sync.Digest{
+			ResourceId: d.ResourceID,
+			Domain:     d.Domain,
+			Project:    d.Project,
+			Hash:       d.Hash,
+			Timestamp:  d.Timestamp,
+		})
+	}
+	for _, t := range tombstones {
+		reply.Tombstones = append(reply.Tombstones, &v1sync.Digest{
+			ResourceId: t.ResourceID,
+			Domain:     t.Domain,
+			Project:    t.Project,
+			Timestamp:  t.Timestamp,
+		})
+	}
+	return reply, nil
+}
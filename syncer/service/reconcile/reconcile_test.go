@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/go-chassis/cari/sync"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+)
+
+func TestDiff(t *testing.T) {
+	local := []*datasource.Digest{
+		{ResourceID: "missing", Hash: "1", Timestamp: 10},
+		{ResourceID: "deleted", Hash: "1", Timestamp: 10},
+		{ResourceID: "same", Hash: "1", Timestamp: 10},
+		{ResourceID: "newer", Hash: "1", Timestamp: 20},
+		{ResourceID: "older", Hash: "1", Timestamp: 10},
+	}
+	remote := &v1sync.DigestReply{
+		Digests: []*v1sync.Digest{
+			{ResourceId: "same", Domain: sync.Default, Project: sync.Default, Hash: "1", Timestamp: 5},
+			{ResourceId: "newer", Hash: "2", Timestamp: 10},
+			{ResourceId: "older", Hash: "2", Timestamp: 20},
+		},
+		Tombstones: []*v1sync.Digest{
+			{ResourceId: "deleted", Domain: sync.Default, Project: sync.Default, Timestamp: 15},
+		},
+	}
+
+	repairs := Diff(local, remote)
+	if assert.Equal(t, 2, len(repairs)) {
+		assert.Equal(t, "missing", repairs[0].Digest.ResourceID)
+		assert.Equal(t, sync.CreateAction, repairs[0].Action)
+		assert.Equal(t, "newer", repairs[1].Digest.ResourceID)
+		assert.Equal(t, sync.UpdateAction, repairs[1].Action)
+	}
+}
+
+func TestToEvent(t *testing.T) {
+	d := &datasource.Digest{
+		ResourceID: "key",
+		Timestamp:  10,
+		Resource:   []byte("value"),
+		Update:     "updated",
+		Opts:       map[string]string{"key": "key"},
+	}
+
+	e, err := toEvent("kv", &Repair{Action: sync.CreateAction, Digest: d})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), e.Value)
+	assert.Equal(t, int64(10), e.Timestamp)
+	assert.Equal(t, "key", e.Opts["key"])
+	assert.Equal(t, sync.Default, e.Opts["domain"])
+
+	e, err = toEvent("kv", &Repair{Action: sync.UpdateAction, Digest: d})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("updated"), e.Value)
+}
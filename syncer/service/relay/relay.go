@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package relay tracks the sites which the events passed through, so that the changes applied
+// from a peer can be replicated onward to the other peers without cycles
+package relay
+
+import (
+	"context"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/config"
+)
+
+const (
+	// OptOrigin is the opt of the event, the name of the site where the change happened
+	OptOrigin = string(util.CtxSyncOrigin)
+	// OptVisited is the opt of the event, the names of the sites the event passed through
+	OptVisited = string(util.CtxSyncVisited)
+
+	sep = ","
+)
+
+// Enabled returns true if the changes applied from the peers are relayed
+func Enabled() bool {
+	cfg := config.GetConfig().Sync
+	return cfg != nil && cfg.Relay && len(cfg.Name) > 0
+}
+
+func localName() string {
+	if cfg := config.GetConfig().Sync; cfg != nil {
+		return cfg.Name
+	}
+	return ""
+}
+
+// Context returns the ctx to apply the event, the changes are recorded as tasks
+// carrying the origin and visited sites of the event if relay enabled
+func Context(ctx context.Context, e *v1sync.Event) context.Context {
+	if !Enabled() {
+		return ctx
+	}
+	return util.WithSyncRelay(ctx, e.Opts[OptOrigin], e.Opts[OptVisited])
+}
+
+// Stamp adds local site to the visited sites of the opts, and sets the origin
+// to local if the change happened locally
+func Stamp(opts map[string]string) {
+	name := localName()
+	if len(name) == 0 || opts == nil {
+		return
+	}
+	if len(opts[OptOrigin]) == 0 {
+		opts[OptOrigin] = name
+	}
+	visited := VisitedSites(opts)
+	for _, site := range visited {
+		if site == name {
+			return
+		}
+	}
+	opts[OptVisited] = strings.Join(append(visited, name), sep)
+}
+
+// VisitedSites returns the sites the event passed through
+func VisitedSites(opts map[string]string) []string {
+	if len(opts[OptVisited]) == 0 {
+		return []string{}
+	}
+	return strings.Split(opts[OptVisited], sep)
+}
+
+// Visited returns true if the event has passed through the peer,
+// then it should not be replicated to the peer again
+func Visited(opts map[string]string, peer string) bool {
+	if opts[OptOrigin] == peer {
+		return true
+	}
+	for _, site := range VisitedSites(opts) {
+		if site == peer {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package relay_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/config"
+	"github.com/apache/servicecomb-service-center/syncer/service/relay"
+)
+
+func TestStamp(t *testing.T) {
+	origin := config.GetConfig()
+	defer config.SetConfig(origin)
+
+	config.SetConfig(config.Config{Sync: &config.Sync{}})
+	opts := map[string]string{}
+	relay.Stamp(opts)
+	assert.Empty(t, opts, "local name is empty, should not stamp")
+
+	config.SetConfig(config.Config{Sync: &config.Sync{Name: "hub"}})
+	relay.Stamp(opts)
+	assert.Equal(t, "hub", opts[relay.OptOrigin])
+	assert.Equal(t, "hub", opts[relay.OptVisited])
+	relay.Stamp(opts)
+	assert.Equal(t, "hub", opts[relay.OptVisited], "should not stamp twice")
+
+	opts = map[string]string{relay.OptOrigin: "dc1", relay.OptVisited: "dc1,dc2"}
+	relay.Stamp(opts)
+	assert.Equal(t, "dc1", opts[relay.OptOrigin])
+	assert.Equal(t, []string{"dc1", "dc2", "hub"}, relay.VisitedSites(opts))
+}
+
+func TestVisited(t *testing.T) {
+	opts := map[string]string{relay.OptOrigin: "dc1", relay.OptVisited: "dc2,hub"}
+	assert.True(t, relay.Visited(opts, "dc1"))
+	assert.True(t, relay.Visited(opts, "dc2"))
+	assert.True(t, relay.Visited(opts, "hub"))
+	assert.False(t, relay.Visited(opts, "dc3"))
+	assert.False(t, relay.Visited(map[string]string{}, "dc3"))
+}
+
+func TestContext(t *testing.T) {
+	origin := config.GetConfig()
+	defer config.SetConfig(origin)
+
+	e := &v1sync.Event{Opts: map[string]string{relay.OptOrigin: "dc1", relay.OptVisited: "dc1"}}
+
+	config.SetConfig(config.Config{Sync: &config.Sync{Name: "hub"}})
+	ctx := relay.Context(context.Background(), e)
+	assert.False(t, util.EnableSync(ctx), "relay disabled, should not enable sync")
+
+	config.SetConfig(config.Config{Sync: &config.Sync{Name: "hub", Relay: true}})
+	ctx = relay.Context(context.Background(), e)
+	assert.True(t, util.EnableSync(ctx))
+	opts := util.SyncRelayOpts(ctx, map[string]string{"key": "value"})
+	assert.Equal(t, map[string]string{"key": "value", relay.OptOrigin: "dc1", relay.OptVisited: "dc1"}, opts)
+}
@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replicator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+
+	"github.com/apache/servicecomb-service-center/client"
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+)
+
+const (
+	chunkSize = 1024 * 1024
+	// MaxChunkedSize is the max size of an event received in chunks
+	MaxChunkedSize = 128 * 1024 * 1024
+)
+
+var (
+	ErrInvalidChunk  = errors.New("invalid event chunk")
+	ErrEventTooLarge = errors.New("event is too large")
+)
+
+// splitEvent splits the event value into chunks, the first chunk carries the event without value
+func splitEvent(event *v1sync.Event, size int) []*v1sync.EventChunk {
+	header := &v1sync.Event{
+		Id:        event.Id,
+		Action:    event.Action,
+		Subject:   event.Subject,
+		Opts:      event.Opts,
+		Timestamp: event.Timestamp,
+	}
+	chunks := make([]*v1sync.EventChunk, 0, len(event.Value)/size+1)
+	for start := 0; start < len(event.Value) || len(chunks) == 0; start += size {
+		end := start + size
+		if end > len(event.Value) {
+			end = len(event.Value)
+		}
+		chunks = append(chunks, &v1sync.EventChunk{
+			Data: event.Value[start:end],
+		})
+	}
+	chunks[0].Event = header
+	chunks[len(chunks)-1].Last = true
+	return chunks
+}
+
+func syncChunks(ctx context.Context, set *client.Set, event *v1sync.Event, size int,
+	opts []grpc.CallOption) (*v1sync.Results, error) {
+	stream, err := set.EventServiceClient.SyncChunks(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, chunk := range splitEvent(event, size) {
+		err = stream.Send(chunk)
+		if err == io.EOF {
+			// the peer closed the stream, the real error is returned by CloseAndRecv
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// ReceiveChunks reassembles the event from the chunks, the size of the value can not exceed max
+func ReceiveChunks(recv func() (*v1sync.EventChunk, error), max int) (*v1sync.Event, error) {
+	var event *v1sync.Event
+	for {
+		chunk, err := recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: the last chunk is missing", ErrInvalidChunk)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if event == nil {
+			if chunk.Event == nil {
+				return nil, fmt.Errorf("%w: the first chunk has no event", ErrInvalidChunk)
+			}
+			event = chunk.Event
+		}
+		if len(event.Value)+len(chunk.Data) > max {
+			return nil, fmt.Errorf("%w: exceeds %d bytes", ErrEventTooLarge, max)
+		}
+		event.Value = append(event.Value, chunk.Data...)
+		if chunk.Last {
+			return event, nil
+		}
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replicator
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/compress"
+	"github.com/apache/servicecomb-service-center/syncer/config"
+	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
+)
+
+func chunkReceiver(chunks []*v1sync.EventChunk) func() (*v1sync.EventChunk, error) {
+	i := 0
+	return func() (*v1sync.EventChunk, error) {
+		if i >= len(chunks) {
+			return nil, io.EOF
+		}
+		i++
+		return chunks[i-1], nil
+	}
+}
+
+func TestSplitEvent(t *testing.T) {
+	event := &v1sync.Event{Id: "1", Subject: "kv", Action: "create", Value: []byte("0123456789"), Timestamp: 1}
+
+	chunks := splitEvent(event, 4)
+	if assert.Equal(t, 3, len(chunks)) {
+		assert.Equal(t, "1", chunks[0].Event.Id)
+		assert.Empty(t, chunks[0].Event.Value)
+		assert.Nil(t, chunks[1].Event)
+		assert.Equal(t, "89", string(chunks[2].Data))
+		assert.False(t, chunks[1].Last)
+		assert.True(t, chunks[2].Last)
+	}
+
+	e, err := ReceiveChunks(chunkReceiver(chunks), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", e.Id)
+	assert.Equal(t, "0123456789", string(e.Value))
+
+	_, err = ReceiveChunks(chunkReceiver(splitEvent(event, 4)), 9)
+	assert.ErrorIs(t, err, ErrEventTooLarge)
+
+	_, err = ReceiveChunks(chunkReceiver(splitEvent(event, 4)[:2]), 10)
+	assert.ErrorIs(t, err, ErrInvalidChunk)
+
+	_, err = ReceiveChunks(chunkReceiver(splitEvent(event, 4)[1:]), 10)
+	assert.ErrorIs(t, err, ErrInvalidChunk)
+
+	chunks = splitEvent(&v1sync.Event{Id: "2"}, 4)
+	if assert.Equal(t, 1, len(chunks)) {
+		assert.True(t, chunks[0].Last)
+	}
+}
+
+type chunkServer struct {
+	v1sync.UnimplementedEventServiceServer
+
+	lock     sync.Mutex
+	received []string
+}
+
+func (s *chunkServer) record(event *v1sync.Event) *v1sync.Results {
+	s.lock.Lock()
+	s.received = append(s.received, event.Id)
+	s.lock.Unlock()
+	return &v1sync.Results{Results: map[string]*v1sync.Result{
+		event.Id: {Code: resource.Success},
+	}}
+}
+
+func (s *chunkServer) Health(context.Context, *v1sync.HealthRequest) (*v1sync.HealthReply, error) {
+	return &v1sync.HealthReply{Compressors: compress.Supported()}, nil
+}
+
+func (s *chunkServer) Sync(_ context.Context, el *v1sync.EventList) (*v1sync.Results, error) {
+	results := &v1sync.Results{Results: map[string]*v1sync.Result{}}
+	for _, event := range el.Events {
+		mergeResults(results, s.record(event))
+	}
+	return results, nil
+}
+
+func (s *chunkServer) SyncChunks(stream v1sync.EventService_SyncChunksServer) error {
+	event, err := ReceiveChunks(stream.Recv, MaxChunkedSize)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(s.record(event))
+}
+
+func TestReplicateInChunks(t *testing.T) {
+	config.SetConfig(config.Config{Sync: &config.Sync{}})
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	cs := &chunkServer{}
+	v1sync.RegisterEventServiceServer(server, cs)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	r := NewPeerManager(&Peer{
+		Name:        "peer",
+		conn:        conn,
+		compression: []string{compress.Zstd},
+	})
+	large := []byte(strings.Repeat("a", maxSize+chunkSize))
+	res, err := r.Replicate(context.Background(), &v1sync.EventList{Events: []*v1sync.Event{
+		{Id: "1", Value: []byte("small")},
+		{Id: "2", Value: large},
+		{Id: "3", Value: []byte("small")},
+	}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 3, len(res.Results))
+	assert.Equal(t, []string{"1", "2", "3"}, cs.received, "events should be sent in order")
+	assert.Equal(t, compress.Zstd, r.(*replicatorManager).peer.compressor)
+}
@@ -19,12 +19,16 @@ package replicator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/go-chassis/foundation/gopool"
 	"github.com/go-chassis/go-chassis/v2/server/restful"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/apache/servicecomb-service-center/client"
 	"github.com/apache/servicecomb-service-center/pkg/log"
@@ -33,7 +37,9 @@ import (
 	"github.com/apache/servicecomb-service-center/server/plugin/security/cipher"
 	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
 	syncerclient "github.com/apache/servicecomb-service-center/syncer/client"
+	"github.com/apache/servicecomb-service-center/syncer/compress"
 	"github.com/apache/servicecomb-service-center/syncer/config"
+	"github.com/apache/servicecomb-service-center/syncer/service/relay"
 	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
 )
 
@@ -49,13 +55,33 @@ const (
 
 var (
 	manager = NewManager(make(map[string]struct{}, 1000))
+
+	ErrPeerNotFound = errors.New("peer not found")
 )
 
 var (
-	conn      *grpc.ClientConn
-	peerToken = ""
+	peerLock     sync.RWMutex
+	peerManagers = make(map[string]Replicator)
+	peerNames    = make([]string, 0)
+	// peers are the dialed peers, including the push peers and the watched peers
+	peers = make([]*Peer, 0)
+	// subscribers are the peers that local subscribes events from
+	subscribers = make([]*Peer, 0)
 )
 
+// Peer holds the connection and credential used to communicate with a remote syncer
+type Peer struct {
+	Name  string
+	conn  *grpc.ClientConn
+	token string
+
+	// compression is the preferred compressors, compressor is the one negotiated with the peer
+	compression []string
+	lock        sync.Mutex
+	negotiated  bool
+	compressor  string
+}
+
 func Work() error {
 	err := InitSyncClient()
 	if err != nil {
@@ -68,50 +94,210 @@ func Work() error {
 		Close()
 	})
 
+	for _, peer := range subscribers {
+		p := peer
+		gopool.Go(func(ctx context.Context) {
+			subscribe(ctx, p)
+		})
+	}
+
 	resource.InitManager()
 	return err
 }
 
+// InitSyncClient dials every configured peer, a peer failed to dial will be skipped
+// so that it does not block the replication to the others
 func InitSyncClient() error {
-	peer := config.GetConfig().Sync.Peers[0]
-	log.Info(fmt.Sprintf("peer is %v", peer))
-	var err error
-	conn, err = rpc.GetRoundRobinLbConn(&rpc.Config{
-		Addrs:       peer.Endpoints,
+	peerLock.Lock()
+	defer peerLock.Unlock()
+
+	for _, p := range config.GetConfig().Sync.Peers {
+		if _, ok := peerManagers[p.Name]; ok {
+			log.Warn(fmt.Sprintf("duplicate peer %s, skip it", p.Name))
+			continue
+		}
+		if p.HasMode(config.ModeWatch) && len(p.Endpoints) == 0 {
+			// the peer can not be dialed, it subscribes the local events
+			peerManagers[p.Name] = NewWatchManager(p.Name, newBacklog(p.Name, backlogSize))
+			peerNames = append(peerNames, p.Name)
+			continue
+		}
+		peer, err := newPeer(p)
+		if err != nil {
+			log.Error(fmt.Sprintf("init sync client of peer %s failed", p.Name), err)
+			continue
+		}
+		peers = append(peers, peer)
+		if p.HasMode(config.ModeWatch) {
+			subscribers = append(subscribers, peer)
+		}
+		if p.HasMode(config.ModePush) {
+			peerManagers[p.Name] = NewPeerManager(peer)
+			peerNames = append(peerNames, p.Name)
+		}
+	}
+	if len(peerManagers) == 0 && len(subscribers) == 0 {
+		return ErrPeerNotFound
+	}
+	return nil
+}
+
+func newPeer(p *config.Peer) (*Peer, error) {
+	log.Info(fmt.Sprintf("peer is %v", p))
+	tlsConfig, err := syncerclient.PeerTLSConfig(p)
+	if err != nil {
+		log.Error(fmt.Sprintf("init tls config of peer %s failed", p.Name), err)
+		return nil, err
+	}
+	conn, err := rpc.GetRoundRobinLbConn(&rpc.Config{
+		Addrs:       p.Endpoints,
 		Scheme:      schema,
 		ServiceName: serviceName,
-		TLSConfig:   syncerclient.RPClientConfig(),
+		TLSConfig:   tlsConfig,
 	})
 	if err != nil {
 		log.Error("get rpc client failed", err)
-		return err
+		return nil, err
+	}
+	peer := &Peer{
+		Name:        p.Name,
+		conn:        conn,
+		compression: p.Compression,
 	}
 	if !config.GetConfig().Sync.RbacEnabled {
-		return nil
+		return peer, nil
 	}
-	peerToken, err = cipher.Decrypt(peer.Token)
+	peer.token, err = cipher.Decrypt(p.Token)
 	if err != nil {
 		log.Error("decrypt peer token failed, use original content", err)
-		peerToken = peer.Token
+		peer.token = p.Token
 	}
-	return nil
+	return peer, nil
 }
 
-func Close() {
-	if conn == nil {
+// withToken returns the outgoing context carrying the token of the peer if rbac enabled
+func (p *Peer) withToken(ctx context.Context) context.Context {
+	if !config.GetConfig().Sync.RbacEnabled {
+		return ctx
+	}
+	return metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{
+		restful.HeaderAuth: "Bearer " + p.token,
+	}))
+}
+
+// callOptions negotiates the compressor with the peer by health check at the first call,
+// the events are sent without compression if the peer supports none of the preferred ones
+func (p *Peer) callOptions(ctx context.Context) []grpc.CallOption {
+	if len(p.compression) == 0 {
+		return nil
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !p.negotiated {
+		reply, err := client.NewSet(p.conn).EventServiceClient.Health(p.withToken(ctx), &v1sync.HealthRequest{})
+		if err != nil {
+			log.Error(fmt.Sprintf("negotiate compressor with peer %s failed", p.Name), err)
+			return nil
+		}
+		p.compressor = compress.Negotiate(p.compression, reply.Compressors)
+		p.negotiated = true
+		log.Info(fmt.Sprintf("negotiate compressor with peer %s: [%s]", p.Name, p.compressor))
+	}
+	if len(p.compressor) == 0 {
+		return nil
+	}
+	return []grpc.CallOption{grpc.UseCompressor(p.compressor)}
+}
+
+// renegotiate makes the next call negotiate the compressor again, it is called
+// when the peer does not recognize the compressor, e.g. the peer is downgraded
+func (p *Peer) renegotiate(err error) {
+	if status.Code(err) != codes.Unimplemented {
 		return
 	}
+	p.lock.Lock()
+	p.negotiated = false
+	p.lock.Unlock()
+}
 
-	err := conn.Close()
+// Digest gets the digests of the subject from the peer
+func Digest(ctx context.Context, name, subject string) (*v1sync.DigestReply, error) {
+	peer, ok := getPeer(name)
+	if !ok {
+		return nil, ErrPeerNotFound
+	}
+	reply, err := client.NewSet(peer.conn).EventServiceClient.Digest(peer.withToken(ctx), &v1sync.DigestRequest{
+		Subject: subject,
+	}, peer.callOptions(ctx)...)
 	if err != nil {
-		log.Error("close conn failed", err)
+		peer.renegotiate(err)
+	}
+	return reply, err
+}
+
+func getPeer(name string) (*Peer, bool) {
+	peerLock.RLock()
+	defer peerLock.RUnlock()
+	for _, peer := range peers {
+		if peer.Name == name {
+			return peer, true
+		}
+	}
+	return nil, false
+}
+
+func Close() {
+	peerLock.RLock()
+	defer peerLock.RUnlock()
+
+	for _, peer := range peers {
+		err := peer.conn.Close()
+		if err != nil {
+			log.Error(fmt.Sprintf("close conn of peer %s failed", peer.Name), err)
+		}
 	}
 }
 
+// Manager returns the local replicator which persists events received from peers
 func Manager() Replicator {
 	return manager
 }
 
+// PeerManager returns the replicator which sends events to the specified peer
+func PeerManager(name string) (Replicator, bool) {
+	peerLock.RLock()
+	defer peerLock.RUnlock()
+	r, ok := peerManagers[name]
+	return r, ok
+}
+
+// PeerNames returns the names of all the peers which sync client inited, in configuration order
+func PeerNames() []string {
+	peerLock.RLock()
+	defer peerLock.RUnlock()
+	names := make([]string, len(peerNames))
+	copy(names, peerNames)
+	return names
+}
+
+// DialedPeerNames returns the names of the peers which the local pushes events to
+// over a connection, the peers in watch mode only are excluded, in configuration order
+func DialedPeerNames() []string {
+	peerLock.RLock()
+	defer peerLock.RUnlock()
+	names := make([]string, 0, len(peerNames))
+	for _, name := range peerNames {
+		for _, peer := range peers {
+			if peer.Name == name {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
 // Replicator define replicator manager, receive events from event manager
 // and send events to remote syncer
 type Replicator interface {
@@ -125,8 +311,17 @@ func NewManager(cache map[string]struct{}) Replicator {
 	}
 }
 
+// NewPeerManager returns a replicator which replicates events to the peer
+func NewPeerManager(peer *Peer) Replicator {
+	return &replicatorManager{
+		cache: make(map[string]struct{}, 1000),
+		peer:  peer,
+	}
+}
+
 type replicatorManager struct {
 	cache map[string]struct{}
+	peer  *Peer
 }
 
 func (r *replicatorManager) Replicate(ctx context.Context, el *v1sync.EventList) (*v1sync.Results, error) {
@@ -166,39 +361,80 @@ func pageEvents(source *v1sync.EventList, max int) []*v1sync.EventList {
 }
 
 func (r *replicatorManager) replicate(ctx context.Context, el *v1sync.EventList) (*v1sync.Results, error) {
-	log.Info(fmt.Sprintf("start replicate events %d", len(el.Events)))
-
-	set := client.NewSet(conn)
+	if r.peer == nil {
+		return nil, ErrPeerNotFound
+	}
+	log.Info(fmt.Sprintf("start replicate events %d to peer %s", len(el.Events), r.peer.Name))
 
-	els := pageEvents(el, maxSize)
+	set := client.NewSet(r.peer.conn)
 
 	result := &v1sync.Results{
 		Results: make(map[string]*v1sync.Result, len(el.Events)),
 	}
 
-	log.Info(fmt.Sprintf("page count %d to sync", len(els)))
-	if config.GetConfig().Sync.RbacEnabled {
-		ctx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{
-			restful.HeaderAuth: "Bearer " + peerToken,
-		}))
+	opts := r.peer.callOptions(ctx)
+	ctx = r.peer.withToken(ctx)
+
+	// the events larger than the page limit are streamed in chunks, the others are
+	// sent in pages, keep the order of the events
+	pending := &v1sync.EventList{
+		Events: make([]*v1sync.Event, 0, len(el.Events)),
 	}
+	for _, event := range el.Events {
+		if len(event.Value) < maxSize {
+			pending.Events = append(pending.Events, event)
+			continue
+		}
+		err := r.syncPages(ctx, set, pending, result, opts)
+		if err != nil {
+			return nil, err
+		}
+		pending.Events = pending.Events[:0]
 
-	for _, in := range els {
-		res, err := set.EventServiceClient.Sync(ctx, in)
+		res, err := syncChunks(ctx, set, event, chunkSize, opts)
 		if err != nil {
+			r.peer.renegotiate(err)
 			return nil, err
 		}
+		log.Info(fmt.Sprintf("replicate event %s in chunks success, size is %d", event.Flag(), len(event.Value)))
+		mergeResults(result, res)
+	}
+	err := r.syncPages(ctx, set, pending, result, opts)
+	if err != nil {
+		return nil, err
+	}
 
-		log.Info(fmt.Sprintf("replicate events success, count is %d", len(in.Events)))
+	log.Info(fmt.Sprintf("replicate events to peer %s success %d", r.peer.Name, len(result.Results)))
+	return result, nil
+}
+
+func (r *replicatorManager) syncPages(ctx context.Context, set *client.Set, el *v1sync.EventList,
+	result *v1sync.Results, opts []grpc.CallOption) error {
+	if len(el.Events) == 0 {
+		return nil
+	}
+
+	els := pageEvents(el, maxSize)
+	log.Info(fmt.Sprintf("page count %d to sync", len(els)))
 
-		for k, v := range res.Results {
-			log.Info(fmt.Sprintf("replicate event %s, %v", k, v))
-			result.Results[k] = v
+	for _, in := range els {
+		res, err := set.EventServiceClient.Sync(ctx, in, opts...)
+		if err != nil {
+			r.peer.renegotiate(err)
+			return err
 		}
+
+		log.Info(fmt.Sprintf("replicate events success, count is %d", len(in.Events)))
+		mergeResults(result, res)
 	}
+	return nil
+}
 
-	log.Info(fmt.Sprintf("replicate events success %d", len(result.Results)))
-	return result, nil
+func mergeResults(result *v1sync.Results, res *v1sync.Results) {
+	for k, v := range res.Results {
+		log.Info(fmt.Sprintf("replicate event %s, %v", k, v))
+		result.Results[k] = v
+	}
 }
 
 func (r *replicatorManager) Persist(ctx context.Context, el *v1sync.EventList) []*resource.Result {
@@ -218,6 +454,7 @@ func (r *replicatorManager) Persist(ctx context.Context, el *v1sync.EventList) [
 
 		ctx = util.SetDomain(ctx, event.Opts[string(util.CtxDomain)])
 		ctx = util.SetProject(ctx, event.Opts[string(util.CtxProject)])
+		ctx = relay.Context(ctx, event)
 
 		result = r.LoadCurrentResource(ctx)
 		if result != nil {
@@ -139,3 +139,27 @@ func Test_pageEvents(t *testing.T) {
 		}
 	})
 }
+
+func Test_replicatorManager_Replicate(t *testing.T) {
+	t.Run("no peer case", func(t *testing.T) {
+		_, err := manager.Replicate(context.TODO(), &v1sync.EventList{})
+		assert.ErrorIs(t, err, ErrPeerNotFound)
+	})
+
+	t.Run("peer not inited case", func(t *testing.T) {
+		_, ok := PeerManager("not exist")
+		assert.False(t, ok)
+	})
+}
+
+func TestDialedPeerNames(t *testing.T) {
+	oldNames, oldPeers := peerNames, peers
+	defer func() {
+		peerNames, peers = oldNames, oldPeers
+	}()
+
+	peerNames = []string{"push", "watch"}
+	peers = []*Peer{{Name: "push"}}
+	assert.Equal(t, []string{"push"}, DialedPeerNames())
+	assert.Equal(t, []string{"push", "watch"}, PeerNames())
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 
 	"github.com/apache/servicecomb-service-center/datasource/rbac"
 	"github.com/apache/servicecomb-service-center/pkg/log"
@@ -100,6 +101,11 @@ func (a *account) NeedOperate(ctx context.Context) *Result {
 		updateTime: func() (int64, error) {
 			return formatUpdateTimeSecond(a.cur.UpdateTime)
 		},
+		equal: func() bool {
+			return reflect.DeepEqual(a.cur.Roles, a.input.Roles) &&
+				a.cur.Status == a.input.Status &&
+				a.cur.TokenExpirationTime == a.input.TokenExpirationTime
+		},
 		resourceID: a.input.Name,
 	}
 	c.tombstoneLoader = c
@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 
 	kiemodel "github.com/apache/servicecomb-kie/pkg/model"
 	kiedb "github.com/apache/servicecomb-kie/server/datasource"
@@ -103,6 +104,12 @@ func (c *kvConfig) NeedOperate(ctx context.Context) *Result {
 		updateTime: func() (int64, error) {
 			return secToNanoSec(c.cur.UpdateTime), nil
 		},
+		equal: func() bool {
+			return c.cur.Value == c.input.Value &&
+				c.cur.ValueType == c.input.ValueType &&
+				c.cur.Status == c.input.Status &&
+				reflect.DeepEqual(c.cur.Labels, c.input.Labels)
+		},
 		resourceID: kiedb.TombstoneID(c.input),
 	}
 	ck.tombstoneLoader = ck
@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/config"
+)
+
+const (
+	conflictRecordSize = 1000
+
+	ResolutionLocalKept     = "localKept"
+	ResolutionEventApplied  = "eventApplied"
+	ResolutionEventRejected = "eventRejected"
+)
+
+var (
+	conflictLock sync.RWMutex
+	conflicts    = make([]*ConflictRecord, 0)
+)
+
+// ConflictRecord is the record of a change from the peer which conflicts with the local resource
+type ConflictRecord struct {
+	EventID        string `json:"eventId"`
+	Subject        string `json:"subject"`
+	Action         string `json:"action"`
+	ResourceID     string `json:"resourceId"`
+	Policy         string `json:"policy"`
+	Resolution     string `json:"resolution"`
+	LocalTimestamp int64  `json:"localTimestamp"`
+	EventTimestamp int64  `json:"eventTimestamp"`
+	Timestamp      int64  `json:"timestamp"`
+}
+
+func resolveConflict(event *v1sync.Event, resourceID string, updateTime int64) *Result {
+	policy := config.GetConfig().Sync.ConflictPolicy(event.Subject)
+	c := &ConflictRecord{
+		EventID:        event.Id,
+		Subject:        event.Subject,
+		Action:         event.Action,
+		ResourceID:     resourceID,
+		Policy:         policy,
+		LocalTimestamp: updateTime,
+		EventTimestamp: event.Timestamp,
+		Timestamp:      time.Now().UnixNano(),
+	}
+
+	var result *Result
+	switch policy {
+	case config.PolicyOriginWins:
+		c.Resolution = ResolutionEventApplied
+	case config.PolicyRejectAndAlarm:
+		c.Resolution = ResolutionEventRejected
+		result = ConflictResult(fmt.Sprintf("%s %s conflicts with the local resource", event.Subject, resourceID))
+	default:
+		c.Resolution = ResolutionLocalKept
+		result = SkipResult()
+	}
+	recordConflict(c)
+	return result
+}
+
+func recordConflict(c *ConflictRecord) {
+	conflictLock.Lock()
+	conflicts = append(conflicts, c)
+	if over := len(conflicts) - conflictRecordSize; over > 0 {
+		conflicts = append(conflicts[:0:0], conflicts[over:]...)
+	}
+	conflictLock.Unlock()
+
+	log.Warn(fmt.Sprintf("event %s conflicts with the local resource, policy: %s, resolution: %s",
+		c.EventID, c.Policy, c.Resolution))
+	err := alarm.Raise(alarm.IDSyncConflict,
+		alarm.FieldString("eventId", c.EventID),
+		alarm.FieldString("subject", c.Subject),
+		alarm.FieldString("resourceId", c.ResourceID),
+		alarm.FieldString("policy", c.Policy),
+		alarm.FieldString("resolution", c.Resolution),
+		alarm.AdditionalContext("%d conflicts", ConflictCount()))
+	if err != nil {
+		log.Error("raise sync conflict alarm failed", err)
+	}
+}
+
+// ListConflicts returns the recent conflicts, the oldest first
+func ListConflicts() []*ConflictRecord {
+	conflictLock.RLock()
+	defer conflictLock.RUnlock()
+	ls := make([]*ConflictRecord, len(conflicts))
+	copy(ls, conflicts)
+	return ls
+}
+
+// ConflictCount returns the count of the recent conflicts
+func ConflictCount() int {
+	conflictLock.RLock()
+	defer conflictLock.RUnlock()
+	return len(conflicts)
+}
+
+// ClearConflicts removes the conflict records and clears the alarm
+func ClearConflicts() error {
+	conflictLock.Lock()
+	conflicts = make([]*ConflictRecord, 0)
+	conflictLock.Unlock()
+	return alarm.Clear(alarm.IDSyncConflict)
+}
@@ -0,0 +1,149 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/config"
+
+	"github.com/go-chassis/cari/sync"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeedOperateConflict(t *testing.T) {
+	origin := config.GetConfig()
+	defer config.SetConfig(origin)
+	defer func() {
+		_ = ClearConflicts()
+	}()
+
+	newChecker := func(equal bool) *checker {
+		return &checker{
+			curNotNil: true,
+			event: &v1sync.Event{
+				Id:        "xxx",
+				Action:    sync.UpdateAction,
+				Subject:   Role,
+				Timestamp: v1sync.Timestamp(),
+			},
+			updateTime: func() (int64, error) {
+				return time.Now().Add(time.Minute).UnixNano(), nil
+			},
+			equal: func() bool {
+				return equal
+			},
+			resourceID: "admin",
+		}
+	}
+	ctx := context.TODO()
+
+	t.Run("no conflict when content is the same", func(t *testing.T) {
+		_ = ClearConflicts()
+		r := newChecker(true).needOperate(ctx)
+		if assert.NotNil(t, r) {
+			assert.Equal(t, Skip, r.Status)
+		}
+		assert.Equal(t, 0, ConflictCount())
+	})
+
+	t.Run("no conflict when event is newer", func(t *testing.T) {
+		_ = ClearConflicts()
+		c := newChecker(false)
+		c.updateTime = func() (int64, error) {
+			return time.Now().Add(-time.Minute).UnixNano(), nil
+		}
+		assert.Nil(t, c.needOperate(ctx))
+		assert.Equal(t, 0, ConflictCount())
+	})
+
+	t.Run("last writer wins by default", func(t *testing.T) {
+		_ = ClearConflicts()
+		config.SetConfig(config.Config{})
+		r := newChecker(false).needOperate(ctx)
+		if assert.NotNil(t, r) {
+			assert.Equal(t, Skip, r.Status)
+		}
+		conflicts := ListConflicts()
+		if assert.Equal(t, 1, len(conflicts)) {
+			assert.Equal(t, config.PolicyLastWriterWins, conflicts[0].Policy)
+			assert.Equal(t, ResolutionLocalKept, conflicts[0].Resolution)
+			assert.Equal(t, "admin", conflicts[0].ResourceID)
+		}
+	})
+
+	t.Run("origin wins", func(t *testing.T) {
+		_ = ClearConflicts()
+		config.SetConfig(config.Config{Sync: &config.Sync{
+			Conflict: &config.Conflict{
+				Policy:    config.PolicyRejectAndAlarm,
+				Resources: map[string]string{Role: config.PolicyOriginWins},
+			},
+		}})
+		assert.Nil(t, newChecker(false).needOperate(ctx))
+		conflicts := ListConflicts()
+		if assert.Equal(t, 1, len(conflicts)) {
+			assert.Equal(t, ResolutionEventApplied, conflicts[0].Resolution)
+		}
+	})
+
+	t.Run("reject and alarm", func(t *testing.T) {
+		_ = ClearConflicts()
+		config.SetConfig(config.Config{Sync: &config.Sync{
+			Conflict: &config.Conflict{
+				Policy: config.PolicyRejectAndAlarm,
+			},
+		}})
+		r := newChecker(false).needOperate(ctx)
+		if assert.NotNil(t, r) {
+			assert.Equal(t, Conflict, r.Status)
+		}
+		conflicts := ListConflicts()
+		if assert.Equal(t, 1, len(conflicts)) {
+			assert.Equal(t, ResolutionEventRejected, conflicts[0].Resolution)
+		}
+	})
+
+	t.Run("delete action is not a conflict", func(t *testing.T) {
+		_ = ClearConflicts()
+		c := newChecker(false)
+		c.event.Action = sync.DeleteAction
+		r := c.needOperate(ctx)
+		if assert.NotNil(t, r) {
+			assert.Equal(t, Skip, r.Status)
+		}
+		assert.Equal(t, 0, ConflictCount())
+	})
+}
+
+func TestRecordConflict(t *testing.T) {
+	defer func() {
+		_ = ClearConflicts()
+	}()
+
+	for i := 0; i < conflictRecordSize+10; i++ {
+		recordConflict(&ConflictRecord{EventID: "xxx"})
+	}
+	assert.Equal(t, conflictRecordSize, ConflictCount())
+
+	_ = ClearConflicts()
+	assert.Equal(t, 0, len(ListConflicts()))
+}
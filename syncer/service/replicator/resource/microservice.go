@@ -19,11 +19,13 @@ package resource
 
 import (
 	"context"
+	"reflect"
 
 	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
 
 	pb "github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/sync"
 )
 
 const (
@@ -112,12 +114,26 @@ func (m *microservice) NeedOperate(ctx context.Context) *Result {
 		updateTime: func() (int64, error) {
 			return formatUpdateTimeSecond(m.cur.ModTimestamp)
 		},
+		equal: func() bool {
+			// only properties can be updated, creating an existing service changes nothing
+			if m.event.Action != sync.UpdateAction {
+				return true
+			}
+			return equalProperties(m.cur.Properties, m.updateInput.Properties)
+		},
 		resourceID: m.serviceID,
 	}
 	c.tombstoneLoader = c
 	return c.needOperate(ctx)
 }
 
+func equalProperties(cur, input map[string]string) bool {
+	if len(cur) == 0 && len(input) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(cur, input)
+}
+
 func (m *microservice) CreateHandle(ctx context.Context) error {
 	_, err := m.manager.RegisterService(ctx, m.createInput)
 	return err
@@ -40,6 +40,7 @@ const (
 	MicroNonExist
 	InstNonExist
 	NonImplement
+	Conflict
 )
 
 const (
@@ -49,6 +50,7 @@ const (
 	ResultStatusMicroNonExist = "microNonExist"
 	ResultStatusInstNonExist  = "instNonExist"
 	ResultStatusNonImplement  = "nonImplement"
+	ResultStatusConflict      = "conflict"
 )
 
 var codeDescriber = map[int32]string{
@@ -58,6 +60,7 @@ var codeDescriber = map[int32]string{
 	MicroNonExist: ResultStatusMicroNonExist,
 	InstNonExist:  ResultStatusInstNonExist,
 	NonImplement:  ResultStatusNonImplement,
+	Conflict:      ResultStatusConflict,
 }
 
 type NewResource func(event *v1sync.Event) Resource
@@ -172,6 +175,10 @@ func NonImplementResult() *Result {
 	return NewResult(NonImplement, "")
 }
 
+func ConflictResult(message string) *Result {
+	return NewResult(Conflict, message)
+}
+
 type FailHandler interface {
 	FailHandle(context.Context, int32) (*v1sync.Event, error)
 	CanDrop() bool
@@ -301,6 +308,9 @@ type checker struct {
 
 	event      *v1sync.Event
 	updateTime func() (int64, error)
+	// equal returns true if the event changes nothing of the current resource,
+	// the resource does not detect conflicts if it is nil
+	equal      func() bool
 	resourceID string
 
 	tombstoneLoader tombstoneLoader
@@ -330,11 +340,13 @@ func (o *checker) needOperate(ctx context.Context) *Result {
 			log.Error("get update time failed", err)
 			return FailResult(err)
 		}
-		if updateTime >= o.event.Timestamp {
+		if updateTime < o.event.Timestamp {
+			return nil
+		}
+		if !o.isConflict() {
 			return SkipResult()
 		}
-
-		return nil
+		return resolveConflict(o.event, o.resourceID, updateTime)
 	}
 
 	switch o.event.Action {
@@ -369,6 +381,17 @@ func (o *checker) needOperate(ctx context.Context) *Result {
 	}
 }
 
+// isConflict returns true if the event changes the resource which is newer locally
+func (o *checker) isConflict() bool {
+	if o.equal == nil {
+		return false
+	}
+	if o.event.Action != sync.CreateAction && o.event.Action != sync.UpdateAction {
+		return false
+	}
+	return !o.equal()
+}
+
 func (o *checker) get(ctx context.Context, req *model.GetTombstoneRequest) (*sync.Tombstone, error) {
 	return tombstone.Get(ctx, req)
 }
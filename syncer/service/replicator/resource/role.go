@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 
 	"github.com/apache/servicecomb-service-center/datasource/rbac"
 	"github.com/apache/servicecomb-service-center/pkg/log"
@@ -108,6 +109,9 @@ func (r *role) NeedOperate(ctx context.Context) *Result {
 		updateTime: func() (int64, error) {
 			return formatUpdateTimeSecond(r.cur.UpdateTime)
 		},
+		equal: func() bool {
+			return reflect.DeepEqual(r.cur.Perms, r.input.Perms)
+		},
 		resourceID: r.input.Name,
 	}
 	checker.tombstoneLoader = checker
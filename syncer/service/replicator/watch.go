@@ -0,0 +1,363 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replicator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/config"
+	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
+)
+
+const (
+	backlogSize        = 10000
+	watchRetryInterval = 5 * time.Second
+	deliverTimeout     = 3 * time.Second
+
+	// RevisionKey is the option key of the backlog revision of the event sent to the watcher,
+	// the watcher resumes from the revision after reconnecting
+	RevisionKey = "watchRevision"
+)
+
+var (
+	ErrSyncerNameEmpty = errors.New("syncer name is empty")
+
+	backlogLock sync.RWMutex
+	backlogs    = make(map[string]*Backlog)
+)
+
+type backlogEntry struct {
+	revision int64
+	event    *v1sync.Event
+}
+
+// Backlog buffers the events for a peer in watch mode until they are delivered.
+// Every event appended gets the next revision, which starts from the creation time in
+// nanoseconds, so the revisions keep increasing after the service center restarts
+type Backlog struct {
+	lock      sync.RWMutex
+	size      int
+	revision  int64
+	delivered int64
+	watchers  int
+	entries   []*backlogEntry
+	notify    chan struct{}
+	sent      chan struct{}
+}
+
+func newBacklog(peer string, size int) *Backlog {
+	b := &Backlog{
+		size:     size,
+		revision: time.Now().UnixNano(),
+		entries:  make([]*backlogEntry, 0, size),
+		notify:   make(chan struct{}),
+		sent:     make(chan struct{}),
+	}
+	b.delivered = b.revision
+	backlogLock.Lock()
+	backlogs[peer] = b
+	backlogLock.Unlock()
+	return b
+}
+
+// GetBacklog returns the backlog of the peer in watch mode
+func GetBacklog(peer string) (*Backlog, bool) {
+	backlogLock.RLock()
+	defer backlogLock.RUnlock()
+	b, ok := backlogs[peer]
+	return b, ok
+}
+
+// Append adds events in order and returns the revision of the last one,
+// the oldest events are dropped when exceeding the size
+func (b *Backlog) Append(events ...*v1sync.Event) int64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if len(events) == 0 {
+		return b.revision
+	}
+	for _, event := range events {
+		b.revision++
+		b.entries = append(b.entries, &backlogEntry{revision: b.revision, event: event})
+	}
+	if over := len(b.entries) - b.size; over > 0 {
+		log.Warn(fmt.Sprintf("backlog reaches the limit %d, drop %d events", b.size, over))
+		b.entries = append(b.entries[:0:0], b.entries[over:]...)
+	}
+	close(b.notify)
+	b.notify = make(chan struct{})
+	return b.revision
+}
+
+// Since returns the events whose revision greater than the revision, the revision of
+// each event is set to the RevisionKey option of the copy, and returns a channel which
+// will be closed when new events appended
+func (b *Backlog) Since(revision int64) ([]*v1sync.Event, <-chan struct{}) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	i := sort.Search(len(b.entries), func(i int) bool {
+		return b.entries[i].revision > revision
+	})
+	events := make([]*v1sync.Event, 0, len(b.entries)-i)
+	for _, e := range b.entries[i:] {
+		events = append(events, withRevision(e.event, e.revision))
+	}
+	return events, b.notify
+}
+
+// Watching returns true if any watcher is connected
+func (b *Backlog) Watching() bool {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.watchers > 0
+}
+
+// WaitDelivered blocks until the events before the revision (included) are sent to the watcher,
+// returns false if timeout or the ctx is done
+func (b *Backlog) WaitDelivered(ctx context.Context, revision int64, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		b.lock.RLock()
+		delivered, sent := b.delivered, b.sent
+		b.lock.RUnlock()
+		if delivered >= revision {
+			return true
+		}
+		select {
+		case <-sent:
+		case <-timer.C:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (b *Backlog) watch() {
+	b.lock.Lock()
+	b.watchers++
+	b.lock.Unlock()
+}
+
+func (b *Backlog) unwatch() {
+	b.lock.Lock()
+	b.watchers--
+	b.lock.Unlock()
+}
+
+func (b *Backlog) ack(revision int64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if revision <= b.delivered {
+		return
+	}
+	b.delivered = revision
+	close(b.sent)
+	b.sent = make(chan struct{})
+}
+
+func withRevision(e *v1sync.Event, revision int64) *v1sync.Event {
+	opts := make(map[string]string, len(e.Opts)+1)
+	for k, v := range e.Opts {
+		opts[k] = v
+	}
+	opts[RevisionKey] = strconv.FormatInt(revision, 10)
+	return &v1sync.Event{
+		Id:        e.Id,
+		Action:    e.Action,
+		Subject:   e.Subject,
+		Opts:      opts,
+		Value:     e.Value,
+		Timestamp: e.Timestamp,
+	}
+}
+
+// eventRevision returns the backlog revision of the event, or the timestamp if the
+// event is sent by the old version publisher
+func eventRevision(e *v1sync.Event) int64 {
+	if v, ok := e.Opts[RevisionKey]; ok {
+		if revision, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return revision
+		}
+	}
+	return e.Timestamp
+}
+
+// NewWatchManager returns a replicator which delivers events to the watching peer
+// through the backlog
+func NewWatchManager(peer string, backlog *Backlog) Replicator {
+	return &watchManager{
+		peer:    peer,
+		backlog: backlog,
+	}
+}
+
+type watchManager struct {
+	peer    string
+	backlog *Backlog
+}
+
+// Replicate succeeds only if the events are sent to the watcher, otherwise the events fail
+// and are retried by the task manager, as the backlog is lost once the service center restarts
+func (w *watchManager) Replicate(ctx context.Context, el *v1sync.EventList) (*v1sync.Results, error) {
+	message := fmt.Sprintf("peer %s is not watching", w.peer)
+	delivered := false
+	if w.backlog.Watching() {
+		revision := w.backlog.Append(el.Events...)
+		delivered = w.backlog.WaitDelivered(ctx, revision, deliverTimeout)
+		message = fmt.Sprintf("deliver to peer %s timeout", w.peer)
+	}
+	log.Info(fmt.Sprintf("deliver events %d to peer %s, delivered: %t", len(el.Events), w.peer, delivered))
+
+	result := &v1sync.Results{
+		Results: make(map[string]*v1sync.Result, len(el.Events)),
+	}
+	for _, event := range el.Events {
+		if !delivered {
+			result.Results[event.Id] = &v1sync.Result{Code: resource.Fail, Message: message}
+			continue
+		}
+		result.Results[event.Id] = &v1sync.Result{
+			Code:    resource.Success,
+			Message: "delivered",
+		}
+	}
+	return result, nil
+}
+
+func (w *watchManager) Persist(ctx context.Context, el *v1sync.EventList) []*resource.Result {
+	return manager.Persist(ctx, el)
+}
+
+// ServeWatch sends the events since the revision to the peer, and blocks to
+// send the new events as they happen, until the ctx is done or send failed.
+// The caller must be authenticated as the peer, the backlog is acked by the sending
+func ServeWatch(ctx context.Context, in *v1sync.WatchRequest, send func(*v1sync.EventList) error) error {
+	b, ok := GetBacklog(in.Peer)
+	if !ok {
+		return fmt.Errorf("%w: %s is not a watch mode peer", ErrPeerNotFound, in.Peer)
+	}
+
+	log.Info(fmt.Sprintf("peer %s start watching from revision %d", in.Peer, in.Revision))
+	b.watch()
+	defer b.unwatch()
+	revision := in.Revision
+	for {
+		events, changed := b.Since(revision)
+		if len(events) > 0 {
+			for _, el := range pageEvents(&v1sync.EventList{Events: events}, maxSize) {
+				if err := send(el); err != nil {
+					log.Error(fmt.Sprintf("send events to peer %s failed", in.Peer), err)
+					return err
+				}
+			}
+			revision = eventRevision(events[len(events)-1])
+			b.ack(revision)
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			log.Info(fmt.Sprintf("peer %s stop watching at revision %d", in.Peer, revision))
+			return nil
+		}
+	}
+}
+
+// subscribe watches the events of the peer and persists them, it reconnects
+// from the last persisted revision when the stream is broken
+func subscribe(ctx context.Context, peer *Peer) {
+	name := config.GetConfig().Sync.Name
+	if len(name) == 0 {
+		log.Error(fmt.Sprintf("can not watch peer %s", peer.Name), ErrSyncerNameEmpty)
+		return
+	}
+
+	var revision int64
+	for {
+		revision = watch(ctx, name, peer, revision)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+	}
+}
+
+func watch(ctx context.Context, name string, peer *Peer, revision int64) int64 {
+	opts := peer.callOptions(ctx)
+	ctx = peer.withToken(ctx)
+	stream, err := client.NewSet(peer.conn).EventServiceClient.Watch(ctx, &v1sync.WatchRequest{
+		Peer:     name,
+		Revision: revision,
+	}, opts...)
+	if err != nil {
+		peer.renegotiate(err)
+		log.Error(fmt.Sprintf("watch peer %s failed", peer.Name), err)
+		return revision
+	}
+
+	for {
+		el, err := stream.Recv()
+		if err == io.EOF {
+			log.Info(fmt.Sprintf("watch stream of peer %s is closed", peer.Name))
+			return revision
+		}
+		if err != nil {
+			log.Error(fmt.Sprintf("receive events from peer %s failed", peer.Name), err)
+			return revision
+		}
+
+		var ok bool
+		revision, ok = persist(ctx, el, revision)
+		if !ok {
+			return revision
+		}
+	}
+}
+
+// persist returns the revision of the last persisted event, false if any
+// event persisted failed, then the events after the revision will be re-watched
+func persist(ctx context.Context, el *v1sync.EventList, revision int64) (int64, bool) {
+	results := Manager().Persist(ctx, el)
+	codes := make(map[string]int32, len(results))
+	for _, r := range results {
+		codes[r.EventID] = r.Status
+	}
+
+	for _, event := range el.Events {
+		if codes[event.Id] == resource.Fail {
+			log.Warn(fmt.Sprintf("persist event %s failed, re-watch from revision %d", event.Flag(), revision))
+			return revision, false
+		}
+		revision = eventRevision(event)
+	}
+	return revision, true
+}
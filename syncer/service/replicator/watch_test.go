@@ -0,0 +1,132 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replicator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBacklog(t *testing.T) {
+	b := newBacklog("edge", 2)
+	start := b.Append()
+	b.Append(&v1sync.Event{Id: "2", Timestamp: 2}, &v1sync.Event{Id: "1", Timestamp: 1})
+
+	events, changed := b.Since(start)
+	if assert.Equal(t, 2, len(events)) {
+		assert.Equal(t, "2", events[0].Id)
+		assert.Equal(t, "1", events[1].Id)
+		assert.Equal(t, start+1, eventRevision(events[0]))
+		assert.Equal(t, start+2, eventRevision(events[1]))
+	}
+
+	t.Run("older timestamp event should be appended after the cursor", func(t *testing.T) {
+		last := b.Append(&v1sync.Event{Id: "0", Timestamp: 0})
+		select {
+		case <-changed:
+		default:
+			assert.Fail(t, "changed should be closed after appended")
+		}
+
+		events, _ = b.Since(start + 2)
+		if assert.Equal(t, 1, len(events)) {
+			assert.Equal(t, "0", events[0].Id)
+			assert.Equal(t, last, eventRevision(events[0]))
+		}
+	})
+
+	t.Run("exceed the size should drop the oldest", func(t *testing.T) {
+		events, _ = b.Since(start)
+		if assert.Equal(t, 2, len(events)) {
+			assert.Equal(t, "1", events[0].Id)
+			assert.Equal(t, "0", events[1].Id)
+		}
+	})
+}
+
+func TestReplicate(t *testing.T) {
+	b := newBacklog("replicate", backlogSize)
+	r := NewWatchManager("replicate", b)
+	el := &v1sync.EventList{Events: []*v1sync.Event{{Id: "1", Timestamp: 1}}}
+
+	t.Run("no watcher should fail", func(t *testing.T) {
+		res, err := r.Replicate(context.TODO(), el)
+		assert.NoError(t, err)
+		assert.Equal(t, resource.Fail, res.Results["1"].Code)
+		events, _ := b.Since(0)
+		assert.Equal(t, 0, len(events))
+	})
+
+	t.Run("not delivered should fail", func(t *testing.T) {
+		b.watch()
+		defer b.unwatch()
+		ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+		res, err := r.Replicate(ctx, el)
+		assert.NoError(t, err)
+		assert.Equal(t, resource.Fail, res.Results["1"].Code)
+	})
+
+	t.Run("delivered should succeed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		go func() {
+			_ = ServeWatch(ctx, &v1sync.WatchRequest{Peer: "replicate"}, func(el *v1sync.EventList) error {
+				return nil
+			})
+		}()
+		assert.Eventually(t, b.Watching, time.Second, 10*time.Millisecond)
+
+		res, err := r.Replicate(context.TODO(), &v1sync.EventList{Events: []*v1sync.Event{{Id: "2", Timestamp: 2}}})
+		assert.NoError(t, err)
+		assert.Equal(t, resource.Success, res.Results["2"].Code)
+	})
+}
+
+func TestServeWatch(t *testing.T) {
+	b := newBacklog("watcher", backlogSize)
+	start := b.Append()
+	b.Append(&v1sync.Event{Id: "1", Timestamp: 1}, &v1sync.Event{Id: "2", Timestamp: 2})
+
+	t.Run("not watch mode peer should return error", func(t *testing.T) {
+		err := ServeWatch(context.TODO(), &v1sync.WatchRequest{Peer: "not exist"}, nil)
+		assert.ErrorIs(t, err, ErrPeerNotFound)
+	})
+
+	t.Run("watch from revision should send the newer events", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		received := make([]*v1sync.Event, 0)
+		err := ServeWatch(ctx, &v1sync.WatchRequest{Peer: "watcher", Revision: start + 1}, func(el *v1sync.EventList) error {
+			received = append(received, el.Events...)
+			cancel()
+			return nil
+		})
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(received)) {
+			assert.Equal(t, "2", received[0].Id)
+			assert.Equal(t, start+2, eventRevision(received[0]))
+		}
+		assert.True(t, b.WaitDelivered(context.TODO(), start+2, time.Millisecond))
+	})
+}
@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	servicetask "github.com/apache/servicecomb-service-center/eventbase/service/task"
+	carisync "github.com/go-chassis/cari/sync"
+)
+
+const (
+	// PeerStatusPending means the task is waiting to be sent to the peer
+	PeerStatusPending = "pending"
+	// PeerStatusSent means the task is sent and waiting for the result of the peer
+	PeerStatusSent = "sent"
+	// PeerStatusFailed means the task failed to replicate to the peer, it will be resent
+	PeerStatusFailed = "failed"
+	// PeerStatusDone means the task is replicated to the peer
+	PeerStatusDone = "done"
+	// PeerStatusSkipped means the task is filtered out for the peer
+	PeerStatusSkipped = "skipped"
+)
+
+var (
+	ErrManagerNotRunning = errors.New("task manager is not running on this node")
+	ErrTaskNotFound      = errors.New("task not found")
+	ErrTaskNotHandled    = errors.New("task is not handled yet")
+
+	runningLock sync.RWMutex
+	runningCtx  context.Context
+	running     *manager
+)
+
+// PeerState is the replication state of a task for a peer
+type PeerState struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+func setRunning(ctx context.Context, m *manager) {
+	runningLock.Lock()
+	defer runningLock.Unlock()
+	runningCtx, running = ctx, m
+}
+
+// getRunning returns the task manager running on this node, it only runs
+// on the node which holds the distributed lock
+func getRunning() (*manager, bool) {
+	runningLock.RLock()
+	defer runningLock.RUnlock()
+	if running == nil || runningCtx.Err() != nil {
+		return nil, false
+	}
+	return running, true
+}
+
+// PeerStates returns the replication states of the task for each peer,
+// false if the task is not handled by the task manager of this node
+func PeerStates(id string) (map[string]*PeerState, bool) {
+	m, ok := getRunning()
+	if !ok {
+		return nil, false
+	}
+	v, ok := m.cache.Load(id)
+	if !ok {
+		return nil, false
+	}
+	return v.(*taskState).peerStates(m.eventSenders), true
+}
+
+// Retry resends the task to the peers which are not done
+func Retry(id string) error {
+	m, ok := getRunning()
+	if !ok {
+		return ErrManagerNotRunning
+	}
+	v, ok := m.cache.Load(id)
+	if !ok {
+		return ErrTaskNotHandled
+	}
+	v.(*taskState).retryAll()
+	return nil
+}
+
+// Get returns the task of the id
+func Get(ctx context.Context, id string) (*carisync.Task, error) {
+	t, err := servicetask.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTaskNotFound
+	}
+	return t, nil
+}
+
+// Discard deletes the task, then it will not be replicated to any peer
+func Discard(ctx context.Context, id string) error {
+	t, err := Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = servicetask.Delete(ctx, t)
+	if err != nil {
+		return err
+	}
+	if m, ok := getRunning(); ok {
+		m.cache.Delete(id)
+	}
+	return nil
+}
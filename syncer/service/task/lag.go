@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	carisync "github.com/go-chassis/cari/sync"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/syncer/config"
+	"github.com/apache/servicecomb-service-center/syncer/metrics"
+)
+
+// reportPending reports the tasks pending to replicate to each peer, and raises the
+// lag alarm if the oldest pending task of any peer exceeds the threshold
+func (m *manager) reportPending(tasks []*carisync.Task) {
+	pending := make(map[metrics.PendingKey]int64)
+	oldest := make(map[string]int64)
+	for _, t := range tasks {
+		var ts *taskState
+		if v, ok := m.cache.Load(t.ID); ok {
+			ts = v.(*taskState)
+		}
+		for peer := range m.eventSenders {
+			if ts != nil && ts.isDone(peer) {
+				continue
+			}
+			pending[metrics.PendingKey{Peer: peer, Type: t.ResourceType, Action: t.Action}]++
+			if o, ok := oldest[peer]; !ok || t.Timestamp < o {
+				oldest[peer] = t.Timestamp
+			}
+		}
+	}
+
+	now := time.Now()
+	ages := make(map[string]time.Duration, len(oldest))
+	for peer, timestamp := range oldest {
+		ages[peer] = now.Sub(time.Unix(0, timestamp))
+	}
+	metrics.PeerPendingTaskSet(pending)
+	metrics.OldestPendingTaskSet(ages)
+	m.checkLag(ages)
+}
+
+// checkLag raises the alarm when the lagging peers changed, and clears it if no peer lags
+func (m *manager) checkLag(ages map[string]time.Duration) {
+	threshold := config.GetConfig().Sync.GetLagThreshold()
+	lagging := make([]string, 0, len(ages))
+	if threshold > 0 {
+		for peer, age := range ages {
+			if age > threshold {
+				lagging = append(lagging, peer)
+			}
+		}
+	}
+	sort.Strings(lagging)
+
+	peers := strings.Join(lagging, ",")
+	if peers == m.laggingPeers {
+		return
+	}
+	m.laggingPeers = peers
+
+	if len(lagging) == 0 {
+		log.Info("replication lag of all the peers recovered")
+		if err := alarm.Clear(alarm.IDSyncLag); err != nil {
+			log.Error("clear sync lag alarm failed", err)
+		}
+		return
+	}
+	log.Warn(fmt.Sprintf("replication lag of peers %s exceeds %s", peers, threshold))
+	err := alarm.Raise(alarm.IDSyncLag,
+		alarm.FieldString("peers", peers),
+		alarm.AdditionalContext("tasks pending to replicate to peers %s exceed %s", peers, threshold))
+	if err != nil {
+		log.Error("raise sync lag alarm failed", err)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-chassis/cari/sync"
+	"github.com/stretchr/testify/assert"
+
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/config"
+	"github.com/apache/servicecomb-service-center/syncer/service/event"
+	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
+)
+
+func TestReportPending(t *testing.T) {
+	origin := config.GetConfig()
+	defer config.SetConfig(origin)
+	config.SetConfig(config.Config{Sync: &config.Sync{LagThreshold: "1m"}})
+
+	m := NewManager(ManagerOperator(&mockOperator{}),
+		EventSender("dc1", &mockSender{events: make(map[string]*event.Event), receive: make(chan struct{}, 10)}),
+		EventSender("dc2", &mockSender{events: make(map[string]*event.Event), receive: make(chan struct{}, 10)}),
+	).(*manager)
+
+	old := &sync.Task{ID: "xxx1", ResourceType: "demo", Action: "create",
+		Timestamp: time.Now().Add(-2 * time.Minute).UnixNano()}
+	tasks := []*sync.Task{old}
+
+	m.reportPending(tasks)
+	assert.Equal(t, "dc1,dc2", m.laggingPeers)
+
+	m.handleTasks(tasks)
+	m.handleResult(&event.Result{ID: "xxx1", Peer: "dc2", Data: &v1sync.Result{Code: resource.Success}})
+	m.reportPending(tasks)
+	assert.Equal(t, "dc1", m.laggingPeers)
+
+	m.reportPending(nil)
+	assert.Equal(t, "", m.laggingPeers)
+
+	t.Run("disabled lag alarm", func(t *testing.T) {
+		config.SetConfig(config.Config{Sync: &config.Sync{LagThreshold: "0"}})
+		m.reportPending(tasks)
+		assert.Equal(t, "", m.laggingPeers)
+	})
+}
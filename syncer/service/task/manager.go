@@ -30,6 +30,8 @@ import (
 	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
 	"github.com/apache/servicecomb-service-center/syncer/metrics"
 	"github.com/apache/servicecomb-service-center/syncer/service/event"
+	"github.com/apache/servicecomb-service-center/syncer/service/filter"
+	"github.com/apache/servicecomb-service-center/syncer/service/relay"
 	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
 
 	carisync "github.com/go-chassis/cari/sync"
@@ -57,6 +59,7 @@ func work() {
 			m := NewManager()
 			m.LoadAndHandleTask(ctx)
 			m.UpdateResultTask(ctx)
+			setRunning(ctx, m.(*manager))
 		},
 	}
 	dl.LockDo()
@@ -71,15 +74,18 @@ type Manager interface {
 type ManagerOption func(*managerOptions)
 
 type managerOptions struct {
-	internal    time.Duration
-	operator    Operator
-	eventSender event.Sender
+	internal     time.Duration
+	operator     Operator
+	eventSenders map[string]event.Sender
 }
 
 func toManagerOptions(os ...ManagerOption) *managerOptions {
 	mo := new(managerOptions)
 	mo.internal = defaultInternal
-	mo.eventSender = event.GetManager()
+	mo.eventSenders = make(map[string]event.Sender)
+	for peer, em := range event.Managers() {
+		mo.eventSenders[peer] = em
+	}
 
 	for _, o := range os {
 		o(mo)
@@ -93,9 +99,10 @@ func ManagerInternal(i time.Duration) ManagerOption {
 	}
 }
 
-func EventSender(e event.Sender) ManagerOption {
+// EventSender set the event sender of the peer, tasks are sent to all the peers
+func EventSender(peer string, e event.Sender) ManagerOption {
 	return func(options *managerOptions) {
-		options.eventSender = e
+		options.eventSenders[peer] = e
 	}
 }
 
@@ -118,7 +125,7 @@ func NewManager(os ...ManagerOption) Manager {
 
 	m.internal = mo.internal
 	m.operator = mo.operator
-	m.eventSender = mo.eventSender
+	m.eventSenders = mo.eventSenders
 	return m
 }
 
@@ -130,10 +137,144 @@ type manager struct {
 
 	isClosing bool
 	result    chan *event.Result
-	cache     sync.Map
+	// cache stores the taskState of the handling tasks, key is the task id
+	cache sync.Map
+
+	operator     Operator
+	eventSenders map[string]event.Sender
+	// laggingPeers are the peers joined by comma which the lag alarm raised for
+	laggingPeers string
+}
+
+// taskState records the replication state of a task for each peer
+type taskState struct {
+	lock sync.Mutex
+	task *carisync.Task
+	// sent records the peers that the task is sent to and waiting for result
+	sent map[string]struct{}
+	// done records the peers that the task is replicated to
+	done map[string]struct{}
+	// skipped records the peers that the task is filtered out, they are also done
+	skipped map[string]struct{}
+	// failed records the last error of the peers that the task failed to replicate to
+	failed map[string]string
+}
+
+func newTaskState(t *carisync.Task) *taskState {
+	return &taskState{
+		task:    t,
+		sent:    make(map[string]struct{}),
+		done:    make(map[string]struct{}),
+		skipped: make(map[string]struct{}),
+		failed:  make(map[string]string),
+	}
+}
+
+// toSendPeers marks and returns the peers that the task need to be sent to
+func (ts *taskState) toSendPeers(peers map[string]event.Sender) []string {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	toSend := make([]string, 0, len(peers))
+	for peer := range peers {
+		if _, ok := ts.sent[peer]; ok {
+			continue
+		}
+		if _, ok := ts.done[peer]; ok {
+			continue
+		}
+		ts.sent[peer] = struct{}{}
+		toSend = append(toSend, peer)
+	}
+	sort.Strings(toSend)
+	return toSend
+}
 
-	operator    Operator
-	eventSender event.Sender
+// isDone returns true if the task is replicated to the peer or skipped
+func (ts *taskState) isDone(peer string) bool {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	_, ok := ts.done[peer]
+	return ok
+}
+
+// hasPeerToSend returns true if there is any peer that the task is neither sent to nor replicated to
+func (ts *taskState) hasPeerToSend(peers map[string]event.Sender) bool {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	for peer := range peers {
+		_, sent := ts.sent[peer]
+		_, done := ts.done[peer]
+		if !sent && !done {
+			return true
+		}
+	}
+	return false
+}
+
+// retry clears the sent mark of the peer, then the task will be resent to the peer
+func (ts *taskState) retry(peer string) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	delete(ts.sent, peer)
+}
+
+// retryAll clears the sent marks of all the peers which are not done
+func (ts *taskState) retryAll() {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	ts.sent = make(map[string]struct{})
+}
+
+// fail records the error of the peer and clears its sent mark
+func (ts *taskState) fail(peer string, message string) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	delete(ts.sent, peer)
+	ts.failed[peer] = message
+}
+
+// skip marks the task filtered out for the peer
+func (ts *taskState) skip(peer string) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	ts.skipped[peer] = struct{}{}
+}
+
+// peerStates returns the replication states of the task for the peers
+func (ts *taskState) peerStates(peers map[string]event.Sender) map[string]*PeerState {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	states := make(map[string]*PeerState, len(peers))
+	for peer := range peers {
+		state := &PeerState{Status: PeerStatusPending, Message: ts.failed[peer]}
+		if _, ok := ts.sent[peer]; ok {
+			state.Status = PeerStatusSent
+		} else if len(state.Message) > 0 {
+			state.Status = PeerStatusFailed
+		}
+		if _, ok := ts.done[peer]; ok {
+			state.Status = PeerStatusDone
+		}
+		if _, ok := ts.skipped[peer]; ok {
+			state.Status = PeerStatusSkipped
+		}
+		states[peer] = state
+	}
+	return states
+}
+
+// finish marks the task replicated to the peer, returns true if all the peers finished
+func (ts *taskState) finish(peer string, peers map[string]event.Sender) bool {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	delete(ts.sent, peer)
+	ts.done[peer] = struct{}{}
+	for p := range peers {
+		if _, ok := ts.done[p]; !ok {
+			return false
+		}
+	}
+	return true
 }
 
 // Operator define task operator, to list tasks and delete task
@@ -188,12 +329,11 @@ func (m *manager) ListTasks(ctx context.Context) ([]*carisync.Task, error) {
 	allTaskIDs := make(map[string]struct{}, len(tasks))
 	for _, t := range tasks {
 		allTaskIDs[t.ID] = struct{}{}
-		_, ok := m.cache.Load(t.ID)
-		if ok {
+		v, ok := m.cache.Load(t.ID)
+		if ok && !v.(*taskState).hasPeerToSend(m.eventSenders) {
 			skipTaskIDs = append(skipTaskIDs, t.ID)
 			continue
 		}
-		m.cache.Store(t.ID, t)
 		noHandleTasks = append(noHandleTasks, t)
 	}
 	m.cache.Range(func(key, value any) bool {
@@ -202,6 +342,7 @@ func (m *manager) ListTasks(ctx context.Context) ([]*carisync.Task, error) {
 		}
 		return true
 	})
+	m.reportPending(tasks)
 
 	log.Info(fmt.Sprintf("load task raw count %d, to handle count %d",
 		len(tasks), len(noHandleTasks)))
@@ -251,53 +392,92 @@ func (m *manager) closeUpdateTasks() {
 }
 
 func (m *manager) handleResult(res *event.Result) {
-	if res.Error != nil || res.Data.Code == resource.Fail {
-		log.Error(fmt.Sprintf("get task %s result, return error", res.ID), res.Error)
+	if res.Error != nil || res.Data == nil || res.Data.Code == resource.Fail {
+		// resend all the handling tasks to the peer in order, other peers are not affected
+		log.Error(fmt.Sprintf("get task %s result of peer %s, return error", res.ID, res.Peer), res.Error)
 		m.cache.Range(func(key, value interface{}) bool {
-			m.cache.Delete(key)
+			value.(*taskState).retry(res.Peer)
 			return true
 		})
+		if v, ok := m.cache.Load(res.ID); ok {
+			v.(*taskState).fail(res.Peer, resultMessage(res))
+		}
 		return
 	}
 
-	if res.Data == nil {
-		log.Info("result data is empty")
+	log.Info(fmt.Sprintf("key: %s, peer: %s, result: %v", res.ID, res.Peer, res.Data))
+
+	v, ok := m.cache.Load(res.ID)
+	if !ok {
 		return
 	}
 
-	log.Info(fmt.Sprintf("key: %s,result: %v", res.ID, res.Data))
+	m.finish(v.(*taskState), res.Peer)
+}
 
-	t, ok := m.cache.Load(res.ID)
-	if !ok {
+// finish marks the task done for the peer, the task is deleted if all the peers are done
+func (m *manager) finish(ts *taskState, peer string) {
+	if !ts.finish(peer, m.eventSenders) {
 		return
 	}
-
-	code := res.Data.Code
-	if code != resource.Fail {
-		tk := t.(*carisync.Task)
-		err := m.operator.DeleteTask(context.TODO(), tk)
-		if err != nil {
-			log.Error("delete task failed", err)
-		}
+	err := m.operator.DeleteTask(context.TODO(), ts.task)
+	if err != nil {
+		log.Error("delete task failed", err)
+		return
 	}
+	m.cache.Delete(ts.task.ID)
 }
 
 func (m *manager) handleTasks(sts syncTasks) {
 	sort.Sort(sts)
 
+	ctx := context.Background()
 	for _, st := range sts {
-		m.eventSender.Send(toEvent(st, m.result))
+		v, _ := m.cache.LoadOrStore(st.ID, newTaskState(st))
+		ts := v.(*taskState)
+		r := filter.NewResource(st)
+		for _, peer := range ts.toSendPeers(m.eventSenders) {
+			if relay.Visited(st.Opts, peer) {
+				// the change comes from the peer, do not send it back
+				log.Info(fmt.Sprintf("task %s has been visited by peer %s, skip it", st.ID, peer))
+				ts.skip(peer)
+				m.finish(ts, peer)
+				continue
+			}
+			if !filter.Allow(ctx, peer, r) {
+				// the task is skipped for the peer, rather than pending forever
+				log.Info(fmt.Sprintf("task %s is filtered out for peer %s, skip it", st.ID, peer))
+				ts.skip(peer)
+				m.finish(ts, peer)
+				continue
+			}
+			m.eventSenders[peer].Send(toEvent(st, m.result))
+		}
 	}
 }
 
-func toEvent(task *carisync.Task, result chan<- *event.Result) *event.Event {
-	ops := task.Opts
-	if len(ops) == 0 {
-		ops = make(map[string]string, 2)
+func resultMessage(res *event.Result) string {
+	if res.Error != nil {
+		return res.Error.Error()
+	}
+	if res.Data == nil {
+		return "result is empty"
+	}
+	if len(res.Data.Message) == 0 {
+		return "replicate failed"
 	}
+	return res.Data.Message
+}
 
+func toEvent(task *carisync.Task, result chan<- *event.Result) *event.Event {
+	// the task is sent to multiple peers, so copy the opts instead of modifying it
+	ops := make(map[string]string, len(task.Opts)+2)
+	for k, v := range task.Opts {
+		ops[k] = v
+	}
 	ops[string(util.CtxDomain)] = task.Domain
 	ops[string(util.CtxProject)] = task.Project
+	relay.Stamp(ops)
 	return &event.Event{
 		Event: &v1sync.Event{
 			Id:        task.ID,
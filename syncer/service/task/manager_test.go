@@ -21,7 +21,11 @@ import (
 	"context"
 	"testing"
 
+	v1sync "github.com/apache/servicecomb-service-center/syncer/api/v1"
+	"github.com/apache/servicecomb-service-center/syncer/config"
 	"github.com/apache/servicecomb-service-center/syncer/service/event"
+	"github.com/apache/servicecomb-service-center/syncer/service/relay"
+	"github.com/apache/servicecomb-service-center/syncer/service/replicator/resource"
 
 	"github.com/go-chassis/cari/sync"
 	"github.com/stretchr/testify/assert"
@@ -47,7 +51,7 @@ func TestNewManager(t *testing.T) {
 			},
 		}),
 		ManagerInternal(defaultInternal),
-		EventSender(fs))
+		EventSender("dc", fs))
 
 	m.LoadAndHandleTask(ctx)
 	m.UpdateResultTask(ctx)
@@ -55,6 +59,160 @@ func TestNewManager(t *testing.T) {
 	assert.Equal(t, 1, len(fs.events))
 }
 
+func TestManagerMultiPeers(t *testing.T) {
+	peers := map[string]event.Sender{
+		"dc1": &mockSender{events: make(map[string]*event.Event), receive: make(chan struct{}, 10)},
+		"dc2": &mockSender{events: make(map[string]*event.Event), receive: make(chan struct{}, 10)},
+	}
+	op := &mockOperator{
+		tasks: map[string]*sync.Task{
+			"xxx1": {ID: "xxx1", ResourceType: "demo", Action: "create", Status: "pending"},
+		},
+	}
+	m := NewManager(ManagerOperator(op),
+		EventSender("dc1", peers["dc1"]),
+		EventSender("dc2", peers["dc2"])).(*manager)
+
+	ts, err := op.ListTasks(context.TODO())
+	assert.NoError(t, err)
+	m.handleTasks(ts)
+	m.handleTasks(ts)
+	assert.Equal(t, 1, len(peers["dc1"].(*mockSender).events))
+	assert.Equal(t, 1, len(peers["dc2"].(*mockSender).events))
+
+	t.Run("one peer failed should only retry the peer", func(t *testing.T) {
+		m.handleResult(&event.Result{ID: "xxx1", Peer: "dc1", Error: assert.AnError})
+		m.handleResult(&event.Result{ID: "xxx1", Peer: "dc2", Data: &v1sync.Result{Code: resource.Success}})
+		assert.Equal(t, 1, len(op.tasks))
+
+		v, ok := m.cache.Load("xxx1")
+		if assert.True(t, ok) {
+			assert.Equal(t, []string{"dc1"}, v.(*taskState).toSendPeers(m.eventSenders))
+		}
+	})
+
+	t.Run("all peers succeeded should delete the task", func(t *testing.T) {
+		m.handleResult(&event.Result{ID: "xxx1", Peer: "dc1", Data: &v1sync.Result{Code: resource.Success}})
+		assert.Equal(t, 0, len(op.tasks))
+		_, ok := m.cache.Load("xxx1")
+		assert.False(t, ok)
+	})
+}
+
+func TestManagerFilter(t *testing.T) {
+	origin := config.GetConfig()
+	defer config.SetConfig(origin)
+	config.SetConfig(config.Config{Sync: &config.Sync{Peers: []*config.Peer{
+		{Name: "dc1"},
+		{Name: "dc2", Exclude: []*config.Rule{{ResourceTypes: []string{"demo"}}}},
+	}}})
+
+	peers := map[string]event.Sender{
+		"dc1": &mockSender{events: make(map[string]*event.Event), receive: make(chan struct{}, 10)},
+		"dc2": &mockSender{events: make(map[string]*event.Event), receive: make(chan struct{}, 10)},
+	}
+	op := &mockOperator{
+		tasks: map[string]*sync.Task{
+			"xxx1": {ID: "xxx1", ResourceType: "demo", Action: "create", Status: "pending"},
+		},
+	}
+	m := NewManager(ManagerOperator(op),
+		EventSender("dc1", peers["dc1"]),
+		EventSender("dc2", peers["dc2"])).(*manager)
+
+	ts, err := op.ListTasks(context.TODO())
+	assert.NoError(t, err)
+	m.handleTasks(ts)
+	assert.Equal(t, 1, len(peers["dc1"].(*mockSender).events))
+	assert.Equal(t, 0, len(peers["dc2"].(*mockSender).events))
+
+	m.handleResult(&event.Result{ID: "xxx1", Peer: "dc1", Data: &v1sync.Result{Code: resource.Success}})
+	assert.Equal(t, 0, len(op.tasks))
+	_, ok := m.cache.Load("xxx1")
+	assert.False(t, ok)
+}
+
+func TestManagerRelay(t *testing.T) {
+	origin := config.GetConfig()
+	defer config.SetConfig(origin)
+	config.SetConfig(config.Config{Sync: &config.Sync{Name: "hub", Relay: true, Peers: []*config.Peer{
+		{Name: "dc1"},
+		{Name: "dc2"},
+	}}})
+
+	peers := map[string]event.Sender{
+		"dc1": &mockSender{events: make(map[string]*event.Event), receive: make(chan struct{}, 10)},
+		"dc2": &mockSender{events: make(map[string]*event.Event), receive: make(chan struct{}, 10)},
+	}
+	op := &mockOperator{
+		tasks: map[string]*sync.Task{
+			"xxx1": {ID: "xxx1", ResourceType: "demo", Action: "create", Status: "pending",
+				Opts: map[string]string{relay.OptOrigin: "dc1", relay.OptVisited: "dc1"}},
+		},
+	}
+	m := NewManager(ManagerOperator(op),
+		EventSender("dc1", peers["dc1"]),
+		EventSender("dc2", peers["dc2"])).(*manager)
+
+	ts, err := op.ListTasks(context.TODO())
+	assert.NoError(t, err)
+	m.handleTasks(ts)
+	assert.Equal(t, 0, len(peers["dc1"].(*mockSender).events), "should not send back to the origin")
+	e, ok := peers["dc2"].(*mockSender).events["xxx1"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "dc1", e.Opts[relay.OptOrigin])
+		assert.Equal(t, "dc1,hub", e.Opts[relay.OptVisited])
+	}
+	assert.Equal(t, "dc1", op.tasks["xxx1"].Opts[relay.OptVisited], "task opts should not be modified")
+}
+
+func TestPeerStates(t *testing.T) {
+	peers := map[string]event.Sender{
+		"dc1": &mockSender{events: make(map[string]*event.Event), receive: make(chan struct{}, 10)},
+		"dc2": &mockSender{events: make(map[string]*event.Event), receive: make(chan struct{}, 10)},
+	}
+	op := &mockOperator{
+		tasks: map[string]*sync.Task{
+			"xxx1": {ID: "xxx1", ResourceType: "demo", Action: "create", Status: "pending"},
+		},
+	}
+	m := NewManager(ManagerOperator(op),
+		EventSender("dc1", peers["dc1"]),
+		EventSender("dc2", peers["dc2"])).(*manager)
+
+	_, ok := PeerStates("xxx1")
+	assert.False(t, ok)
+	assert.ErrorIs(t, Retry("xxx1"), ErrManagerNotRunning)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	setRunning(ctx, m)
+	assert.ErrorIs(t, Retry("xxx1"), ErrTaskNotHandled)
+
+	ts, err := op.ListTasks(context.TODO())
+	assert.NoError(t, err)
+	m.handleTasks(ts)
+	m.handleResult(&event.Result{ID: "xxx1", Peer: "dc1", Data: &v1sync.Result{Code: resource.Fail, Message: "demo"}})
+	m.handleResult(&event.Result{ID: "xxx1", Peer: "dc2", Data: &v1sync.Result{Code: resource.Success}})
+
+	states, ok := PeerStates("xxx1")
+	if assert.True(t, ok) {
+		assert.Equal(t, &PeerState{Status: PeerStatusFailed, Message: "demo"}, states["dc1"])
+		assert.Equal(t, PeerStatusDone, states["dc2"].Status)
+	}
+
+	m.handleTasks(ts)
+	states, _ = PeerStates("xxx1")
+	assert.Equal(t, PeerStatusSent, states["dc1"].Status)
+	assert.NoError(t, Retry("xxx1"))
+	states, _ = PeerStates("xxx1")
+	assert.Equal(t, PeerStatusFailed, states["dc1"].Status)
+	assert.Equal(t, PeerStatusDone, states["dc2"].Status)
+
+	cancel()
+	_, ok = PeerStates("xxx1")
+	assert.False(t, ok)
+}
+
 type mockOperator struct {
 	tasks map[string]*sync.Task
 }